@@ -0,0 +1,291 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pokt-foundation/portal-api-go/repository"
+	logger "github.com/sirupsen/logrus"
+)
+
+const (
+	HEADER_AUTHORIZATION    = "Authorization"
+	HEADER_API_KEY          = "X-API-Key"
+	HEADER_WWW_AUTHENTICATE = "WWW-Authenticate"
+
+	BEARER_PREFIX = "Bearer "
+	// Only HMAC-SHA256 signed tokens are supported
+	TOKEN_ALGORITHM = "HS256"
+)
+
+var (
+	ErrUnauthenticated = errors.New("Missing or invalid credentials")
+	ErrForbidden       = errors.New("Access to the requested resource is not allowed")
+)
+
+// Principal is the authenticated identity of a request
+type Principal struct {
+	// User ID, as used by Backend.UserApps and the load balancers' UserID
+	User string
+	// Admins are allowed to access all routes
+	Admin bool
+}
+
+// Authenticator returns the principal of a request: ErrUnauthenticated is returned if the request has no valid credentials.
+type Authenticator interface {
+	Authenticate(req *http.Request) (Principal, error)
+}
+
+// Owners provides the ownership of applications and load balancers: it is implemented by the Backend.
+type Owners interface {
+	// Is expected to return the list of applicationIDs owned by the user
+	UserApps(user string) ([]string, error)
+	// LoadBalancer is expected to return ErrLoadBalancerNotFound, possibly wrapped, for unknown endpoints
+	LoadBalancer(endpoint string) (*repository.LoadBalancer, error)
+}
+
+// APIKey is a static key, passed on the X-API-Key header or as a bearer token, which authenticates its holder as the specified principal
+type APIKey struct {
+	Key   string
+	User  string
+	Admin bool
+}
+
+// NewAPIKeyAuthenticator returns an authenticator using static API keys
+func NewAPIKeyAuthenticator(keys []APIKey) (Authenticator, error) {
+	for _, k := range keys {
+		if k.Key == "" {
+			return nil, fmt.Errorf("Missing key on API key of user: %q", k.User)
+		}
+		if k.User == "" && !k.Admin {
+			return nil, fmt.Errorf("API keys need to specify a user, or be admin keys")
+		}
+	}
+	return &apiKeyAuthenticator{keys: keys}, nil
+}
+
+type apiKeyAuthenticator struct {
+	keys []APIKey
+}
+
+func (a *apiKeyAuthenticator) Authenticate(req *http.Request) (Principal, error) {
+	key := req.Header.Get(HEADER_API_KEY)
+	if key == "" {
+		key = bearerToken(req)
+	}
+	if key == "" {
+		return Principal{}, ErrUnauthenticated
+	}
+
+	// All keys are compared in constant time, to avoid leaking information on valid keys
+	var principal *Principal
+	for _, k := range a.keys {
+		if subtle.ConstantTimeCompare([]byte(k.Key), []byte(key)) == 1 {
+			principal = &Principal{User: k.User, Admin: k.Admin}
+		}
+	}
+	if principal == nil {
+		return Principal{}, ErrUnauthenticated
+	}
+	return *principal, nil
+}
+
+// TokenClaims are the claims of a signed bearer token
+type TokenClaims struct {
+	// Subject is the user ID
+	Subject string `json:"sub"`
+	Admin   bool   `json:"admin,omitempty"`
+	// Expiry, as a Unix timestamp: tokens with no expiry are rejected
+	Expiry int64 `json:"exp"`
+}
+
+// NewTokenAuthenticator returns an authenticator using bearer tokens in JWT format, signed with HMAC-SHA256 using the secret.
+func NewTokenAuthenticator(secret []byte) (Authenticator, error) {
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("Missing token signing secret")
+	}
+	return &tokenAuthenticator{secret: secret}, nil
+}
+
+type tokenAuthenticator struct {
+	secret []byte
+}
+
+func (a *tokenAuthenticator) Authenticate(req *http.Request) (Principal, error) {
+	token := bearerToken(req)
+	if token == "" {
+		return Principal{}, ErrUnauthenticated
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Principal{}, fmt.Errorf("%w: invalid token format", ErrUnauthenticated)
+	}
+
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, mac.Sum(nil)) {
+		return Principal{}, fmt.Errorf("%w: invalid token signature", ErrUnauthenticated)
+	}
+
+	var header struct {
+		Algorithm string `json:"alg"`
+	}
+	if err := decodeTokenPart(parts[0], &header); err != nil || header.Algorithm != TOKEN_ALGORITHM {
+		return Principal{}, fmt.Errorf("%w: unsupported token algorithm", ErrUnauthenticated)
+	}
+	var claims TokenClaims
+	if err := decodeTokenPart(parts[1], &claims); err != nil {
+		return Principal{}, fmt.Errorf("%w: invalid token claims: %v", ErrUnauthenticated, err)
+	}
+	if claims.Expiry == 0 || time.Now().Unix() >= claims.Expiry {
+		return Principal{}, fmt.Errorf("%w: token expired", ErrUnauthenticated)
+	}
+	if claims.Subject == "" && !claims.Admin {
+		return Principal{}, fmt.Errorf("%w: missing token subject", ErrUnauthenticated)
+	}
+
+	return Principal{User: claims.Subject, Admin: claims.Admin}, nil
+}
+
+// SignToken returns a bearer token, in JWT format, holding the claims signed with the secret: e.g. for issuing tokens to users.
+func SignToken(claims TokenClaims, secret []byte) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": TOKEN_ALGORITHM, "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+func decodeTokenPart(part string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func bearerToken(req *http.Request) string {
+	authorization := req.Header.Get(HEADER_AUTHORIZATION)
+	if !strings.HasPrefix(authorization, BEARER_PREFIX) {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(authorization, BEARER_PREFIX))
+}
+
+// Authenticators tries each authenticator in order: the principal of the first successful one is returned.
+type Authenticators []Authenticator
+
+func (a Authenticators) Authenticate(req *http.Request) (Principal, error) {
+	err := ErrUnauthenticated
+	for _, authenticator := range a {
+		principal, authErr := authenticator.Authenticate(req)
+		if authErr == nil {
+			return principal, nil
+		}
+		// Keep the most specific error, e.g. an expired token, for logging
+		if authErr != ErrUnauthenticated {
+			err = authErr
+		}
+	}
+	return Principal{}, err
+}
+
+// authorizeSelf allows users to access their own resources, e.g. /v0/relays/users/{self}
+func authorizeSelf(_ Owners, p Principal, user string) error {
+	if p.User != user {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// authorizeApp allows users to access the applications they own
+func authorizeApp(owners Owners, p Principal, app string) error {
+	apps, err := owners.UserApps(p.User)
+	if err != nil {
+		return err
+	}
+	for _, a := range apps {
+		if a == app {
+			return nil
+		}
+	}
+	return ErrForbidden
+}
+
+// authorizeLoadBalancer allows users to access the load balancers they own.
+//	Unknown endpoints are forbidden, as other users' endpoints are, so users cannot tell whether an endpoint exists.
+func authorizeLoadBalancer(owners Owners, p Principal, endpoint string) error {
+	lb, err := owners.LoadBalancer(endpoint)
+	if errors.Is(err, ErrLoadBalancerNotFound) {
+		return ErrForbidden
+	}
+	if err != nil {
+		return err
+	}
+	if lb == nil || lb.UserID != p.User {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// RequireAuthorization returns a handler which only passes authorized requests to the next handler, e.g. the one returned by GetHttpServer.
+//	Admins are allowed access to all routes. Other users are only allowed access to the routes with an authorization rule,
+//	e.g. their own applications, and only if the rule allows it. Public routes, e.g. the OpenAPI specification, need no credentials.
+func RequireAuthorization(next func(w http.ResponseWriter, req *http.Request), authenticator Authenticator, owners Owners, l *logger.Logger) func(w http.ResponseWriter, req *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log := l.WithFields(logger.Fields{"path": req.URL.Path})
+
+		r, id := matchRoute(req.URL.Path)
+		// Unknown paths are handled by the next handler, as they do not reveal any data
+		if r == nil || r.public {
+			next(w, req)
+			return
+		}
+
+		principal, err := authenticator.Authenticate(req)
+		if err != nil {
+			log.WithFields(logger.Fields{"error": err}).Warn("Unauthenticated request")
+			w.Header().Set(HEADER_WWW_AUTHENTICATE, "Bearer")
+			WriteError(l, w, http.StatusUnauthorized, ERROR_CODE_UNAUTHENTICATED, ErrUnauthenticated.Error())
+			return
+		}
+		log = log.WithFields(logger.Fields{"user": principal.User, "admin": principal.Admin})
+
+		if !principal.Admin {
+			err := ErrForbidden
+			if r.authorize != nil {
+				err = r.authorize(owners, principal, id)
+			}
+			switch {
+			case errors.Is(err, ErrForbidden):
+				log.Warn("Unauthorized request")
+				WriteError(l, w, http.StatusForbidden, ERROR_CODE_FORBIDDEN, err.Error())
+				return
+			case err != nil:
+				log.WithFields(logger.Fields{"error": err}).Warn("Error authorizing request")
+				WriteError(l, w, http.StatusInternalServerError, ERROR_CODE_INTERNAL_ERROR, "Internal server error")
+				return
+			}
+		}
+
+		next(w, req)
+	}
+}
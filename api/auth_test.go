@@ -0,0 +1,274 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pokt-foundation/portal-api-go/repository"
+	logger "github.com/sirupsen/logrus"
+)
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	authenticator, err := NewAPIKeyAuthenticator([]APIKey{
+		{Key: "key1", User: "user1"},
+		{Key: "admin-key", Admin: true},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		name          string
+		headers       map[string]string
+		expected      Principal
+		expectedError error
+	}{
+		{
+			name:     "API key header",
+			headers:  map[string]string{HEADER_API_KEY: "key1"},
+			expected: Principal{User: "user1"},
+		},
+		{
+			name:     "API key as a bearer token",
+			headers:  map[string]string{HEADER_AUTHORIZATION: "Bearer admin-key"},
+			expected: Principal{Admin: true},
+		},
+		{
+			name:          "Unknown API key",
+			headers:       map[string]string{HEADER_API_KEY: "key2"},
+			expectedError: ErrUnauthenticated,
+		},
+		{
+			name:          "Missing API key",
+			expectedError: ErrUnauthenticated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://relay-meter.pokt.network/v0/relays/apps", nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+
+			got, err := authenticator.Authenticate(req)
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("Expected error: %v, got: %v", tc.expectedError, err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := NewAPIKeyAuthenticator([]APIKey{{Key: "key1"}}); err == nil {
+		t.Errorf("Expected an error for an API key with no user")
+	}
+}
+
+func TestTokenAuthenticator(t *testing.T) {
+	secret := []byte("secret")
+	authenticator, err := NewTokenAuthenticator(secret)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	sign := func(claims TokenClaims, secret []byte) string {
+		token, err := SignToken(claims, secret)
+		if err != nil {
+			t.Fatalf("Unexpected error signing token: %v", err)
+		}
+		return token
+	}
+	expiry := time.Now().Add(time.Hour).Unix()
+
+	testCases := []struct {
+		name          string
+		token         string
+		expected      Principal
+		expectedError error
+	}{
+		{
+			name:     "Valid user token",
+			token:    sign(TokenClaims{Subject: "user1", Expiry: expiry}, secret),
+			expected: Principal{User: "user1"},
+		},
+		{
+			name:     "Valid admin token",
+			token:    sign(TokenClaims{Subject: "admin1", Admin: true, Expiry: expiry}, secret),
+			expected: Principal{User: "admin1", Admin: true},
+		},
+		{
+			name:          "Token signed with a different secret",
+			token:         sign(TokenClaims{Subject: "user1", Expiry: expiry}, []byte("other")),
+			expectedError: ErrUnauthenticated,
+		},
+		{
+			name:          "Expired token",
+			token:         sign(TokenClaims{Subject: "user1", Expiry: time.Now().Add(-time.Minute).Unix()}, secret),
+			expectedError: ErrUnauthenticated,
+		},
+		{
+			name:          "Token with no expiry",
+			token:         sign(TokenClaims{Subject: "user1"}, secret),
+			expectedError: ErrUnauthenticated,
+		},
+		{
+			name:          "Token with no subject",
+			token:         sign(TokenClaims{Expiry: expiry}, secret),
+			expectedError: ErrUnauthenticated,
+		},
+		{
+			name:          "Malformed token",
+			token:         "not-a-token",
+			expectedError: ErrUnauthenticated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://relay-meter.pokt.network/v0/relays/apps", nil)
+			req.Header.Set(HEADER_AUTHORIZATION, BEARER_PREFIX+tc.token)
+
+			got, err := authenticator.Authenticate(req)
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("Expected error: %v, got: %v", tc.expectedError, err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRequireAuthorization(t *testing.T) {
+	authenticator, err := NewAPIKeyAuthenticator([]APIKey{
+		{Key: "user1-key", User: "user1"},
+		{Key: "admin-key", Admin: true},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	backend := fakeBackend{
+		userApps: map[string][]string{"user1": {"app1"}},
+		loadbalancers: map[string]*repository.LoadBalancer{
+			"lb1": {ID: "lb1", UserID: "user1"},
+			"lb2": {ID: "lb2", UserID: "user2"},
+		},
+	}
+	handler := RequireAuthorization(GetHttpServer(&fakeRelayMeter{}, logger.New()), authenticator, &backend, logger.New())
+	failingBackend := fakeBackend{err: errors.New("connection refused")}
+	failingHandler := RequireAuthorization(GetHttpServer(&fakeRelayMeter{}, logger.New()), authenticator, &failingBackend, logger.New())
+
+	testCases := []struct {
+		name           string
+		path           string
+		apiKey         string
+		backendFails   bool
+		expectedStatus int
+	}{
+		{
+			name:           "Admins can access all routes",
+			path:           "/v0/relays/apps",
+			apiKey:         "admin-key",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Users can access their own usage",
+			path:           "/v0/relays/users/user1",
+			apiKey:         "user1-key",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Users cannot access other users' usage",
+			path:           "/v0/relays/users/user2",
+			apiKey:         "user1-key",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Users can access their own applications",
+			path:           "/v0/relays/apps/app1",
+			apiKey:         "user1-key",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Users cannot access other users' applications",
+			path:           "/v0/relays/apps/app2",
+			apiKey:         "user1-key",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Users can access their own load balancers",
+			path:           "/v0/relays/endpoints/lb1",
+			apiKey:         "user1-key",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Users cannot access other users' load balancers",
+			path:           "/v0/relays/endpoints/lb2",
+			apiKey:         "user1-key",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Users cannot access unknown load balancers",
+			path:           "/v0/relays/endpoints/lb3",
+			apiKey:         "user1-key",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Backend failure getting a load balancer",
+			path:           "/v0/relays/endpoints/lb1",
+			apiKey:         "user1-key",
+			backendFails:   true,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "Users cannot access routes with no authorization rule",
+			path:           "/v0/relays/apps",
+			apiKey:         "user1-key",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Missing credentials",
+			path:           "/v0/relays/apps/app1",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Invalid credentials",
+			path:           "/v0/relays/apps/app1",
+			apiKey:         "unknown-key",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Public routes need no credentials",
+			path:           "/v0/openapi.json",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://relay-meter.pokt.network"+tc.path, nil)
+			if tc.apiKey != "" {
+				req.Header.Set(HEADER_API_KEY, tc.apiKey)
+			}
+			w := httptest.NewRecorder()
+			if tc.backendFails {
+				failingHandler(w, req)
+			} else {
+				handler(w, req)
+			}
+
+			resp := w.Result()
+			if resp.StatusCode != tc.expectedStatus {
+				t.Fatalf("Expected status code: %d, got: %d", tc.expectedStatus, resp.StatusCode)
+			}
+			if tc.expectedStatus == http.StatusUnauthorized && resp.Header.Get(HEADER_WWW_AUTHENTICATE) == "" {
+				t.Errorf("Expected a %s header", HEADER_WWW_AUTHENTICATE)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	HEADER_ETAG              = "ETag"
+	HEADER_LAST_MODIFIED     = "Last-Modified"
+	HEADER_IF_NONE_MATCH     = "If-None-Match"
+	HEADER_IF_MODIFIED_SINCE = "If-Modified-Since"
+	HEADER_VARY              = "Vary"
+)
+
+// cacheValidator identifies the version of the metrics a response is based on, so clients can skip downloading unchanged responses.
+type cacheValidator struct {
+	etag         string
+	lastModified time.Time
+}
+
+// newCacheValidator returns the validator of the response to the request, based on the time the meter's metrics were last updated.
+//	Responses also change at the start of a new day without the metrics being updated, e.g. today's relays:
+//	the start of today is used as the last modified time if the metrics were last updated on a previous day.
+//	Days start in the location of lastUpdated, which the meter keeps in the reporting timezone.
+//	The ETag is weak, as the same response can be compressed or not.
+//	Note: user applications and load balancers are read from the backend on every request, changes to them are only reflected
+//	in the validator once the metrics are updated.
+func newCacheValidator(lastUpdated time.Time, contentType string, req *http.Request) *cacheValidator {
+	now := time.Now().In(lastUpdated.Location())
+	today, _, _ := AdjustTimePeriodIn(now, now, now.Location())
+	lastModified := lastUpdated
+	if today.After(lastModified) {
+		lastModified = today
+	}
+
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d|%s|%s", lastModified.UnixNano(), contentType, req.URL.RequestURI())
+	return &cacheValidator{
+		etag:         fmt.Sprintf("W/%q", fmt.Sprintf("%x", h.Sum64())),
+		lastModified: lastModified,
+	}
+}
+
+// notModified returns true if the client's cached response, as specified by the request's conditional headers, is still valid.
+//	If-Modified-Since is ignored if If-None-Match is set.
+func (c *cacheValidator) notModified(req *http.Request) bool {
+	if ifNoneMatch := req.Header.Get(HEADER_IF_NONE_MATCH); ifNoneMatch != "" {
+		for _, etag := range strings.Split(ifNoneMatch, ",") {
+			etag = strings.TrimSpace(etag)
+			// Weak comparison: the weakness indicator is ignored
+			if etag == "*" || strings.TrimPrefix(etag, "W/") == strings.TrimPrefix(c.etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	ifModifiedSince, err := http.ParseTime(req.Header.Get(HEADER_IF_MODIFIED_SINCE))
+	if err != nil {
+		return false
+	}
+	// The header has a precision of one second
+	return !c.lastModified.Truncate(time.Second).After(ifModifiedSince)
+}
+
+func (c *cacheValidator) setHeaders(w http.ResponseWriter) {
+	w.Header().Set(HEADER_ETAG, c.etag)
+	w.Header().Set(HEADER_LAST_MODIFIED, c.lastModified.UTC().Format(http.TimeFormat))
+}
@@ -0,0 +1,277 @@
+package api
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	logger "github.com/sirupsen/logrus"
+)
+
+func TestCacheValidatorNotModified(t *testing.T) {
+	lastUpdated := time.Now().Add(-time.Minute)
+	req := httptest.NewRequest("GET", "http://relay-meter.pokt.network/v0/relays/apps", nil)
+	validator := newCacheValidator(lastUpdated, CONTENT_TYPE_JSON, req)
+
+	testCases := []struct {
+		name            string
+		ifNoneMatch     string
+		ifModifiedSince string
+		expected        bool
+	}{
+		{
+			name: "No conditional headers",
+		},
+		{
+			name:        "Matching ETag",
+			ifNoneMatch: validator.etag,
+			expected:    true,
+		},
+		{
+			name:        "Matching ETag in a list",
+			ifNoneMatch: `"other", ` + validator.etag,
+			expected:    true,
+		},
+		{
+			name:        "Different ETag",
+			ifNoneMatch: `W/"other"`,
+		},
+		{
+			name:            "ETag takes precedence over modification time",
+			ifNoneMatch:     `W/"other"`,
+			ifModifiedSince: time.Now().UTC().Format(http.TimeFormat),
+		},
+		{
+			name:            "Not modified since",
+			ifModifiedSince: lastUpdated.UTC().Format(http.TimeFormat),
+			expected:        true,
+		},
+		{
+			name:            "Modified since",
+			ifModifiedSince: lastUpdated.Add(-time.Hour).UTC().Format(http.TimeFormat),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://relay-meter.pokt.network/v0/relays/apps", nil)
+			if tc.ifNoneMatch != "" {
+				req.Header.Set(HEADER_IF_NONE_MATCH, tc.ifNoneMatch)
+			}
+			if tc.ifModifiedSince != "" {
+				req.Header.Set(HEADER_IF_MODIFIED_SINCE, tc.ifModifiedSince)
+			}
+
+			if got := validator.notModified(req); got != tc.expected {
+				t.Errorf("Expected not modified: %t, got: %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestCacheValidatorChanges(t *testing.T) {
+	lastUpdated := time.Now().Add(-time.Minute)
+	req := httptest.NewRequest("GET", "http://relay-meter.pokt.network/v0/relays/apps", nil)
+	validator := newCacheValidator(lastUpdated, CONTENT_TYPE_JSON, req)
+
+	if other := newCacheValidator(lastUpdated.Add(time.Second), CONTENT_TYPE_JSON, req); other.etag == validator.etag {
+		t.Errorf("Expected a different ETag after the metrics are updated")
+	}
+	if other := newCacheValidator(lastUpdated, CONTENT_TYPE_CSV, req); other.etag == validator.etag {
+		t.Errorf("Expected a different ETag for a different content type")
+	}
+	otherReq := httptest.NewRequest("GET", "http://relay-meter.pokt.network/v0/relays/apps?limit=1", nil)
+	if other := newCacheValidator(lastUpdated, CONTENT_TYPE_JSON, otherReq); other.etag == validator.etag {
+		t.Errorf("Expected a different ETag for different query parameters")
+	}
+
+	// Metrics updated on a previous day: responses are considered modified at the start of today
+	now := time.Now()
+	today, _, _ := AdjustTimePeriod(now, now)
+	if got := newCacheValidator(today.AddDate(0, 0, -1), CONTENT_TYPE_JSON, req).lastModified; !got.Equal(today) {
+		t.Errorf("Expected last modified: %v, got: %v", today, got)
+	}
+}
+
+func TestHandleEndpointConditionalRequests(t *testing.T) {
+	fakeMeter := fakeRelayMeter{
+		allResponse: []AppRelaysResponse{{Application: "app1", Count: RelayCounts{Success: 10}}},
+		lastUpdated: time.Now().Add(-time.Minute),
+	}
+	serve := func(header http.Header) *http.Response {
+		req := httptest.NewRequest("GET", "http://relay-meter.pokt.network/v0/relays/apps", nil)
+		req.Header = header
+		w := httptest.NewRecorder()
+		handleAllAppsRelays(&fakeMeter, logger.New(), w, req)
+		return w.Result()
+	}
+
+	resp := serve(http.Header{})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status code: %d, got: %d", http.StatusOK, resp.StatusCode)
+	}
+	etag := resp.Header.Get(HEADER_ETAG)
+	if etag == "" || resp.Header.Get(HEADER_LAST_MODIFIED) == "" {
+		t.Fatalf("Expected validators, got ETag: %q, Last-Modified: %q", etag, resp.Header.Get(HEADER_LAST_MODIFIED))
+	}
+
+	resp = serve(http.Header{HEADER_IF_NONE_MATCH: []string{etag}})
+	if resp.StatusCode != http.StatusNotModified {
+		t.Fatalf("Expected status code: %d, got: %d", http.StatusNotModified, resp.StatusCode)
+	}
+	if body, _ := io.ReadAll(resp.Body); len(body) != 0 {
+		t.Errorf("Expected an empty body, got: %s", string(body))
+	}
+
+	// New metrics are loaded: the full response is returned
+	fakeMeter.lastUpdated = time.Now()
+	resp = serve(http.Header{HEADER_IF_NONE_MATCH: []string{etag}})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status code: %d, got: %d", http.StatusOK, resp.StatusCode)
+	}
+	if resp.Header.Get(HEADER_ETAG) == etag {
+		t.Errorf("Expected a new ETag, got: %s", etag)
+	}
+
+	// Responses are not cacheable before any metrics are loaded
+	fakeMeter.lastUpdated = time.Time{}
+	resp = serve(http.Header{HEADER_IF_NONE_MATCH: []string{"*"}})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status code: %d, got: %d", http.StatusOK, resp.StatusCode)
+	}
+	if resp.Header.Get(HEADER_ETAG) != "" {
+		t.Errorf("Expected no ETag, got: %s", resp.Header.Get(HEADER_ETAG))
+	}
+}
+
+func TestHandleEndpointConditionalRequestsNegotiateFirst(t *testing.T) {
+	testCases := []struct {
+		name           string
+		path           string
+		accept         string
+		meterErr       error
+		expectedStatus int
+	}{
+		{
+			name:           "Content type the endpoint cannot produce",
+			path:           "/v0/relays/apps/app1/compare",
+			accept:         CONTENT_TYPE_CSV,
+			expectedStatus: http.StatusNotAcceptable,
+		},
+		{
+			name:           "Error response",
+			path:           "/v0/relays/apps/app1",
+			meterErr:       AppNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Successful response",
+			path:           "/v0/relays/apps/app1",
+			accept:         CONTENT_TYPE_CSV,
+			expectedStatus: http.StatusNotModified,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeMeter := fakeRelayMeter{
+				lastUpdated: time.Now().Add(-time.Minute),
+				responseErr: tc.meterErr,
+			}
+			req := httptest.NewRequest("GET", "http://relay-meter.pokt.network"+tc.path, nil)
+			req.Header.Set(HEADER_IF_NONE_MATCH, "*")
+			if tc.accept != "" {
+				req.Header.Set(HEADER_ACCEPT, tc.accept)
+			}
+			w := httptest.NewRecorder()
+			GetHttpServer(&fakeMeter, logger.New())(w, req)
+
+			if resp := w.Result(); resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected status code: %d, got: %d", tc.expectedStatus, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestHandleEndpointGzip(t *testing.T) {
+	expected := []AppRelaysResponse{{Application: "app1", Count: RelayCounts{Success: 10}}}
+	fakeMeter := fakeRelayMeter{
+		allResponse: expected,
+		response:    expected[0],
+	}
+
+	testCases := []struct {
+		name            string
+		acceptEncoding  string
+		handler         func(w http.ResponseWriter, req *http.Request)
+		expectedEncoded bool
+	}{
+		{
+			name:           "List responses are compressed",
+			acceptEncoding: "deflate, gzip",
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				handleAllAppsRelays(&fakeMeter, logger.New(), w, req)
+			},
+			expectedEncoded: true,
+		},
+		{
+			name: "Responses are not compressed if not accepted by the client",
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				handleAllAppsRelays(&fakeMeter, logger.New(), w, req)
+			},
+		},
+		{
+			name:           "Responses are not compressed if gzip is disabled by the client",
+			acceptEncoding: "gzip;q=0",
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				handleAllAppsRelays(&fakeMeter, logger.New(), w, req)
+			},
+		},
+		{
+			name:           "Single item responses are not compressed",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				handleAppRelays(&fakeMeter, logger.New(), "app1", w, req)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://relay-meter.pokt.network/v0/relays/apps", nil)
+			req.Header.Set(HEADER_ACCEPT_ENCODING, tc.acceptEncoding)
+			w := httptest.NewRecorder()
+			tc.handler(w, req)
+
+			resp := w.Result()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("Expected status code: %d, got: %d", http.StatusOK, resp.StatusCode)
+			}
+			encoded := resp.Header.Get(HEADER_CONTENT_ENCODING) == CONTENT_ENCODING_GZIP
+			if encoded != tc.expectedEncoded {
+				t.Fatalf("Expected gzip encoding: %t, got: %t", tc.expectedEncoded, encoded)
+			}
+			if !encoded {
+				return
+			}
+
+			reader, err := gzip.NewReader(resp.Body)
+			if err != nil {
+				t.Fatalf("Unexpected error reading compressed response: %v", err)
+			}
+			body, _ := io.ReadAll(reader)
+			var got []AppRelaysResponse
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("Unexpected error unmarhsalling the response: %v", err)
+			}
+			if diff := cmp.Diff(expected, got); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const (
+	PARAMETER_CHAIN    = "chain"
+	PARAMETER_GROUP_BY = "groupBy"
+
+	GROUP_BY_CHAIN = "chain"
+)
+
+// chainOptions holds the options on the breakdown of relays by chain, i.e. blockchain ID, of a relays request.
+type chainOptions struct {
+	// Only the relays of this chain are counted, if set
+	chain string
+	// The breakdown of counts by chain is included in the response
+	groupByChain bool
+}
+
+// parseChainOptions returns the chain options of the request, e.g. ?chain=0021 or ?groupBy=chain: errors wrap InvalidRequest.
+func parseChainOptions(req *http.Request) (chainOptions, error) {
+	options := chainOptions{chain: req.URL.Query().Get(PARAMETER_CHAIN)}
+
+	switch groupBy := req.URL.Query().Get(PARAMETER_GROUP_BY); groupBy {
+	case "":
+	case GROUP_BY_CHAIN:
+		options.groupByChain = true
+	default:
+		return options, fmt.Errorf("%w: invalid %s parameter: %s", InvalidRequest, PARAMETER_GROUP_BY, groupBy)
+	}
+	return options, nil
+}
+
+// apply returns the counts to include in the response: the breakdown by chain is only kept if grouping by chain.
+//	If a chain is specified, the counts only include that chain's relays: they are zero for metrics with no breakdown by chain.
+//	The input counts are not modified.
+func (o chainOptions) apply(counts RelayCounts) RelayCounts {
+	if o.chain != "" {
+		chainCounts := counts.Chains[o.chain]
+		if o.groupByChain {
+			return ChainRelayCounts(o.chain, chainCounts)
+		}
+		return chainCounts
+	}
+
+	if !o.groupByChain {
+		counts.Chains = nil
+	}
+	return counts
+}
+
+// applyLatency returns the latency statistics to include in the response: they are not broken down by chain,
+//	so none are returned if a chain is specified, rather than those of all chains.
+func (o chainOptions) applyLatency(latency *RelayLatency) *RelayLatency {
+	if o.chain != "" {
+		return nil
+	}
+	return latency
+}
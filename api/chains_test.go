@@ -0,0 +1,166 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	logger "github.com/sirupsen/logrus"
+)
+
+func TestChainOptionsApply(t *testing.T) {
+	counts := RelayCounts{
+		Success: 10,
+		Failure: 4,
+		Chains: map[string]RelayCounts{
+			"0021": {Success: 7, Failure: 1},
+			"0009": {Success: 3, Failure: 3},
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		query       string
+		expected    RelayCounts
+		expectedErr error
+	}{
+		{
+			name:     "Breakdown by chain is removed by default",
+			expected: RelayCounts{Success: 10, Failure: 4},
+		},
+		{
+			name:     "Breakdown by chain is kept when grouping by chain",
+			query:    "groupBy=chain",
+			expected: counts,
+		},
+		{
+			name:     "Only the specified chain is counted",
+			query:    "chain=0021",
+			expected: RelayCounts{Success: 7, Failure: 1},
+		},
+		{
+			name:  "Breakdown holds only the specified chain when grouping by chain",
+			query: "chain=0009&groupBy=chain",
+			expected: RelayCounts{
+				Success: 3,
+				Failure: 3,
+				Chains:  map[string]RelayCounts{"0009": {Success: 3, Failure: 3}},
+			},
+		},
+		{
+			name:  "Chain with no relays",
+			query: "chain=0040",
+		},
+		{
+			name:        "Invalid groupBy parameter",
+			query:       "groupBy=application",
+			expectedErr: InvalidRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://relay-meter.pokt.network/v0/relays?"+tc.query, nil)
+			options, err := parseChainOptions(req)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("Expected error: %v, got: %v", tc.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			got := options.apply(counts)
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+			// The input counts are not modified
+			if len(counts.Chains) != 2 {
+				t.Errorf("Expected the input breakdown to be unchanged, got: %v", counts.Chains)
+			}
+		})
+	}
+}
+
+func TestChainOptionsApplyLatency(t *testing.T) {
+	latency := &RelayLatency{Relays: 10, Average: 0.5, P50: 0.4}
+
+	testCases := []struct {
+		name     string
+		query    string
+		expected *RelayLatency
+	}{
+		{
+			name:     "Latency is kept by default",
+			expected: latency,
+		},
+		{
+			name:     "Latency is kept when grouping by chain",
+			query:    "groupBy=chain",
+			expected: latency,
+		},
+		{
+			name:  "Latency is dropped if a chain is specified, as it covers all chains",
+			query: "chain=0021",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://relay-meter.pokt.network/v0/relays?"+tc.query, nil)
+			options, err := parseChainOptions(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.expected, options.applyLatency(latency)); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHandleAllAppsRelaysByChain(t *testing.T) {
+	fakeMeter := fakeRelayMeter{
+		allResponse: []AppRelaysResponse{
+			{
+				Application: "app1",
+				Count: RelayCounts{Success: 100, Chains: map[string]RelayCounts{
+					"0021": {Success: 10},
+					"0009": {Success: 90},
+				}},
+				Latency: &RelayLatency{Relays: 100, Average: 0.5},
+			},
+			{
+				Application: "app2",
+				Count:       ChainRelayCounts("0021", RelayCounts{Success: 50}),
+			},
+		},
+	}
+
+	req := httptest.NewRequest("GET", "http://relay-meter.pokt.network/v0/relays/apps?chain=0021&sort=-total", nil)
+	w := httptest.NewRecorder()
+	handleAllAppsRelays(&fakeMeter, logger.New(), w, req)
+
+	resp := w.Result()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status code: %d, got: %d", http.StatusOK, resp.StatusCode)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	var got []AppRelaysResponse
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("Unexpected error unmarhsalling the response: %v", err)
+	}
+	// Applications are sorted on the chain's counts, and the latency of all chains is dropped
+	expected := []AppRelaysResponse{
+		{Application: "app2", Count: RelayCounts{Success: 50}},
+		{Application: "app1", Count: RelayCounts{Success: 10}},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected value (-want +got):\n%s", diff)
+	}
+}
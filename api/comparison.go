@@ -0,0 +1,130 @@
+package api
+
+import (
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+)
+
+// RelaysComparisonResponse compares the relays of a time period with the previous time period of equal length, e.g. this week versus last week.
+//	Only the field identifying the compared application, user, or endpoint is set: none is set when comparing the total relays.
+type RelaysComparisonResponse struct {
+	Current  TotalRelaysResponse
+	Previous TotalRelaysResponse
+	Delta    RelaysDelta
+
+	Application string `json:",omitempty"`
+	User        string `json:",omitempty"`
+	Endpoint    string `json:",omitempty"`
+}
+
+// RelaysDelta holds the changes of the relay counts from the previous time period to the current one.
+type RelaysDelta struct {
+	Total   int64
+	Success int64
+	Failure int64
+	// Changes as a percentage of the previous time period's counts, e.g. 25 for a 25% growth.
+	//	They are nil if the previous time period has no such relays.
+	TotalPercent   *float64
+	SuccessPercent *float64
+	FailurePercent *float64
+}
+
+// newRelaysDelta returns the changes from the previous counts to the current ones.
+func newRelaysDelta(current, previous RelayCounts) RelaysDelta {
+	percent := func(current, previous int64) *float64 {
+		if previous == 0 {
+			return nil
+		}
+		p := float64(current-previous) / float64(previous) * 100
+		return &p
+	}
+
+	return RelaysDelta{
+		Total:          current.Total() - previous.Total(),
+		Success:        current.Success - previous.Success,
+		Failure:        current.Failure - previous.Failure,
+		TotalPercent:   percent(current.Total(), previous.Total()),
+		SuccessPercent: percent(current.Success, previous.Success),
+		FailurePercent: percent(current.Failure, previous.Failure),
+	}
+}
+
+// CompareAppRelays compares the relays of the app over the specified time period with the previous time period of equal length.
+func (r *relayMeter) CompareAppRelays(app string, from, to time.Time) (RelaysComparisonResponse, error) {
+	r.Logger.WithFields(logger.Fields{"app": app, "from": from, "to": to}).Info("apiserver: Received CompareAppRelays request")
+
+	usage := func(from, to time.Time) (TotalRelaysResponse, error) {
+		appRelays, err := r.AppRelays(app, from, to)
+		return TotalRelaysResponse{Count: appRelays.Count, From: appRelays.From, To: appRelays.To, Notes: appRelays.Notes}, err
+	}
+	resp, err := compareRelays(from, to, r.location(), usage)
+	resp.Application = app
+	return resp, err
+}
+
+// CompareUserRelays compares the relays of all the user's applications over the specified time period with the previous time period of equal length.
+func (r *relayMeter) CompareUserRelays(user string, from, to time.Time) (RelaysComparisonResponse, error) {
+	r.Logger.WithFields(logger.Fields{"user": user, "from": from, "to": to}).Info("apiserver: Received CompareUserRelays request")
+
+	usage := func(from, to time.Time) (TotalRelaysResponse, error) {
+		userRelays, err := r.UserRelays(user, from, to)
+		return TotalRelaysResponse{Count: userRelays.Count, From: userRelays.From, To: userRelays.To, Notes: userRelays.Notes}, err
+	}
+	resp, err := compareRelays(from, to, r.location(), usage)
+	resp.User = user
+	return resp, err
+}
+
+// CompareLoadBalancerRelays compares the relays of all the load balancer's applications over the specified time period with the previous time period of equal length.
+func (r *relayMeter) CompareLoadBalancerRelays(endpoint string, from, to time.Time) (RelaysComparisonResponse, error) {
+	r.Logger.WithFields(logger.Fields{"endpoint": endpoint, "from": from, "to": to}).Info("apiserver: Received CompareLoadBalancerRelays request")
+
+	usage := func(from, to time.Time) (TotalRelaysResponse, error) {
+		lbRelays, err := r.LoadBalancerRelays(endpoint, from, to)
+		return TotalRelaysResponse{Count: lbRelays.Count, From: lbRelays.From, To: lbRelays.To, Notes: lbRelays.Notes}, err
+	}
+	resp, err := compareRelays(from, to, r.location(), usage)
+	resp.Endpoint = endpoint
+	return resp, err
+}
+
+// CompareTotalRelays compares the relays of all applications over the specified time period with the previous time period of equal length.
+func (r *relayMeter) CompareTotalRelays(from, to time.Time) (RelaysComparisonResponse, error) {
+	r.Logger.WithFields(logger.Fields{"from": from, "to": to}).Info("apiserver: Received CompareTotalRelays request")
+
+	return compareRelays(from, to, r.location(), r.TotalRelays)
+}
+
+// compareRelays compares the relays of the time period, adjusted as done by AdjustTimePeriodIn, with the previous time period of the same number of days.
+//	If the current time period ends after today, only the days up to today are compared, see the previous time period's calculation below.
+//	The usage function returns the relays of a time period: it is passed the first and the last day of the period, e.g. TotalRelays.
+//	Its response is included as is, so any changes made to either time period, e.g. limiting it to the days with metrics, are noted.
+func compareRelays(from, to time.Time, loc *time.Location, usage func(from, to time.Time) (TotalRelaysResponse, error)) (RelaysComparisonResponse, error) {
+	var resp RelaysComparisonResponse
+
+	from, to, err := AdjustTimePeriodIn(from, to, loc)
+	if err != nil {
+		return resp, err
+	}
+	// Adjusted times are at the start of a day in the reporting timezone, so the period is a whole number of days
+	days := daysBetween(from, to)
+
+	current, err := usage(from, to.AddDate(0, 0, -1))
+	if err != nil {
+		return resp, err
+	}
+	// The current time period may have been limited by the usage function, e.g. to today: the previous time period is limited
+	//	to the same number of days, so e.g. this week so far is compared with the same days of last week, and not with all of last week.
+	previousFrom := current.From.AddDate(0, 0, -days)
+	previousTo := previousFrom.AddDate(0, 0, daysBetween(current.From, current.To))
+	previous, err := usage(previousFrom, previousTo.AddDate(0, 0, -1))
+	if err != nil {
+		return resp, err
+	}
+
+	resp.Current = current
+	resp.Previous = previous
+	resp.Delta = newRelaysDelta(current.Count, previous.Count)
+	return resp, nil
+}
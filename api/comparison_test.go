@@ -0,0 +1,201 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	logger "github.com/sirupsen/logrus"
+)
+
+func TestRelaysDelta(t *testing.T) {
+	percent := func(p float64) *float64 { return &p }
+
+	testCases := []struct {
+		name     string
+		current  RelayCounts
+		previous RelayCounts
+		expected RelaysDelta
+	}{
+		{
+			name:     "Growth and decline",
+			current:  RelayCounts{Success: 150, Failure: 10},
+			previous: RelayCounts{Success: 100, Failure: 20},
+			expected: RelaysDelta{
+				Total:          40,
+				Success:        50,
+				Failure:        -10,
+				TotalPercent:   percent(float64(40) / float64(120) * 100),
+				SuccessPercent: percent(50),
+				FailurePercent: percent(-50),
+			},
+		},
+		{
+			name:     "Previous period with no relays has no percentages",
+			current:  RelayCounts{Success: 10},
+			previous: RelayCounts{},
+			expected: RelaysDelta{Total: 10, Success: 10},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.expected, newRelaysDelta(tc.current, tc.previous)); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCompareRelays(t *testing.T) {
+	today, _ := time.Parse(dayFormat, time.Now().UTC().Format(dayFormat))
+
+	fakeBackend := fakeBackend{
+		usage:       fakeDailyMetrics(),
+		todaysUsage: fakeTodaysMetrics(),
+		userApps: map[string][]string{
+			"user1": {"app1", "app2"},
+		},
+	}
+	meter := NewRelayMeter(&fakeBackend, logger.New(), RelayMeterOptions{LoadInterval: 100 * time.Millisecond})
+	time.Sleep(200 * time.Millisecond)
+
+	testCases := []struct {
+		name        string
+		compare     func(from, to time.Time) (RelaysComparisonResponse, error)
+		from        time.Time
+		to          time.Time
+		expected    RelaysComparisonResponse
+		expectedErr bool
+	}{
+		{
+			name: "Application: period including today is compared with the previous days",
+			compare: func(from, to time.Time) (RelaysComparisonResponse, error) {
+				return meter.CompareAppRelays("app1", from, to)
+			},
+			from: today.AddDate(0, 0, -2),
+			to:   today,
+			expected: RelaysComparisonResponse{
+				Current: TotalRelaysResponse{
+					Count: RelayCounts{Success: 2*2 + 50, Failure: 2*3 + 40},
+					From:  today.AddDate(0, 0, -2),
+					To:    today.AddDate(0, 0, 1),
+				},
+				Previous: TotalRelaysResponse{
+					Count: RelayCounts{Success: 3 * 2, Failure: 3 * 3},
+					From:  today.AddDate(0, 0, -5),
+					To:    today.AddDate(0, 0, -2),
+				},
+				Delta:       newRelaysDelta(RelayCounts{Success: 54, Failure: 46}, RelayCounts{Success: 6, Failure: 9}),
+				Application: "app1",
+			},
+		},
+		{
+			name: "Application: previous period with no metrics",
+			compare: func(from, to time.Time) (RelaysComparisonResponse, error) {
+				return meter.CompareAppRelays("app1", from, to)
+			},
+			from: today.AddDate(0, 0, -6),
+			to:   today.AddDate(0, 0, -4),
+			expected: RelaysComparisonResponse{
+				Current: TotalRelaysResponse{
+					Count: RelayCounts{Success: 3 * 2, Failure: 3 * 3},
+					From:  today.AddDate(0, 0, -6),
+					To:    today.AddDate(0, 0, -3),
+				},
+				Previous: TotalRelaysResponse{
+					From: today.AddDate(0, 0, -9),
+					To:   today.AddDate(0, 0, -6),
+				},
+				Delta:       RelaysDelta{Total: 15, Success: 6, Failure: 9},
+				Application: "app1",
+			},
+		},
+		{
+			name: "User: all the user's applications are compared",
+			compare: func(from, to time.Time) (RelaysComparisonResponse, error) {
+				return meter.CompareUserRelays("user1", from, to)
+			},
+			from: today.AddDate(0, 0, -1),
+			to:   today.AddDate(0, 0, -1),
+			expected: RelaysComparisonResponse{
+				Current: TotalRelaysResponse{
+					Count: RelayCounts{Success: 2 + 1, Failure: 3 + 5},
+					From:  today.AddDate(0, 0, -1),
+					To:    today,
+				},
+				Previous: TotalRelaysResponse{
+					Count: RelayCounts{Success: 2 + 1, Failure: 3 + 5},
+					From:  today.AddDate(0, 0, -2),
+					To:    today.AddDate(0, 0, -1),
+				},
+				Delta: newRelaysDelta(RelayCounts{Success: 3, Failure: 8}, RelayCounts{Success: 3, Failure: 8}),
+				User:  "user1",
+			},
+		},
+		{
+			name: "Application: period ending after today is compared with the same days of the previous period",
+			compare: func(from, to time.Time) (RelaysComparisonResponse, error) {
+				return meter.CompareAppRelays("app1", from, to)
+			},
+			from: today.AddDate(0, 0, -1),
+			to:   today.AddDate(0, 0, 1),
+			expected: RelaysComparisonResponse{
+				Current: TotalRelaysResponse{
+					Count: RelayCounts{Success: 2 + 50, Failure: 3 + 40},
+					From:  today.AddDate(0, 0, -1),
+					To:    today.AddDate(0, 0, 1),
+					Notes: afterTodayNotes(today, today.AddDate(0, 0, 1)),
+				},
+				Previous: TotalRelaysResponse{
+					Count: RelayCounts{Success: 2 * 2, Failure: 2 * 3},
+					From:  today.AddDate(0, 0, -4),
+					To:    today.AddDate(0, 0, -2),
+				},
+				Delta:       newRelaysDelta(RelayCounts{Success: 52, Failure: 43}, RelayCounts{Success: 4, Failure: 6}),
+				Application: "app1",
+			},
+		},
+		{
+			name:    "Total: all applications are compared",
+			compare: meter.CompareTotalRelays,
+			from:    today.AddDate(0, 0, -2),
+			to:      today.AddDate(0, 0, -1),
+			expected: RelaysComparisonResponse{
+				Current: TotalRelaysResponse{
+					Count: RelayCounts{Success: 2 * 8, Failure: 2 * 15},
+					From:  today.AddDate(0, 0, -2),
+					To:    today,
+				},
+				Previous: TotalRelaysResponse{
+					Count: RelayCounts{Success: 2 * 8, Failure: 2 * 15},
+					From:  today.AddDate(0, 0, -4),
+					To:    today.AddDate(0, 0, -2),
+				},
+				Delta: newRelaysDelta(RelayCounts{Success: 16, Failure: 30}, RelayCounts{Success: 16, Failure: 30}),
+			},
+		},
+		{
+			name:        "Invalid time period returns an error",
+			compare:     meter.CompareTotalRelays,
+			from:        today,
+			to:          today.AddDate(0, 0, -1),
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.compare(tc.from, tc.to)
+			if (err != nil) != tc.expectedErr {
+				t.Fatalf("Expected error: %v, got: %v", tc.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	logger "github.com/sirupsen/logrus"
+)
+
+// Error codes are stable, machine-readable identifiers of the error responses: clients can rely on them, while messages may change.
+const (
+	ERROR_CODE_INVALID_REQUEST     = "invalid_request"
+	ERROR_CODE_INVALID_TIMESPAN    = "invalid_timespan"
+	ERROR_CODE_INVALID_PATH        = "invalid_path"
+	ERROR_CODE_APP_NOT_FOUND       = "app_not_found"
+	ERROR_CODE_LB_NOT_FOUND        = "lb_not_found"
+	ERROR_CODE_QUOTA_NOT_FOUND     = "quota_not_found"
+	ERROR_CODE_NOT_ACCEPTABLE      = "not_acceptable"
+	ERROR_CODE_METHOD_NOT_ALLOWED  = "method_not_allowed"
+	ERROR_CODE_UNAUTHENTICATED     = "unauthenticated"
+	ERROR_CODE_FORBIDDEN           = "forbidden"
+	ERROR_CODE_RATE_LIMITED        = "rate_limited"
+	ERROR_CODE_BACKEND_UNAVAILABLE = "backend_unavailable"
+	ERROR_CODE_INTERNAL_ERROR      = "internal_error"
+)
+
+// ErrorResponse is the body of all error responses.
+type ErrorResponse struct {
+	// Code is one of the ERROR_CODE_* values, e.g. lb_not_found
+	Code    string
+	Message string
+}
+
+// errorStatus returns the HTTP status code and the error code of an error returned by a meter endpoint.
+//	The message is the error's own message, except for internal errors, whose details are only logged.
+func errorStatus(err error) (int, string, string) {
+	switch {
+	case errors.Is(err, ErrInvalidTimespan):
+		return http.StatusBadRequest, ERROR_CODE_INVALID_TIMESPAN, err.Error()
+	case errors.Is(err, InvalidRequest):
+		return http.StatusBadRequest, ERROR_CODE_INVALID_REQUEST, err.Error()
+	case errors.Is(err, AppNotFound):
+		return http.StatusNotFound, ERROR_CODE_APP_NOT_FOUND, err.Error()
+	case errors.Is(err, ErrLoadBalancerNotFound):
+		return http.StatusNotFound, ERROR_CODE_LB_NOT_FOUND, err.Error()
+	case errors.Is(err, ErrQuotaNotFound):
+		return http.StatusNotFound, ERROR_CODE_QUOTA_NOT_FOUND, err.Error()
+	case errors.Is(err, NotAcceptable):
+		return http.StatusNotAcceptable, ERROR_CODE_NOT_ACCEPTABLE, err.Error()
+	case errors.Is(err, ErrBackendUnavailable):
+		return http.StatusServiceUnavailable, ERROR_CODE_BACKEND_UNAVAILABLE, ErrBackendUnavailable.Error()
+	default:
+		return http.StatusInternalServerError, ERROR_CODE_INTERNAL_ERROR, "Internal server error"
+	}
+}
+
+// WriteError writes an error response: the body is always JSON, regardless of the content type requested by the client.
+//	It is exported so other servers, e.g. the collector's ingestion endpoint, return the same error responses.
+func WriteError(l *logger.Logger, w http.ResponseWriter, statusCode int, code, message string) {
+	bytes, err := json.Marshal(ErrorResponse{Code: code, Message: message})
+	if err != nil {
+		l.WithFields(logger.Fields{"error": err}).Warn("Internal error marshalling error response")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(HEADER_CONTENT_TYPE, CONTENT_TYPE_JSON)
+	w.WriteHeader(statusCode)
+	if _, err := w.Write(bytes); err != nil {
+		l.WithFields(logger.Fields{"error": err}).Warn("Error writing error response")
+	}
+}
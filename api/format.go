@@ -0,0 +1,296 @@
+package api
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HEADER_ACCEPT           = "Accept"
+	HEADER_CONTENT_TYPE     = "Content-Type"
+	HEADER_ACCEPT_ENCODING  = "Accept-Encoding"
+	HEADER_CONTENT_ENCODING = "Content-Encoding"
+
+	CONTENT_ENCODING_GZIP = "gzip"
+
+	CONTENT_TYPE_JSON   = "application/json"
+	CONTENT_TYPE_CSV    = "text/csv"
+	CONTENT_TYPE_NDJSON = "application/x-ndjson"
+
+	// Number of NDJSON lines written between flushes of the response
+	NDJSON_FLUSH_LINES = 100
+)
+
+// supportedContentTypes lists the response content types, in order of preference when the Accept header allows several, e.g. */*
+var supportedContentTypes = []string{CONTENT_TYPE_JSON, CONTENT_TYPE_CSV, CONTENT_TYPE_NDJSON}
+
+// negotiateContentType returns the content type of the response, based on the request's Accept header: JSON is returned if the header is not set.
+//	The supported media type with the highest quality value is returned, with ties going to the first one listed in the header.
+//	Errors wrap NotAcceptable.
+func negotiateContentType(req *http.Request) (string, error) {
+	accept := req.Header.Get(HEADER_ACCEPT)
+	if accept == "" {
+		return CONTENT_TYPE_JSON, nil
+	}
+
+	var contentType string
+	var quality float64
+	for _, item := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(item))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		if q <= quality {
+			continue
+		}
+		if matched := matchContentType(mediaType); matched != "" {
+			contentType, quality = matched, q
+		}
+	}
+
+	if contentType == "" {
+		return "", fmt.Errorf("%w: %s, supported content types: %s", NotAcceptable, accept, strings.Join(supportedContentTypes, ", "))
+	}
+	return contentType, nil
+}
+
+// matchContentType returns the preferred supported content type matching the media type, which can be a wildcard, e.g. text/*
+func matchContentType(mediaType string) string {
+	for _, contentType := range supportedContentTypes {
+		if mediaType == contentType || mediaType == "*/*" {
+			return contentType
+		}
+		if strings.HasSuffix(mediaType, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(mediaType, "*")) {
+			return contentType
+		}
+	}
+	return ""
+}
+
+// relaysRow is a single row of the CSV output: the relays of an application, endpoint, user, or all relays, over a time period.
+type relaysRow struct {
+	key   string
+	from  string
+	to    string
+	chain string
+	count RelayCounts
+	// partial is only set for the rows of buckets, see RelaysBucket
+	partial string
+}
+
+// relaysRows returns the rows of the CSV output for the response, and the name of the column holding the rows' key, e.g. application.
+//	Only relays responses are supported: the returned error wraps NotAcceptable for any other response.
+func relaysRows(resp any) (string, []relaysRow, error) {
+	row := func(key string, count RelayCounts, from, to time.Time) relaysRow {
+		return relaysRow{key: key, from: from.Format(DATE_LAYOUT), to: to.Format(DATE_LAYOUT), count: count}
+	}
+
+	var keyColumn string
+	var rows []relaysRow
+	switch r := resp.(type) {
+	case AppRelaysResponse:
+		keyColumn = "application"
+		rows = append(rows, row(r.Application, r.Count, r.From, r.To))
+	case []AppRelaysResponse:
+		keyColumn = "application"
+		for _, item := range r {
+			rows = append(rows, row(item.Application, item.Count, item.From, item.To))
+		}
+	case LoadBalancerRelaysResponse:
+		keyColumn = "endpoint"
+		rows = append(rows, row(r.Endpoint, r.Count, r.From, r.To))
+	case []LoadBalancerRelaysResponse:
+		keyColumn = "endpoint"
+		for _, item := range r {
+			rows = append(rows, row(item.Endpoint, item.Count, item.From, item.To))
+		}
+	case UserRelaysResponse:
+		keyColumn = "user"
+		rows = append(rows, row(r.User, r.Count, r.From, r.To))
+	case TotalRelaysResponse:
+		rows = append(rows, row("", r.Count, r.From, r.To))
+	case []RelaysBucket:
+		// The rows of each bucket are already broken down by chain
+		var bucketRows []relaysRow
+		for _, bucket := range r {
+			var err error
+			var relays []relaysRow
+			keyColumn, relays, err = relaysRows(bucket.Relays)
+			if err != nil {
+				return "", nil, err
+			}
+			for _, item := range relays {
+				item.partial = strconv.FormatBool(bucket.Partial)
+				bucketRows = append(bucketRows, item)
+			}
+		}
+		return keyColumn, bucketRows, nil
+	default:
+		return "", nil, fmt.Errorf("%w: %s is not supported for this endpoint", NotAcceptable, CONTENT_TYPE_CSV)
+	}
+
+	// Counts broken down by chain are written as one row per chain
+	var chainRows []relaysRow
+	for _, r := range rows {
+		if len(r.count.Chains) == 0 {
+			chainRows = append(chainRows, r)
+			continue
+		}
+
+		var chains []string
+		for chain := range r.count.Chains {
+			chains = append(chains, chain)
+		}
+		sort.Strings(chains)
+		for _, chain := range chains {
+			chainRow := r
+			chainRow.chain = chain
+			chainRow.count = r.count.Chains[chain]
+			chainRows = append(chainRows, chainRow)
+		}
+	}
+	return keyColumn, chainRows, nil
+}
+
+// writeCSV writes the rows as CSV, with a header row. The key column is omitted if empty, i.e. for the total relays.
+//	The partial column is only included for the rows of buckets.
+func writeCSV(w io.Writer, keyColumn string, rows []relaysRow) error {
+	header := []string{"from", "to", "chain", "success", "failure", "client_error", "server_error", "timeout", "other", "total"}
+	if keyColumn != "" {
+		header = append([]string{keyColumn}, header...)
+	}
+	partialColumn := len(rows) > 0 && rows[0].partial != ""
+	if partialColumn {
+		header = append(header, "partial")
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		c := r.count
+		record := []string{r.from, r.to, r.chain}
+		for _, value := range []int64{c.Success, c.Failure, c.ClientError, c.ServerError, c.Timeout, c.Other, c.Total()} {
+			record = append(record, strconv.FormatInt(value, 10))
+		}
+		if keyColumn != "" {
+			record = append([]string{r.key}, record...)
+		}
+		if partialColumn {
+			record = append(record, r.partial)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeNDJSON writes the response as newline-delimited JSON: list responses are written one item per line, flushing every NDJSON_FLUSH_LINES lines,
+//	so the encoded response is not buffered as a whole. The items themselves are all computed before the first line is written.
+//	Any other response is written as a single line.
+func writeNDJSON(w io.Writer, resp any) error {
+	encoder := json.NewEncoder(w)
+	flush := func() {
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+	}
+
+	items := reflect.ValueOf(resp)
+	if items.Kind() != reflect.Slice {
+		return encoder.Encode(resp)
+	}
+	for i := 0; i < items.Len(); i++ {
+		if err := encoder.Encode(items.Index(i).Interface()); err != nil {
+			return err
+		}
+		if (i+1)%NDJSON_FLUSH_LINES == 0 {
+			flush()
+		}
+	}
+	flush()
+	return nil
+}
+
+// acceptsGzip returns true if the request's Accept-Encoding header allows gzip compression of the response
+func acceptsGzip(req *http.Request) bool {
+	for _, item := range strings.Split(req.Header.Get(HEADER_ACCEPT_ENCODING), ",") {
+		params := strings.Split(item, ";")
+		encoding := strings.ToLower(strings.TrimSpace(params[0]))
+		if encoding != CONTENT_ENCODING_GZIP && encoding != "*" {
+			continue
+		}
+		// An encoding with a quality value of 0 is not acceptable
+		disabled := false
+		for _, param := range params[1:] {
+			if q := strings.TrimPrefix(strings.TrimSpace(param), "q="); q != strings.TrimSpace(param) {
+				value, err := strconv.ParseFloat(q, 64)
+				disabled = err != nil || value == 0
+			}
+		}
+		if !disabled {
+			return true
+		}
+	}
+	return false
+}
+
+// bodyWriter writes the body of a response, compressing it with gzip if enabled.
+type bodyWriter struct {
+	w    http.ResponseWriter
+	gzip *gzip.Writer
+}
+
+// newBodyWriter returns the writer for the response body: list responses are compressed with gzip if the client accepts it.
+//	The Content-Encoding header is set accordingly, so this needs to be called before the response's status code is written.
+func newBodyWriter(w http.ResponseWriter, req *http.Request, resp any) *bodyWriter {
+	if !acceptsGzip(req) || reflect.ValueOf(resp).Kind() != reflect.Slice {
+		return &bodyWriter{w: w}
+	}
+	w.Header().Set(HEADER_CONTENT_ENCODING, CONTENT_ENCODING_GZIP)
+	return &bodyWriter{w: w, gzip: gzip.NewWriter(w)}
+}
+
+func (b *bodyWriter) Write(p []byte) (int, error) {
+	if b.gzip != nil {
+		return b.gzip.Write(p)
+	}
+	return b.w.Write(p)
+}
+
+// Flush sends any buffered data to the client
+func (b *bodyWriter) Flush() {
+	if b.gzip != nil {
+		b.gzip.Flush()
+	}
+	if flusher, ok := b.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Close needs to be called once the body is written, to write any remaining compressed data
+func (b *bodyWriter) Close() error {
+	if b.gzip != nil {
+		return b.gzip.Close()
+	}
+	return nil
+}
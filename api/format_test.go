@@ -0,0 +1,190 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	logger "github.com/sirupsen/logrus"
+)
+
+func TestNegotiateContentType(t *testing.T) {
+	testCases := []struct {
+		name        string
+		accept      string
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:     "JSON is returned by default",
+			expected: CONTENT_TYPE_JSON,
+		},
+		{
+			name:     "Any content type",
+			accept:   "*/*",
+			expected: CONTENT_TYPE_JSON,
+		},
+		{
+			name:     "CSV",
+			accept:   "text/csv",
+			expected: CONTENT_TYPE_CSV,
+		},
+		{
+			name:     "Media type parameters are allowed",
+			accept:   "application/x-ndjson; charset=utf-8",
+			expected: CONTENT_TYPE_NDJSON,
+		},
+		{
+			name:     "Highest quality value is selected",
+			accept:   "application/json;q=0.5, text/csv;q=0.9",
+			expected: CONTENT_TYPE_CSV,
+		},
+		{
+			name:     "Unsupported media types are skipped",
+			accept:   "text/html, text/*;q=0.8",
+			expected: CONTENT_TYPE_CSV,
+		},
+		{
+			name:        "No supported media type",
+			accept:      "text/html, application/xml",
+			expectedErr: NotAcceptable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://relay-meter.pokt.network/v0/relays", nil)
+			if tc.accept != "" {
+				req.Header.Set(HEADER_ACCEPT, tc.accept)
+			}
+
+			got, err := negotiateContentType(req)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("Expected error: %v, got: %v", tc.expectedErr, err)
+			}
+			if got != tc.expected {
+				t.Errorf("Expected content type: %s, got: %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestHandleEndpointContentTypes(t *testing.T) {
+	day := time.Date(2022, time.July, 20, 0, 0, 0, 0, time.UTC)
+	fakeMeter := fakeRelayMeter{
+		allResponse: []AppRelaysResponse{
+			{Application: "app1", From: day, To: day.AddDate(0, 0, 1), Count: RelayCounts{Success: 10, Failure: 2, Timeout: 2}},
+			{Application: "app2", From: day, To: day.AddDate(0, 0, 1), Count: RelayCounts{Success: 5}},
+		},
+		appQuotaResponse: AppQuotaResponse{Application: "app1"},
+	}
+
+	testCases := []struct {
+		name               string
+		url                string
+		accept             string
+		handler            func(w http.ResponseWriter, req *http.Request)
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:   "CSV holds one row per application",
+			url:    "http://relay-meter.pokt.network/v0/relays/apps",
+			accept: CONTENT_TYPE_CSV,
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				handleAllAppsRelays(&fakeMeter, logger.New(), w, req)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: "application,from,to,chain,success,failure,client_error,server_error,timeout,other,total\n" +
+				"app1,2022-07-20T00:00:00Z,2022-07-21T00:00:00Z,,10,2,0,0,2,0,12\n" +
+				"app2,2022-07-20T00:00:00Z,2022-07-21T00:00:00Z,,5,0,0,0,0,0,5\n",
+		},
+		{
+			name:   "NDJSON holds one line per application",
+			url:    "http://relay-meter.pokt.network/v0/relays/apps?limit=1",
+			accept: CONTENT_TYPE_NDJSON,
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				handleAllAppsRelays(&fakeMeter, logger.New(), w, req)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{"Count":{"Success":10,"Failure":2,"ClientError":0,"ServerError":0,"Timeout":2,"Other":0,"Total":12,"FailureRatio":0.16666666666666666,"SuccessRatio":0.8333333333333334},` +
+				`"From":"2022-07-20T00:00:00Z","To":"2022-07-21T00:00:00Z","Application":"app1"}` + "\n",
+		},
+		{
+			name:   "CSV is not supported for quotas",
+			url:    "http://relay-meter.pokt.network/v0/quota/apps/app1",
+			accept: CONTENT_TYPE_CSV,
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				handleAppQuota(&fakeMeter, logger.New(), "app1", w, req)
+			},
+			expectedStatusCode: http.StatusNotAcceptable,
+		},
+		{
+			name:   "Unsupported content type",
+			url:    "http://relay-meter.pokt.network/v0/relays/apps",
+			accept: "application/xml",
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				handleAllAppsRelays(&fakeMeter, logger.New(), w, req)
+			},
+			expectedStatusCode: http.StatusNotAcceptable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tc.url, nil)
+			req.Header.Set(HEADER_ACCEPT, tc.accept)
+			w := httptest.NewRecorder()
+			tc.handler(w, req)
+
+			resp := w.Result()
+			if resp.StatusCode != tc.expectedStatusCode {
+				t.Fatalf("Expected status code: %d, got: %d", tc.expectedStatusCode, resp.StatusCode)
+			}
+			if tc.expectedStatusCode != http.StatusOK {
+				return
+			}
+
+			if resp.Header.Get(HEADER_CONTENT_TYPE) != tc.accept {
+				t.Errorf("Expected Content-Type: %s, got: %s", tc.accept, resp.Header.Get(HEADER_CONTENT_TYPE))
+			}
+			body, _ := io.ReadAll(resp.Body)
+			if diff := cmp.Diff(tc.expectedBody, string(body)); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRelaysRowsByChain(t *testing.T) {
+	day := time.Date(2022, time.July, 20, 0, 0, 0, 0, time.UTC)
+	resp := LoadBalancerRelaysResponse{
+		Endpoint: "lb1",
+		From:     day,
+		To:       day.AddDate(0, 0, 1),
+		Count: RelayCounts{Success: 10, Chains: map[string]RelayCounts{
+			"0021": {Success: 7},
+			"0009": {Success: 3},
+		}},
+	}
+
+	keyColumn, rows, err := relaysRows(resp)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if keyColumn != "endpoint" {
+		t.Errorf("Expected key column: endpoint, got: %s", keyColumn)
+	}
+
+	expected := []relaysRow{
+		{key: "lb1", from: "2022-07-20T00:00:00Z", to: "2022-07-21T00:00:00Z", chain: "0009", count: RelayCounts{Success: 3}},
+		{key: "lb1", from: "2022-07-20T00:00:00Z", to: "2022-07-21T00:00:00Z", chain: "0021", count: RelayCounts{Success: 7}},
+	}
+	if diff := cmp.Diff(expected, rows, cmp.AllowUnexported(relaysRow{})); diff != "" {
+		t.Errorf("unexpected value (-want +got):\n%s", diff)
+	}
+}
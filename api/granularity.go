@@ -0,0 +1,101 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+)
+
+const (
+	// Maximum number of buckets a time period can be split into: limits the work of a single request, e.g. 10 years of daily buckets
+	MAX_GRANULARITY_BUCKETS = 400
+)
+
+// Granularity is the length of the periods a time period is split into, see RelayMeter.Buckets
+type Granularity string
+
+const (
+	GRANULARITY_DAY Granularity = "day"
+	// Weeks are ISO weeks, i.e. they start on Monday
+	GRANULARITY_WEEK  Granularity = "week"
+	GRANULARITY_MONTH Granularity = "month"
+)
+
+// RelaysBucket holds the relays of one period of the requested granularity, e.g. one calendar month.
+type RelaysBucket struct {
+	From time.Time
+	To   time.Time
+	// Partial is set if the bucket only covers part of its period: either the requested time period starts or ends within the period,
+	//	e.g. a month's bucket starting on the 15th, or the period has not ended yet, e.g. the current week.
+	Partial bool
+	// Relays is the endpoint's response for the bucket's time period
+	Relays any
+}
+
+// Buckets splits the time period, limited to the days with metrics as done by clampTimePeriod, into periods of the granularity in the reporting timezone.
+//	No buckets are returned if the time period has no days with metrics: every bucket is served by a call to the endpoint,
+//	so the work of a request is bounded by the days with metrics. The Relays field of the buckets is not set.
+func (r *relayMeter) Buckets(from, to time.Time, granularity Granularity) ([]RelaysBucket, error) {
+	r.Logger.WithFields(logger.Fields{"from": from, "to": to, "granularity": granularity}).Info("apiserver: Received Buckets request")
+
+	from, to, _, err := r.clampTimePeriod(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if !r.hasMetrics(from, to) {
+		// The granularity is still validated
+		return splitTimePeriod(from, from, granularity, time.Now().In(r.location()))
+	}
+	return splitTimePeriod(from, to, granularity, time.Now().In(r.location()))
+}
+
+// splitTimePeriod splits the time period into buckets of the granularity: from and to are expected to be at the start of a day.
+//	The time period is cut short at the end of the day containing now, and buckets whose period ends after now are partial.
+//	Errors wrap InvalidRequest.
+func splitTimePeriod(from, to time.Time, granularity Granularity, now time.Time) ([]RelaysBucket, error) {
+	var periodStart func(day time.Time) time.Time
+	var periodEnd func(start time.Time) time.Time
+	switch granularity {
+	case GRANULARITY_DAY:
+		periodStart = func(day time.Time) time.Time { return day }
+		periodEnd = func(start time.Time) time.Time { return start.AddDate(0, 0, 1) }
+	case GRANULARITY_WEEK:
+		periodStart = func(day time.Time) time.Time { return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7)) }
+		periodEnd = func(start time.Time) time.Time { return start.AddDate(0, 0, 7) }
+	case GRANULARITY_MONTH:
+		periodStart = func(day time.Time) time.Time { return day.AddDate(0, 0, 1-day.Day()) }
+		periodEnd = func(start time.Time) time.Time { return start.AddDate(0, 1, 0) }
+	default:
+		return nil, fmt.Errorf("%w: invalid granularity: %q, expected one of: %s, %s, %s", InvalidRequest, granularity, GRANULARITY_DAY, GRANULARITY_WEEK, GRANULARITY_MONTH)
+	}
+
+	_, tomorrow, err := AdjustTimePeriodIn(now, now, from.Location())
+	if err != nil {
+		return nil, err
+	}
+	if tomorrow.Before(to) {
+		to = tomorrow
+	}
+
+	buckets := []RelaysBucket{}
+	for start := from; start.Before(to); {
+		if len(buckets) == MAX_GRANULARITY_BUCKETS {
+			return nil, fmt.Errorf("%w: the time period has more than %d periods of granularity %s", InvalidRequest, MAX_GRANULARITY_BUCKETS, granularity)
+		}
+
+		fullStart := periodStart(start)
+		fullEnd := periodEnd(fullStart)
+		end := fullEnd
+		if to.Before(end) {
+			end = to
+		}
+		buckets = append(buckets, RelaysBucket{
+			From:    start,
+			To:      end,
+			Partial: !start.Equal(fullStart) || !end.Equal(fullEnd) || fullEnd.After(now),
+		})
+		start = end
+	}
+	return buckets, nil
+}
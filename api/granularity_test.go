@@ -0,0 +1,173 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	logger "github.com/sirupsen/logrus"
+)
+
+func TestBuckets(t *testing.T) {
+	today, _ := time.Parse(dayFormat, time.Now().UTC().Format(dayFormat))
+	oldest := today.AddDate(0, 0, -MAX_PAST_DAYS_METRICS_DEFAULT_DAYS)
+
+	testCases := []struct {
+		name          string
+		from          time.Time
+		to            time.Time
+		granularity   Granularity
+		expectedCount int
+		expectedFrom  time.Time
+		expectedErr   error
+	}{
+		{
+			name:          "Time period is limited to the days with metrics",
+			from:          today.AddDate(-1, 0, 0),
+			to:            today,
+			granularity:   GRANULARITY_DAY,
+			expectedCount: MAX_PAST_DAYS_METRICS_DEFAULT_DAYS + 1,
+			expectedFrom:  oldest,
+		},
+		{
+			name:        "No buckets for a time period with no metrics",
+			from:        time.Date(1000, 1, 2, 0, 0, 0, 0, time.UTC),
+			to:          time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC),
+			granularity: GRANULARITY_DAY,
+		},
+		{
+			name:        "Invalid granularity is rejected for a time period with no metrics",
+			from:        time.Date(1000, 1, 2, 0, 0, 0, 0, time.UTC),
+			to:          time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC),
+			granularity: "year",
+			expectedErr: InvalidRequest,
+		},
+	}
+
+	relayMeter := NewRelayMeter(&fakeBackend{usage: fakeDailyMetrics(), todaysUsage: fakeTodaysMetrics()}, logger.New(), RelayMeterOptions{LoadInterval: 100 * time.Millisecond})
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := relayMeter.Buckets(tc.from, tc.to, tc.granularity)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("Expected error: %v, got: %v", tc.expectedErr, err)
+			}
+			if len(got) != tc.expectedCount {
+				t.Fatalf("Expected %d buckets, got: %d", tc.expectedCount, len(got))
+			}
+			if len(got) > 0 && !got[0].From.Equal(tc.expectedFrom) {
+				t.Errorf("Expected the first bucket to start on: %v, got: %v", tc.expectedFrom, got[0].From)
+			}
+		})
+	}
+}
+
+func TestSplitTimePeriod(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Unexpected error loading timezone: %v", err)
+	}
+	day := func(month time.Month, d int) time.Time { return time.Date(2022, month, d, 0, 0, 0, 0, time.UTC) }
+	// A Wednesday
+	now := time.Date(2022, time.July, 20, 15, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name        string
+		from        time.Time
+		to          time.Time
+		granularity Granularity
+		now         time.Time
+		expected    []RelaysBucket
+		expectedErr bool
+	}{
+		{
+			name:        "Daily buckets: today's bucket is partial",
+			from:        day(time.July, 18),
+			to:          day(time.July, 21),
+			granularity: GRANULARITY_DAY,
+			now:         now,
+			expected: []RelaysBucket{
+				{From: day(time.July, 18), To: day(time.July, 19)},
+				{From: day(time.July, 19), To: day(time.July, 20)},
+				{From: day(time.July, 20), To: day(time.July, 21), Partial: true},
+			},
+		},
+		{
+			name:        "Weekly buckets start on Monday: weeks at the edges of the time period are partial",
+			from:        day(time.July, 6),
+			to:          day(time.July, 21),
+			granularity: GRANULARITY_WEEK,
+			now:         now,
+			expected: []RelaysBucket{
+				{From: day(time.July, 6), To: day(time.July, 11), Partial: true},
+				{From: day(time.July, 11), To: day(time.July, 18)},
+				{From: day(time.July, 18), To: day(time.July, 21), Partial: true},
+			},
+		},
+		{
+			name:        "Monthly buckets are calendar months",
+			from:        day(time.May, 1),
+			to:          day(time.June, 16),
+			granularity: GRANULARITY_MONTH,
+			now:         now,
+			expected: []RelaysBucket{
+				{From: day(time.May, 1), To: day(time.June, 1)},
+				{From: day(time.June, 1), To: day(time.June, 16), Partial: true},
+			},
+		},
+		{
+			name:        "Monthly buckets across a daylight saving time change",
+			from:        time.Date(2022, time.March, 1, 0, 0, 0, 0, newYork),
+			to:          time.Date(2022, time.April, 1, 0, 0, 0, 0, newYork),
+			granularity: GRANULARITY_MONTH,
+			now:         now.In(newYork),
+			expected: []RelaysBucket{
+				{From: time.Date(2022, time.March, 1, 0, 0, 0, 0, newYork), To: time.Date(2022, time.April, 1, 0, 0, 0, 0, newYork)},
+			},
+		},
+		{
+			name:        "No buckets are returned for periods after today",
+			from:        day(time.July, 20),
+			to:          day(time.July, 26),
+			granularity: GRANULARITY_DAY,
+			now:         now,
+			expected: []RelaysBucket{
+				{From: day(time.July, 20), To: day(time.July, 21), Partial: true},
+			},
+		},
+		{
+			name:        "Invalid granularity returns an error",
+			from:        day(time.July, 18),
+			to:          day(time.July, 21),
+			granularity: Granularity("year"),
+			now:         now,
+			expectedErr: true,
+		},
+		{
+			name:        "Too many buckets returns an error",
+			from:        day(time.January, 1).AddDate(-2, 0, 0),
+			to:          day(time.July, 21),
+			granularity: GRANULARITY_DAY,
+			now:         now,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := splitTimePeriod(tc.from, tc.to, tc.granularity, tc.now)
+			if (err != nil) != tc.expectedErr {
+				t.Fatalf("Expected error: %v, got: %v", tc.expectedErr, err)
+			}
+			if err != nil {
+				if !errors.Is(err, InvalidRequest) {
+					t.Errorf("Expected an InvalidRequest error, got: %v", err)
+				}
+				return
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+)
+
+const (
+	HEALTH_PATH    = "/healthz"
+	READINESS_PATH = "/readyz"
+
+	HEALTH_STATUS_OK    = "ok"
+	HEALTH_STATUS_ERROR = "error"
+
+	// Checks taking longer are considered failed
+	HEALTH_CHECK_TIMEOUT = 5 * time.Second
+)
+
+// HealthCheck verifies a dependency of the process, e.g. a database, is available
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type HealthCheckResult struct {
+	Status string
+	Error  string `json:",omitempty"`
+}
+
+type HealthResponse struct {
+	Status string
+	Checks map[string]HealthCheckResult `json:",omitempty"`
+}
+
+// MeterCheck fails until the meter has successfully loaded its metrics: until then, the meter only returns empty responses.
+func MeterCheck(meter RelayMeter) HealthCheck {
+	return HealthCheck{
+		Name: "meter",
+		Check: func(_ context.Context) error {
+			if meter.LastUpdated().IsZero() {
+				return fmt.Errorf("Metrics have not been loaded yet")
+			}
+			return nil
+		},
+	}
+}
+
+// GetHealthHandler returns the handler of HEALTH_PATH: it reports the process is alive, and runs no checks.
+func GetHealthHandler(l *logger.Logger) func(w http.ResponseWriter, req *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		writeHealthResponse(l, w, http.StatusOK, HealthResponse{Status: HEALTH_STATUS_OK})
+	}
+}
+
+// GetReadinessHandler returns the handler of READINESS_PATH: it runs all the checks concurrently,
+//	and returns a 503 status code if any of them fails, with the result of each check in the response.
+func GetReadinessHandler(checks []HealthCheck, l *logger.Logger) func(w http.ResponseWriter, req *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithTimeout(req.Context(), HEALTH_CHECK_TIMEOUT)
+		defer cancel()
+
+		results := make([]HealthCheckResult, len(checks))
+		var wg sync.WaitGroup
+		for i, check := range checks {
+			wg.Add(1)
+			go func(i int, check HealthCheck) {
+				defer wg.Done()
+				results[i] = HealthCheckResult{Status: HEALTH_STATUS_OK}
+				if err := check.Check(ctx); err != nil {
+					results[i] = HealthCheckResult{Status: HEALTH_STATUS_ERROR, Error: err.Error()}
+				}
+			}(i, check)
+		}
+		wg.Wait()
+
+		resp := HealthResponse{Status: HEALTH_STATUS_OK, Checks: make(map[string]HealthCheckResult)}
+		for i, check := range checks {
+			resp.Checks[check.Name] = results[i]
+			if results[i].Status != HEALTH_STATUS_OK {
+				resp.Status = HEALTH_STATUS_ERROR
+			}
+		}
+
+		statusCode := http.StatusOK
+		if resp.Status != HEALTH_STATUS_OK {
+			l.WithFields(logger.Fields{"checks": resp.Checks}).Warn("Readiness check failed")
+			statusCode = http.StatusServiceUnavailable
+		}
+		writeHealthResponse(l, w, statusCode, resp)
+	}
+}
+
+func writeHealthResponse(l *logger.Logger, w http.ResponseWriter, statusCode int, resp HealthResponse) {
+	w.Header().Set(HEADER_CONTENT_TYPE, CONTENT_TYPE_JSON)
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		l.WithFields(logger.Fields{"error": err}).Warn("Error writing health response")
+	}
+}
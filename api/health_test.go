@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	logger "github.com/sirupsen/logrus"
+)
+
+func TestReadinessHandler(t *testing.T) {
+	okCheck := func(_ context.Context) error { return nil }
+	failedCheck := func(_ context.Context) error { return errors.New("connection refused") }
+
+	testCases := []struct {
+		name               string
+		checks             []HealthCheck
+		expectedStatusCode int
+		expected           HealthResponse
+	}{
+		{
+			name: "All checks pass",
+			checks: []HealthCheck{
+				{Name: "postgres", Check: okCheck},
+				MeterCheck(&fakeRelayMeter{lastUpdated: time.Now()}),
+			},
+			expectedStatusCode: http.StatusOK,
+			expected: HealthResponse{
+				Status: HEALTH_STATUS_OK,
+				Checks: map[string]HealthCheckResult{
+					"postgres": {Status: HEALTH_STATUS_OK},
+					"meter":    {Status: HEALTH_STATUS_OK},
+				},
+			},
+		},
+		{
+			name: "Unreachable dependency",
+			checks: []HealthCheck{
+				{Name: "postgres", Check: failedCheck},
+				MeterCheck(&fakeRelayMeter{lastUpdated: time.Now()}),
+			},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expected: HealthResponse{
+				Status: HEALTH_STATUS_ERROR,
+				Checks: map[string]HealthCheckResult{
+					"postgres": {Status: HEALTH_STATUS_ERROR, Error: "connection refused"},
+					"meter":    {Status: HEALTH_STATUS_OK},
+				},
+			},
+		},
+		{
+			name: "Metrics not loaded yet",
+			checks: []HealthCheck{
+				{Name: "postgres", Check: okCheck},
+				MeterCheck(&fakeRelayMeter{}),
+			},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expected: HealthResponse{
+				Status: HEALTH_STATUS_ERROR,
+				Checks: map[string]HealthCheckResult{
+					"postgres": {Status: HEALTH_STATUS_OK},
+					"meter":    {Status: HEALTH_STATUS_ERROR, Error: "Metrics have not been loaded yet"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://relay-meter.pokt.network"+READINESS_PATH, nil)
+			w := httptest.NewRecorder()
+			GetReadinessHandler(tc.checks, logger.New())(w, req)
+
+			resp := w.Result()
+			if resp.StatusCode != tc.expectedStatusCode {
+				t.Fatalf("Expected status code: %d, got: %d", tc.expectedStatusCode, resp.StatusCode)
+			}
+			var got HealthResponse
+			if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+				t.Fatalf("Unexpected error unmarhsalling the response: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
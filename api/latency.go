@@ -0,0 +1,58 @@
+package api
+
+import (
+	"time"
+)
+
+// RelayLatency holds the statistics on the latency, i.e. the elapsed time in seconds, of a set of relays.
+//	The statistics are calculated from the downsampled elapsed times of the relays, see Backend.DailyLatency:
+//	the percentiles are approximations, even for a single application on a single day.
+type RelayLatency struct {
+	// Number of relays the statistics were calculated on: used as the weight when combining statistics
+	Relays  int64
+	Average float64
+	P50     float64
+	P95     float64
+	P99     float64
+}
+
+// Add returns the combined statistics of the two sets of relays, weighted by their number of relays.
+//	The average is exact, but the percentiles of the combined set cannot be calculated from those of its parts:
+//	the relay-weighted average of the percentiles is returned as an approximation.
+func (l RelayLatency) Add(other RelayLatency) RelayLatency {
+	relays := l.Relays + other.Relays
+	if relays == 0 {
+		return RelayLatency{}
+	}
+
+	weighted := func(a, b float64) float64 {
+		return (a*float64(l.Relays) + b*float64(other.Relays)) / float64(relays)
+	}
+	return RelayLatency{
+		Relays:  relays,
+		Average: weighted(l.Average, other.Average),
+		P50:     weighted(l.P50, other.P50),
+		P95:     weighted(l.P95, other.P95),
+		P99:     weighted(l.P99, other.P99),
+	}
+}
+
+// latency returns the combined latency statistics of the applications over the specified time period, or nil if there are none.
+//	Latency statistics are only collected for past days: today's relays are not included.
+//	The caller is expected to hold the read lock.
+func (r *relayMeter) latency(apps []string, from, to time.Time) *RelayLatency {
+	var total RelayLatency
+	for day, latency := range r.dailyLatency {
+		// Note: Equal is not tested for 'to' parameter, as it is already adjusted to the start of the day after the specified date.
+		if (day.After(from) || day.Equal(from)) && day.Before(to) {
+			for _, app := range apps {
+				total = total.Add(latency[app])
+			}
+		}
+	}
+
+	if total.Relays == 0 {
+		return nil
+	}
+	return &total
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pokt-foundation/portal-api-go/repository"
+	logger "github.com/sirupsen/logrus"
+)
+
+func TestRelayLatencyAdd(t *testing.T) {
+	testCases := []struct {
+		name     string
+		latency  RelayLatency
+		other    RelayLatency
+		expected RelayLatency
+	}{
+		{
+			name:     "Statistics are weighted by the number of relays",
+			latency:  RelayLatency{Relays: 100, Average: 0.1, P50: 0.1, P95: 0.2, P99: 0.4},
+			other:    RelayLatency{Relays: 300, Average: 0.5, P50: 0.3, P95: 0.6, P99: 1.2},
+			expected: RelayLatency{Relays: 400, Average: 0.4, P50: 0.25, P95: 0.5, P99: 1.0},
+		},
+		{
+			name:     "Empty statistics do not change the result",
+			latency:  RelayLatency{Relays: 100, Average: 0.1, P50: 0.1, P95: 0.2, P99: 0.4},
+			expected: RelayLatency{Relays: 100, Average: 0.1, P50: 0.1, P95: 0.2, P99: 0.4},
+		},
+		{
+			name: "No relays",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.latency.Add(tc.other)
+			if diff := cmp.Diff(tc.expected, got, cmp.Comparer(func(a, b float64) bool { return a-b < 1e-9 && b-a < 1e-9 })); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRelaysLatency(t *testing.T) {
+	now, _ := time.Parse(dayFormat, time.Now().UTC().Format(dayFormat))
+	latency := map[time.Time]map[string]RelayLatency{
+		now.AddDate(0, 0, -2): {
+			"app1": {Relays: 10, Average: 0.2, P50: 0.1, P95: 0.4, P99: 0.8},
+			"app2": {Relays: 30, Average: 0.6, P50: 0.5, P95: 0.8, P99: 1.2},
+		},
+		now.AddDate(0, 0, -1): {
+			"app1": {Relays: 10, Average: 0.4, P50: 0.3, P95: 0.6, P99: 1.0},
+		},
+	}
+
+	fakeBackend := fakeBackend{
+		usage:       fakeDailyMetrics(),
+		todaysUsage: fakeTodaysMetrics(),
+		latency:     latency,
+		loadbalancers: map[string]*repository.LoadBalancer{
+			"lb1": {
+				Applications: []*repository.Application{
+					{GatewayAAT: repository.GatewayAAT{ApplicationPublicKey: "app1"}},
+					{GatewayAAT: repository.GatewayAAT{ApplicationPublicKey: "app2"}},
+				},
+			},
+		},
+	}
+	relayMeter := NewRelayMeter(&fakeBackend, logger.New(), RelayMeterOptions{LoadInterval: 100 * time.Millisecond})
+	time.Sleep(200 * time.Millisecond)
+
+	testCases := []struct {
+		name     string
+		from     time.Time
+		to       time.Time
+		relays   func(from, to time.Time) (*RelayLatency, error)
+		expected *RelayLatency
+	}{
+		{
+			name: "Application latency over several days",
+			from: now.AddDate(0, 0, -6),
+			to:   now,
+			relays: func(from, to time.Time) (*RelayLatency, error) {
+				resp, err := relayMeter.AppRelays("app1", from, to)
+				return resp.Latency, err
+			},
+			expected: &RelayLatency{Relays: 20, Average: 0.3, P50: 0.2, P95: 0.5, P99: 0.9},
+		},
+		{
+			name: "Application latency on a single day",
+			from: now.AddDate(0, 0, -1),
+			to:   now.AddDate(0, 0, -1),
+			relays: func(from, to time.Time) (*RelayLatency, error) {
+				resp, err := relayMeter.AppRelays("app1", from, to)
+				return resp.Latency, err
+			},
+			expected: &RelayLatency{Relays: 10, Average: 0.4, P50: 0.3, P95: 0.6, P99: 1.0},
+		},
+		{
+			name: "No latency for today",
+			from: now,
+			to:   now,
+			relays: func(from, to time.Time) (*RelayLatency, error) {
+				resp, err := relayMeter.AppRelays("app1", from, to)
+				return resp.Latency, err
+			},
+		},
+		{
+			name: "Load balancer latency combines all its applications",
+			from: now.AddDate(0, 0, -2),
+			to:   now.AddDate(0, 0, -2),
+			relays: func(from, to time.Time) (*RelayLatency, error) {
+				resp, err := relayMeter.LoadBalancerRelays("lb1", from, to)
+				return resp.Latency, err
+			},
+			expected: &RelayLatency{Relays: 40, Average: 0.5, P50: 0.4, P95: 0.7, P99: 1.1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.relays(tc.from, tc.to)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, got, cmp.Comparer(func(a, b float64) bool { return a-b < 1e-9 && b-a < 1e-9 })); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	PARAMETER_LIMIT     = "limit"
+	PARAMETER_CURSOR    = "cursor"
+	PARAMETER_SORT      = "sort"
+	PARAMETER_MIN_COUNT = "minCount"
+	PARAMETER_APPS      = "apps"
+	PARAMETER_ENDPOINTS = "endpoints"
+
+	// HEADER_NEXT_CURSOR holds the cursor to pass on the request for the next page: it is not set on the last page
+	HEADER_NEXT_CURSOR = "X-Next-Cursor"
+
+	SORT_BY_SUCCESS = "success"
+	SORT_BY_FAILURE = "failure"
+	SORT_BY_TOTAL   = "total"
+	// SORT_DESCENDING_PREFIX reverses the order of a sort field, e.g. "-total"
+	SORT_DESCENDING_PREFIX = "-"
+)
+
+// listOptions holds the pagination, sorting and filtering options of a list request, e.g. all applications' relays.
+type listOptions struct {
+	// Maximum number of items to return: 0 means no limit
+	limit int
+	// Position of the last item of the previous page
+	cursor *listCursor
+	// The count used for sorting items: items are sorted by key if empty
+	sortBy     string
+	descending bool
+	// Minimum number of total relays for an item to be included
+	minCount int64
+	// Keys of the items to include: all items are included if empty
+	keys map[string]bool
+}
+
+// listCursor marks the position of an item in a sorted list: the key breaks ties between items with equal sort values.
+type listCursor struct {
+	value int64
+	key   string
+}
+
+func (c listCursor) encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d,%s", c.value, c.key)))
+}
+
+func decodeListCursor(s string) (*listCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid %s parameter: %v", InvalidRequest, PARAMETER_CURSOR, err)
+	}
+	items := strings.SplitN(string(b), ",", 2)
+	if len(items) != 2 {
+		return nil, fmt.Errorf("%w: invalid %s parameter: %s", InvalidRequest, PARAMETER_CURSOR, s)
+	}
+	value, err := strconv.ParseInt(items[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid %s parameter: %v", InvalidRequest, PARAMETER_CURSOR, err)
+	}
+	return &listCursor{value: value, key: items[1]}, nil
+}
+
+// parseListOptions extracts the list options from the request's query parameters.
+//	keysParameter is the name of the query parameter holding the comma-separated list of keys to include, e.g. apps.
+func parseListOptions(req *http.Request, keysParameter string) (listOptions, error) {
+	var options listOptions
+	query := req.URL.Query()
+
+	if limit := query.Get(PARAMETER_LIMIT); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value < 0 {
+			return options, fmt.Errorf("%w: invalid %s parameter: %s", InvalidRequest, PARAMETER_LIMIT, limit)
+		}
+		options.limit = value
+	}
+
+	if cursor := query.Get(PARAMETER_CURSOR); cursor != "" {
+		c, err := decodeListCursor(cursor)
+		if err != nil {
+			return options, err
+		}
+		options.cursor = c
+	}
+
+	if sortBy := query.Get(PARAMETER_SORT); sortBy != "" {
+		options.descending = strings.HasPrefix(sortBy, SORT_DESCENDING_PREFIX)
+		options.sortBy = strings.TrimPrefix(sortBy, SORT_DESCENDING_PREFIX)
+		switch options.sortBy {
+		case SORT_BY_SUCCESS, SORT_BY_FAILURE, SORT_BY_TOTAL:
+		default:
+			return options, fmt.Errorf("%w: invalid %s parameter: %s", InvalidRequest, PARAMETER_SORT, sortBy)
+		}
+	}
+
+	if minCount := query.Get(PARAMETER_MIN_COUNT); minCount != "" {
+		value, err := strconv.ParseInt(minCount, 10, 64)
+		if err != nil {
+			return options, fmt.Errorf("%w: invalid %s parameter: %s", InvalidRequest, PARAMETER_MIN_COUNT, minCount)
+		}
+		options.minCount = value
+	}
+
+	if keys := query.Get(keysParameter); keys != "" {
+		options.keys = make(map[string]bool)
+		for _, k := range strings.Split(keys, ",") {
+			if k = strings.TrimSpace(k); k != "" {
+				options.keys[k] = true
+			}
+		}
+	}
+
+	return options, nil
+}
+
+// sortValue returns the value of the count used for sorting: it is always 0 when sorting by key.
+func (o listOptions) sortValue(counts RelayCounts) int64 {
+	switch o.sortBy {
+	case SORT_BY_SUCCESS:
+		return counts.Success
+	case SORT_BY_FAILURE:
+		return counts.Failure
+	case SORT_BY_TOTAL:
+		return counts.Total()
+	default:
+		return 0
+	}
+}
+
+// before reports whether the item at position a comes before the item at position b.
+//	Items with equal sort values are always sorted by key, in ascending order, so the order is stable across requests.
+func (o listOptions) before(a, b listCursor) bool {
+	if a.value != b.value {
+		if o.descending {
+			return a.value > b.value
+		}
+		return a.value < b.value
+	}
+	return a.key < b.key
+}
+
+// applyListOptions filters, sorts and paginates the items according to the options.
+//	The returned cursor is empty if there are no more items after the returned page.
+func applyListOptions[T any](items []T, key func(T) string, counts func(T) RelayCounts, options listOptions) ([]T, string) {
+	position := func(item T) listCursor {
+		return listCursor{value: options.sortValue(counts(item)), key: key(item)}
+	}
+
+	// Keep a nil list as is, so its serialization does not change
+	if items == nil {
+		return nil, ""
+	}
+
+	filtered := []T{}
+	for _, item := range items {
+		if len(options.keys) > 0 && !options.keys[key(item)] {
+			continue
+		}
+		if counts(item).Total() < options.minCount {
+			continue
+		}
+		if options.cursor != nil && !options.before(*options.cursor, position(item)) {
+			continue
+		}
+		filtered = append(filtered, item)
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		return options.before(position(filtered[i]), position(filtered[j]))
+	})
+
+	if options.limit == 0 || len(filtered) <= options.limit {
+		return filtered, ""
+	}
+	page := filtered[:options.limit]
+	return page, position(page[len(page)-1]).encode()
+}
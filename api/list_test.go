@@ -0,0 +1,152 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	logger "github.com/sirupsen/logrus"
+)
+
+func TestApplyListOptions(t *testing.T) {
+	apps := []AppRelaysResponse{
+		{Application: "app3", Count: RelayCounts{Success: 10, Failure: 1}},
+		{Application: "app1", Count: RelayCounts{Success: 5, Failure: 5}},
+		{Application: "app4", Count: RelayCounts{Success: 1, Failure: 0}},
+		{Application: "app2", Count: RelayCounts{Success: 7, Failure: 3}},
+	}
+
+	testCases := []struct {
+		name         string
+		query        string
+		expectedApps []string
+		expectedErr  bool
+	}{
+		{
+			name:         "Items are sorted by key by default",
+			expectedApps: []string{"app1", "app2", "app3", "app4"},
+		},
+		{
+			name:         "Items are sorted by the specified count, with ties sorted by key",
+			query:        "sort=total",
+			expectedApps: []string{"app4", "app1", "app2", "app3"},
+		},
+		{
+			name:         "Descending sort order",
+			query:        "sort=-success",
+			expectedApps: []string{"app3", "app2", "app1", "app4"},
+		},
+		{
+			name:         "Items are filtered by minimum count",
+			query:        "minCount=10",
+			expectedApps: []string{"app1", "app2", "app3"},
+		},
+		{
+			name:         "Items are filtered by key",
+			query:        "apps=app4,app2,app9",
+			expectedApps: []string{"app2", "app4"},
+		},
+		{
+			name:        "Invalid sort field is rejected",
+			query:       "sort=name",
+			expectedErr: true,
+		},
+		{
+			name:        "Invalid limit is rejected",
+			query:       "limit=-1",
+			expectedErr: true,
+		},
+		{
+			name:        "Invalid cursor is rejected",
+			query:       "cursor=invalid",
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://relay-meter.pokt.network/v0/relays/apps?"+tc.query, nil)
+			options, err := parseListOptions(req, PARAMETER_APPS)
+			if err != nil {
+				if !tc.expectedErr {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if tc.expectedErr {
+				t.Fatalf("Expected error, got nil")
+			}
+
+			got, cursor := applyListOptions(apps,
+				func(r AppRelaysResponse) string { return r.Application },
+				func(r AppRelaysResponse) RelayCounts { return r.Count },
+				options,
+			)
+			if cursor != "" {
+				t.Errorf("Expected no cursor, got: %s", cursor)
+			}
+
+			var gotApps []string
+			for _, app := range got {
+				gotApps = append(gotApps, app.Application)
+			}
+			if diff := cmp.Diff(tc.expectedApps, gotApps); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestListPagination(t *testing.T) {
+	fakeMeter := fakeRelayMeter{
+		allResponse: []AppRelaysResponse{
+			{Application: "app1", Count: RelayCounts{Success: 5, Failure: 5}},
+			{Application: "app2", Count: RelayCounts{Success: 7, Failure: 3}},
+			{Application: "app3", Count: RelayCounts{Success: 10, Failure: 1}},
+			{Application: "app4", Count: RelayCounts{Success: 1, Failure: 0}},
+			{Application: "app5", Count: RelayCounts{Success: 2, Failure: 2}},
+		},
+	}
+
+	var pages [][]string
+	cursor := ""
+	for i := 0; i < 5; i++ {
+		url := "http://relay-meter.pokt.network/v0/relays/apps?limit=2&sort=-total"
+		if cursor != "" {
+			url += "&cursor=" + cursor
+		}
+		req := httptest.NewRequest("GET", url, nil)
+		w := httptest.NewRecorder()
+
+		handleAllAppsRelays(&fakeMeter, logger.New(), w, req)
+
+		resp := w.Result()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status code: %d, got: %d", http.StatusOK, resp.StatusCode)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		var page []AppRelaysResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			t.Fatalf("Unexpected error unmarhsalling the response: %v", err)
+		}
+		var apps []string
+		for _, app := range page {
+			apps = append(apps, app.Application)
+		}
+		pages = append(pages, apps)
+
+		cursor = resp.Header.Get(HEADER_NEXT_CURSOR)
+		if cursor == "" {
+			break
+		}
+	}
+
+	expected := [][]string{{"app3", "app1"}, {"app2", "app5"}, {"app4"}}
+	if diff := cmp.Diff(expected, pages); diff != "" {
+		t.Errorf("unexpected value (-want +got):\n%s", diff)
+	}
+}
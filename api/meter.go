@@ -2,8 +2,13 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
 	"sync"
 	"time"
 
@@ -18,36 +23,201 @@ const (
 	// TODO: make all time-related parameters configurable
 	TTL_DAILY_METRICS_DEFAULT_SECONDS  = 900
 	TTL_TODAYS_METRICS_DEFAULT_SECONDS = 600
+	// Load balancers are cached for a short time, so a request split into buckets, see RelayMeter.Buckets, gets them from the backend once
+	TTL_LOAD_BALANCERS_SECONDS = 60
 
 	MAX_PAST_DAYS_METRICS_DEFAULT_DAYS = 30
+
+	TODAYS_METRICS_INTERVAL_DEFAULT_MINUTES = 60
+
+	TOP_APPS_DEFAULT_COUNT = 10
+	// Maximum number of applications in a BatchAppRelays request
+	MAX_BATCH_APPLICATIONS = 200
+	// Maximum number of days, i.e. entries, in an AppDailyRelays response: limits the work of a single request, as MAX_GRANULARITY_BUCKETS does
+	MAX_DAILY_RELAYS_DAYS = 400
+)
+
+// RankBy specifies the relay count used to rank applications
+type RankBy string
+
+const (
+	RANK_BY_SUCCESS      RankBy = "success"
+	RANK_BY_FAILURE      RankBy = "failure"
+	RANK_BY_TOTAL        RankBy = "total"
+	RANK_BY_FAILURE_RATE RankBy = "failure_rate"
 )
 
 var (
 	ErrLoadBalancerNotFound = errors.New("loadbalancer/endpoint not found")
+	ErrQuotaNotFound        = errors.New("quota not found")
+	// ErrInvalidTimespan is returned for invalid from or to parameters, e.g. from after to
+	ErrInvalidTimespan = fmt.Errorf("%w: Invalid timespan", InvalidRequest)
+	// ErrBackendUnavailable wraps the errors of backend calls made while processing a request, e.g. getting a user's applications
+	ErrBackendUnavailable = errors.New("backend unavailable")
 )
 
 type RelayMeter interface {
 	// AppRelays returns total number of relays for the app over the specified time period
 	AppRelays(app string, from, to time.Time) (AppRelaysResponse, error)
+	// AppDailyRelays returns the relays for the app over the specified time period, one entry per day
+	AppDailyRelays(app string, from, to time.Time) ([]AppRelaysResponse, error)
+	// AppTodaysRelays returns today's relays for the app so far, one entry per interval, e.g. hour, of today
+	AppTodaysRelays(app string) ([]AppRelaysResponse, error)
 	AllAppsRelays(from, to time.Time) ([]AppRelaysResponse, error)
+	// BatchAppRelays returns the relays of each of the requested applications, over the request's time period, consistently with each other
+	BatchAppRelays(requests []AppRelaysRequest) ([]AppRelaysResponse, error)
+	// TopAppsRelays returns the n applications with the highest relay counts over the specified time period, ranked using the 'by' parameter
+	TopAppsRelays(from, to time.Time, n int, by RankBy) ([]AppRelaysResponse, error)
 	UserRelays(user string, from, to time.Time) (UserRelaysResponse, error)
 	TotalRelays(from, to time.Time) (TotalRelaysResponse, error)
 	// LoadBalancerRelays returns the metrics for an Endpoint, AKA loadbalancer
 	LoadBalancerRelays(endpoint string, from, to time.Time) (LoadBalancerRelaysResponse, error)
 	AllLoadBalancersRelays(from, to time.Time) ([]LoadBalancerRelaysResponse, error)
+	// CompareAppRelays compares the relays of the app over the specified time period with the previous time period of equal length
+	CompareAppRelays(app string, from, to time.Time) (RelaysComparisonResponse, error)
+	CompareUserRelays(user string, from, to time.Time) (RelaysComparisonResponse, error)
+	CompareLoadBalancerRelays(endpoint string, from, to time.Time) (RelaysComparisonResponse, error)
+	CompareTotalRelays(from, to time.Time) (RelaysComparisonResponse, error)
+	// Buckets splits the time period into periods of the granularity, e.g. calendar months, to return the relays of each period separately
+	Buckets(from, to time.Time, granularity Granularity) ([]RelaysBucket, error)
+	// AppQuota returns the usage of the app against its daily and monthly relay limits
+	AppQuota(app string) (AppQuotaResponse, error)
+	// UserQuota returns the usage of all the user's apps against the user's daily and monthly relay limits
+	UserQuota(user string) (UserQuotaResponse, error)
+	// LastUpdated returns the time the metrics were last updated by the data loader: it is zero if no metrics have been loaded yet
+	LastUpdated() time.Time
 }
 
 type RelayCounts struct {
 	Success int64
+	// Failure is the number of failed relays: it includes all the failure categories below
 	Failure int64
+	// Breakdown of failed relays by category, when provided by the source of the metrics.
+	//	Metrics collected before the categories were introduced only have the Failure count.
+	ClientError int64
+	ServerError int64
+	Timeout     int64
+	Other       int64
+	// Chains is the breakdown of the counts by chain, i.e. blockchain ID, when provided by the source of the metrics.
+	//	If set, the counts of all chains add up to the above counts: relays with no chain information are under the empty chain ID.
+	Chains map[string]RelayCounts `json:",omitempty"`
+}
+
+// ChainRelayCounts returns the counts with a breakdown holding a single chain, i.e. all the relays are for the specified chain.
+func ChainRelayCounts(chain string, counts RelayCounts) RelayCounts {
+	counts.Chains = map[string]RelayCounts{chain: counts}
+	return counts
+}
+
+// ResultRelayCounts returns the counts for the specified number of relays, all with the same result, i.e. the HTTP status code of the relay.
+//	Any result, including a non-numeric one, is counted: results which do not match a known category are counted as Other.
+func ResultRelayCounts(result string, count int64) RelayCounts {
+	code, err := strconv.Atoi(result)
+	switch {
+	case err != nil:
+		return RelayCounts{Failure: count, Other: count}
+	case code >= 200 && code < 300:
+		return RelayCounts{Success: count}
+	case code == http.StatusRequestTimeout || code == http.StatusGatewayTimeout:
+		return RelayCounts{Failure: count, Timeout: count}
+	case code >= 400 && code < 500:
+		return RelayCounts{Failure: count, ClientError: count}
+	case code >= 500 && code < 600:
+		return RelayCounts{Failure: count, ServerError: count}
+	default:
+		return RelayCounts{Failure: count, Other: count}
+	}
+}
+
+// Add returns the sum of both counts.
+//	If only one of the counts has a breakdown by chain, the relays of the other one are added to the breakdown under the empty chain ID.
+//	Neither of the counts is modified.
+func (r RelayCounts) Add(other RelayCounts) RelayCounts {
+	sum := RelayCounts{
+		Success:     r.Success + other.Success,
+		Failure:     r.Failure + other.Failure,
+		ClientError: r.ClientError + other.ClientError,
+		ServerError: r.ServerError + other.ServerError,
+		Timeout:     r.Timeout + other.Timeout,
+		Other:       r.Other + other.Other,
+	}
+	if len(r.Chains) == 0 && len(other.Chains) == 0 {
+		return sum
+	}
+
+	sum.Chains = make(map[string]RelayCounts)
+	for _, counts := range []RelayCounts{r, other} {
+		if len(counts.Chains) == 0 {
+			if counts.Total() != 0 {
+				sum.Chains[""] = sum.Chains[""].Add(counts)
+			}
+			continue
+		}
+		for chain, chainCounts := range counts.Chains {
+			sum.Chains[chain] = sum.Chains[chain].Add(chainCounts)
+		}
+	}
+	return sum
+}
+
+// Total returns the total number of relays, i.e. successful and failed
+func (r RelayCounts) Total() int64 {
+	return r.Success + r.Failure
+}
+
+// FailureRatio returns the ratio of failed relays to total relays: it is 0 if there are no relays
+func (r RelayCounts) FailureRatio() float64 {
+	total := r.Total()
+	if total == 0 {
+		return 0
+	}
+	return float64(r.Failure) / float64(total)
+}
+
+// SuccessRatio returns the ratio of successful relays to total relays: it is 0 if there are no relays
+func (r RelayCounts) SuccessRatio() float64 {
+	total := r.Total()
+	if total == 0 {
+		return 0
+	}
+	return float64(r.Success) / float64(total)
+}
+
+// MarshalJSON adds the total and the ratios to the serialized counts, so API clients do not need to compute them.
+//	For an empty time period, i.e. no relays, the total and both ratios are 0: the ratios do not add up to 1 in this case.
+func (r RelayCounts) MarshalJSON() ([]byte, error) {
+	// Avoid infinite recursion: the alias type has no MarshalJSON method
+	type relayCounts RelayCounts
+	return json.Marshal(struct {
+		relayCounts
+		Total        int64
+		FailureRatio float64
+		SuccessRatio float64
+	}{
+		relayCounts:  relayCounts(r),
+		Total:        r.Total(),
+		FailureRatio: r.FailureRatio(),
+		SuccessRatio: r.SuccessRatio(),
+	})
 }
 
 // TODO: refactor common fields
 type AppRelaysResponse struct {
-	Count       RelayCounts
+	Count RelayCounts
+	// Latency statistics of the application's relays, if available: they are not available for a single chain
+	Latency     *RelayLatency `json:",omitempty"`
 	From        time.Time
 	To          time.Time
 	Application string
+	// Notes explain the changes made to the requested time period, e.g. a 'from' older than the oldest day with metrics
+	Notes []string `json:",omitempty"`
+}
+
+// AppRelaysRequest is an application in a BatchAppRelays request: zero times select the same defaults as AppRelays.
+type AppRelaysRequest struct {
+	Application string
+	From        time.Time
+	To          time.Time
 }
 
 type UserRelaysResponse struct {
@@ -56,20 +226,28 @@ type UserRelaysResponse struct {
 	To           time.Time
 	User         string
 	Applications []string
+	// Notes explain the changes made to the requested time period, e.g. a 'from' older than the oldest day with metrics
+	Notes []string `json:",omitempty"`
 }
 
 type TotalRelaysResponse struct {
 	Count RelayCounts
 	From  time.Time
 	To    time.Time
+	// Notes explain the changes made to the requested time period, e.g. a 'from' older than the oldest day with metrics
+	Notes []string `json:",omitempty"`
 }
 
 type LoadBalancerRelaysResponse struct {
-	Count        RelayCounts
+	Count RelayCounts
+	// Latency statistics of the relays of all the load balancer's applications, if available: they are not available for a single chain
+	Latency      *RelayLatency `json:",omitempty"`
 	From         time.Time
 	To           time.Time
 	Endpoint     string
 	Applications []string
+	// Notes explain the changes made to the requested time period, e.g. a 'from' older than the oldest day with metrics
+	Notes []string `json:",omitempty"`
 }
 
 type RelayMeterOptions struct {
@@ -77,17 +255,35 @@ type RelayMeterOptions struct {
 	DailyMetricsTTL  time.Duration
 	TodaysMetricsTTL time.Duration
 	MaxPastDays      time.Duration
+	// Length of the intervals today's metrics are split into: needs to match the collector's setting
+	TodaysMetricsInterval time.Duration
+	// Notifier, if set, is called with today's metrics after every refresh
+	Notifier Notifier
+	// Export today's relays of each application as Prometheus metrics: one time series per application
+	ExportAppMetrics bool
+	// Location is the reporting timezone: days, including today, start at midnight in this timezone. Defaults to UTC.
+	//	Needs to match the collector's setting.
+	Location *time.Location
 }
 
 type Backend interface {
 	//TODO: reverse map keys order, i.e. map[app]-> map[day]RelayCounts, at PG level
 	DailyUsage(from, to time.Time) (map[time.Time]map[string]RelayCounts, error)
 	TodaysUsage() (map[string]RelayCounts, error)
+	// TodaysIntervalUsage returns today's metrics so far, keyed by the start of each interval
+	TodaysIntervalUsage() (map[time.Time]map[string]RelayCounts, error)
+	// DailyLatency returns the latency statistics of each application, keyed by day.
+	//	The source of the metrics may only hold the elapsed times downsampled, e.g. the influx daily bucket, in which case the percentiles are approximations
+	DailyLatency(from, to time.Time) (map[time.Time]map[string]RelayLatency, error)
 	// Is expected to return the list of applicationIDs owned by the user
 	UserApps(user string) ([]string, error)
-	// LoadBalancer returns the full load balancer struct
+	// LoadBalancer returns the full load balancer struct: it is expected to return ErrLoadBalancerNotFound, possibly wrapped, for unknown endpoints
 	LoadBalancer(endpoint string) (*repository.LoadBalancer, error)
 	LoadBalancers() ([]*repository.LoadBalancer, error)
+	// AppQuota returns the relay limits of the application, or nil if the application has no quota
+	AppQuota(app string) (*Quota, error)
+	// UserQuota returns the relay limits of the user, or nil if the user has no quota
+	UserQuota(user string) (*Quota, error)
 }
 
 func NewRelayMeter(backend Backend, logger *logger.Logger, options RelayMeterOptions) RelayMeter {
@@ -106,16 +302,80 @@ type relayMeter struct {
 	Backend
 	*logger.Logger
 
-	dailyUsage  map[time.Time]map[string]RelayCounts
-	todaysUsage map[string]RelayCounts
+	dailyUsage          map[time.Time]map[string]RelayCounts
+	todaysUsage         map[string]RelayCounts
+	todaysIntervalUsage map[time.Time]map[string]RelayCounts
+	dailyLatency        map[time.Time]map[string]RelayLatency
+
+	dailyTTL    time.Time
+	todaysTTL   time.Time
+	lastUpdated time.Time
+	rwMutex     sync.RWMutex
 
-	dailyTTL  time.Time
-	todaysTTL time.Time
-	rwMutex   sync.RWMutex
+	// Load balancers, as returned by the backend, see loadBalancers
+	loadBalancers      []*repository.LoadBalancer
+	loadBalancersTTL   time.Time
+	loadBalancersMutex sync.Mutex
 
 	RelayMeterOptions
 }
 
+// location returns the reporting timezone.
+func (r *relayMeter) location() *time.Location {
+	if r.RelayMeterOptions.Location == nil {
+		return time.UTC
+	}
+	return r.RelayMeterOptions.Location
+}
+
+// adjustTimePeriod adjusts the time period to whole days of the reporting timezone, see AdjustTimePeriodIn.
+func (r *relayMeter) adjustTimePeriod(from, to time.Time) (time.Time, time.Time, error) {
+	return AdjustTimePeriodIn(from, to, r.location())
+}
+
+// clampTimePeriod adjusts the time period as done by adjustTimePeriod, and then limits it to the days with metrics, i.e. from the
+//	oldest day loaded, see RelayMeterOptions.MaxPastDays, to today. A note explaining each change is returned, so clients do not take
+//	days with no metrics for days with no relays. A time period with no days with metrics is returned unchanged, along with a note.
+func (r *relayMeter) clampTimePeriod(from, to time.Time) (time.Time, time.Time, []string, error) {
+	from, to, err := r.adjustTimePeriod(from, to)
+	if err != nil {
+		return from, to, nil, err
+	}
+
+	maxPastDays := maxArchiveAge(r.RelayMeterOptions.MaxPastDays)
+	oldest, tomorrow := r.metricsPeriod()
+	today := tomorrow.AddDate(0, 0, -1)
+
+	if !r.hasMetrics(from, to) {
+		return from, to, []string{fmt.Sprintf("No metrics are available for the time period: metrics are only available from %s to %s", oldest.Format(dayFormat), today.Format(dayFormat))}, nil
+	}
+
+	var notes []string
+	if from.Before(oldest) {
+		notes = append(notes, fmt.Sprintf("The time period starts on %s instead of %s: metrics are only kept for the past %d days", oldest.Format(dayFormat), from.Format(dayFormat), int(-maxPastDays.Hours()/24)))
+		from = oldest
+	}
+	if to.After(tomorrow) {
+		notes = append(notes, fmt.Sprintf("The time period ends on %s instead of %s: no metrics are available after today", today.Format(dayFormat), to.AddDate(0, 0, -1).Format(dayFormat)))
+		to = tomorrow
+	}
+	return from, to, notes, nil
+}
+
+// metricsPeriod returns the time period of the days with metrics: from the oldest day loaded, see RelayMeterOptions.MaxPastDays,
+//	to tomorrow, i.e. the start of the day after today.
+func (r *relayMeter) metricsPeriod() (time.Time, time.Time) {
+	now := time.Now().In(r.location())
+	oldest, tomorrow, _ := r.adjustTimePeriod(now.Add(maxArchiveAge(r.RelayMeterOptions.MaxPastDays)), now)
+	return oldest, tomorrow
+}
+
+// hasMetrics returns true if the time period, adjusted to whole days, includes at least one day with metrics, see metricsPeriod.
+func (r *relayMeter) hasMetrics(from, to time.Time) bool {
+	oldest, tomorrow := r.metricsPeriod()
+	return from.Before(tomorrow) && to.After(oldest)
+}
+
 func (r *relayMeter) isEmpty() bool {
 	r.rwMutex.RLock()
 	defer r.rwMutex.RUnlock()
@@ -123,13 +383,18 @@ func (r *relayMeter) isEmpty() bool {
 	return len(r.dailyUsage) == 0 || len(r.todaysUsage) == 0
 }
 
-// TODO: for now, today's data gets overwritten every time. If needed add todays metrics in intervals as they occur in the day
+// loadData loads the daily metrics and today's metrics from the backend, if they have expired.
+//	Today's metrics intervals are loaded alongside today's metrics, and share their TTL.
+//	Latency statistics are loaded alongside the daily metrics, and share their TTL.
+//	The notifier, if any, is called after today's metrics are refreshed.
 func (r *relayMeter) loadData(from, to time.Time) error {
 	var updateDaily, updateToday bool
 
 	now := time.Now()
 	var dailyUsage map[time.Time]map[string]RelayCounts
 	var todaysUsage map[string]RelayCounts
+	var todaysIntervalUsage map[time.Time]map[string]RelayCounts
+	var dailyLatency map[time.Time]map[string]RelayLatency
 	var err error
 	noDataYet := r.isEmpty()
 
@@ -142,6 +407,16 @@ func (r *relayMeter) loadData(from, to time.Time) error {
 			return err
 		}
 		r.Logger.WithFields(logger.Fields{"daily_metrics_count": len(dailyUsage)}).Info("Received daily metrics")
+		// Keyed by day in the reporting timezone, so days stored at midnight UTC, before the timezone was configurable, line up with the others
+		dailyUsage = byStoredDay(dailyUsage, r.location(), RelayCounts.Add)
+
+		dailyLatency, err = r.Backend.DailyLatency(from, to)
+		if err != nil {
+			r.Logger.WithFields(logger.Fields{"error": err}).Warn("Error loading daily latency data")
+			return err
+		}
+		r.Logger.WithFields(logger.Fields{"daily_latency_count": len(dailyLatency)}).Info("Received daily latency metrics")
+		dailyLatency = byStoredDay(dailyLatency, r.location(), RelayLatency.Add)
 	}
 
 	if noDataYet || now.After(r.todaysTTL) {
@@ -152,17 +427,35 @@ func (r *relayMeter) loadData(from, to time.Time) error {
 			return err
 		}
 		r.Logger.WithFields(logger.Fields{"todays_metrics_count": len(todaysUsage)}).Info("Received todays metrics")
+
+		todaysIntervalUsage, err = r.Backend.TodaysIntervalUsage()
+		if err != nil {
+			r.Logger.WithFields(logger.Fields{"error": err}).Warn("Error loading todays intervals usage data")
+			return err
+		}
+		r.Logger.WithFields(logger.Fields{"todays_intervals_count": len(todaysIntervalUsage)}).Info("Received todays metrics intervals")
 	}
 
 	if !updateDaily && !updateToday {
 		return nil
 	}
 
+	// Kept in the reporting timezone, so the start of the day it belongs to can be derived from it, see newCacheValidator
+	lastUpdated := time.Now().In(r.location())
+
+	// Deferred before acquiring the lock, so the notifier runs once the new metrics are in place and does not block readers.
+	//	The notifier does not block: e.g. the webhook notifier posts in the background
+	if updateToday && r.RelayMeterOptions.Notifier != nil {
+		defer r.RelayMeterOptions.Notifier.Notify(todaysUsage, lastUpdated)
+	}
+
 	r.rwMutex.Lock()
 	defer r.rwMutex.Unlock()
 
+	r.lastUpdated = lastUpdated
 	if updateDaily {
 		r.dailyUsage = dailyUsage
+		r.dailyLatency = dailyLatency
 		d := r.RelayMeterOptions.DailyMetricsTTL
 		if int(d.Seconds()) == 0 {
 			d = time.Duration(TTL_DAILY_METRICS_DEFAULT_SECONDS) * time.Second
@@ -171,59 +464,193 @@ func (r *relayMeter) loadData(from, to time.Time) error {
 	}
 	if updateToday {
 		r.todaysUsage = todaysUsage
+		r.todaysIntervalUsage = todaysIntervalUsage
 		d := r.RelayMeterOptions.TodaysMetricsTTL
 		if int(d.Seconds()) == 0 {
 			d = time.Duration(TTL_TODAYS_METRICS_DEFAULT_SECONDS) * time.Second
 		}
 		r.todaysTTL = time.Now().Add(d)
 	}
+	recordLoadedMetrics(updateDaily, updateToday, todaysUsage, r.RelayMeterOptions.ExportAppMetrics)
 	return nil
 }
 
+func (r *relayMeter) LastUpdated() time.Time {
+	r.rwMutex.RLock()
+	defer r.rwMutex.RUnlock()
+
+	return r.lastUpdated
+}
+
 // TODO: add a cache library, e.g. bigcache, if necessary (a cache library may not be needed, as we have a few thousand apps, for a maximum of 30 days)
 // Notes on To and From parameters:
 // Both parameters are assumed to be in the same timezone as the source of the data, i.e. influx
 //	The From parameter is taken to mean the very start of the day that it specifies: the returned result includes all such relays
 func (r *relayMeter) AppRelays(app string, from, to time.Time) (AppRelaysResponse, error) {
 	r.Logger.WithFields(logger.Fields{"app": app, "from": from, "to": to}).Info("apiserver: Received AppRelays request")
+
+	r.rwMutex.RLock()
+	defer r.rwMutex.RUnlock()
+
+	return r.appRelays(app, from, to)
+}
+
+// BatchAppRelays returns the relays of each application over its own time period, in the order of the requests.
+//	All the responses are calculated from the same metrics, i.e. under a single read lock, so they are consistent with each other.
+func (r *relayMeter) BatchAppRelays(requests []AppRelaysRequest) ([]AppRelaysResponse, error) {
+	r.Logger.WithFields(logger.Fields{"applications": len(requests)}).Info("apiserver: Received BatchAppRelays request")
+	if len(requests) > MAX_BATCH_APPLICATIONS {
+		return nil, fmt.Errorf("%w: at most %d applications are allowed in a batch, got: %d", InvalidRequest, MAX_BATCH_APPLICATIONS, len(requests))
+	}
+
+	r.rwMutex.RLock()
+	defer r.rwMutex.RUnlock()
+
+	resp := make([]AppRelaysResponse, 0, len(requests))
+	for _, req := range requests {
+		if req.Application == "" {
+			return nil, fmt.Errorf("%w: missing application in batch", InvalidRequest)
+		}
+		appResp, err := r.appRelays(req.Application, req.From, req.To)
+		if err != nil {
+			// The error is wrapped, so it keeps its error code, e.g. ErrInvalidTimespan
+			return nil, fmt.Errorf("application %s: %w", req.Application, err)
+		}
+		resp = append(resp, appResp)
+	}
+	return resp, nil
+}
+
+// appRelays returns the relays of the app over the time period: the caller is expected to hold the read lock.
+func (r *relayMeter) appRelays(app string, from, to time.Time) (AppRelaysResponse, error) {
 	resp := AppRelaysResponse{
 		From:        from,
 		To:          to,
 		Application: app,
 	}
 
-	// TODO: enforce MaxArchiveAge on From parameter
-	// TODO: enforce Today as maximum value for To parameter
-	from, to, err := AdjustTimePeriod(from, to)
+	from, to, notes, err := r.clampTimePeriod(from, to)
 	if err != nil {
 		return resp, err
 	}
 
 	// Get today's date in day-only format
-	now := time.Now()
-	_, today, _ := AdjustTimePeriod(now, now)
-
-	r.rwMutex.RLock()
-	defer r.rwMutex.RUnlock()
+	now := time.Now().In(r.location())
+	_, today, _ := r.adjustTimePeriod(now, now)
 
 	var total RelayCounts
 	for day, counts := range r.dailyUsage {
 		// Note: Equal is not tested for 'to' parameter, as it is already adjusted to the start of the day after the specified date.
 		if (day.After(from) || day.Equal(from)) && day.Before(to) {
-			total.Success += counts[app].Success
-			total.Failure += counts[app].Failure
+			total = total.Add(counts[app])
 		}
 	}
 
-	// TODO: Add a 'Notes' []string field to output: to provide an explanation when the input 'from' or 'to' parameters are corrected.
 	if today.Equal(to) || today.Before(to) {
-		total.Success += r.todaysUsage[app].Success
-		total.Failure += r.todaysUsage[app].Failure
+		total = total.Add(r.todaysUsage[app])
 	}
 
 	resp.Count = total
+	resp.Latency = r.latency([]string{app}, from, to)
 	resp.From = from
 	resp.To = to
+	resp.Notes = notes
+
+	return resp, nil
+}
+
+// AppDailyRelays returns the relays for the app over the specified time period, one entry per day.
+//	Every entry holds the notes on the changes made to the time period, see clampTimePeriod.
+//	Days with no relays have an entry with zero counts. Today's entry, if included in the time period, holds today's relays so far.
+//	No entries are returned for days after today, nor if the time period has no days with metrics.
+//	Errors wrap InvalidRequest if the time period has more than MAX_DAILY_RELAYS_DAYS days with metrics.
+func (r *relayMeter) AppDailyRelays(app string, from, to time.Time) ([]AppRelaysResponse, error) {
+	r.Logger.WithFields(logger.Fields{"app": app, "from": from, "to": to}).Info("apiserver: Received AppDailyRelays request")
+
+	from, to, notes, err := r.clampTimePeriod(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if !r.hasMetrics(from, to) {
+		return []AppRelaysResponse{}, nil
+	}
+	if days := daysBetween(from, to); days > MAX_DAILY_RELAYS_DAYS {
+		return nil, fmt.Errorf("%w: the time period has %d days, more than the maximum of %d", InvalidRequest, days, MAX_DAILY_RELAYS_DAYS)
+	}
+
+	// Get today's date in day-only format
+	now := time.Now().In(r.location())
+	_, today, _ := r.adjustTimePeriod(now, now)
+	todayStart := today.AddDate(0, 0, -1)
+
+	resp := []AppRelaysResponse{}
+	for day := from; day.Before(to) && day.Before(today); day = day.AddDate(0, 0, 1) {
+		resp = append(resp, AppRelaysResponse{
+			From:        day,
+			To:          day.AddDate(0, 0, 1),
+			Application: app,
+			Notes:       notes,
+		})
+	}
+
+	r.rwMutex.RLock()
+	defer r.rwMutex.RUnlock()
+
+	for day, counts := range r.dailyUsage {
+		// Note: Equal is not tested for 'to' parameter, as it is already adjusted to the start of the day after the specified date.
+		if (day.After(from) || day.Equal(from)) && day.Before(to) && day.Before(todayStart) {
+			// Days are keyed by their start in the reporting timezone, see byStoredDay: checked anyway, as an index out of range panics
+			if i := daysBetween(from, day); i >= 0 && i < len(resp) {
+				resp[i].Count = resp[i].Count.Add(counts[app])
+			}
+		}
+	}
+
+	// The last entry is today's, if the time period includes today
+	if len(resp) > 0 && (today.Equal(to) || today.Before(to)) {
+		i := len(resp) - 1
+		resp[i].Count = resp[i].Count.Add(r.todaysUsage[app])
+	}
+
+	return resp, nil
+}
+
+// AppTodaysRelays returns today's relays for the app so far, one entry per interval.
+//	The entries start at the beginning of today and end with the interval holding the current time. Intervals with no relays have an entry with zero counts.
+func (r *relayMeter) AppTodaysRelays(app string) ([]AppRelaysResponse, error) {
+	r.Logger.WithFields(logger.Fields{"app": app}).Info("apiserver: Received AppTodaysRelays request")
+
+	interval := r.RelayMeterOptions.TodaysMetricsInterval
+	if interval == 0 {
+		interval = time.Duration(TODAYS_METRICS_INTERVAL_DEFAULT_MINUTES) * time.Minute
+	}
+
+	now := time.Now().In(r.location())
+	todayStart, _, err := r.adjustTimePeriod(now, now)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := []AppRelaysResponse{}
+	for start := todayStart; start.Before(now); start = start.Add(interval) {
+		resp = append(resp, AppRelaysResponse{
+			From:        start,
+			To:          start.Add(interval),
+			Application: app,
+		})
+	}
+
+	r.rwMutex.RLock()
+	defer r.rwMutex.RUnlock()
+
+	for start, counts := range r.todaysIntervalUsage {
+		// Intervals from previous days may still be present until the collector's next run
+		if start.Before(todayStart) || !start.Before(now) {
+			continue
+		}
+		i := int(start.Sub(todayStart) / interval)
+		resp[i].Count = resp[i].Count.Add(counts[app])
+	}
 
 	return resp, nil
 }
@@ -231,16 +658,14 @@ func (r *relayMeter) AppRelays(app string, from, to time.Time) (AppRelaysRespons
 func (r *relayMeter) AllAppsRelays(from, to time.Time) ([]AppRelaysResponse, error) {
 	r.Logger.WithFields(logger.Fields{"from": from, "to": to}).Info("apiserver: Received AllAppRelays request")
 
-	// TODO: enforce MaxArchiveAge on From parameter
-	// TODO: enforce Today as maximum value for To parameter
-	from, to, err := AdjustTimePeriod(from, to)
+	from, to, notes, err := r.clampTimePeriod(from, to)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get today's date in day-only format
-	now := time.Now()
-	_, today, _ := AdjustTimePeriod(now, now)
+	now := time.Now().In(r.location())
+	_, today, _ := r.adjustTimePeriod(now, now)
 
 	r.rwMutex.RLock()
 	defer r.rwMutex.RUnlock()
@@ -253,8 +678,7 @@ func (r *relayMeter) AllAppsRelays(from, to time.Time) ([]AppRelaysResponse, err
 
 			// Note: Equal is not tested for 'to' parameter, as it is already adjusted to the start of the day after the specified date.
 			if (day.After(from) || day.Equal(from)) && day.Before(to) {
-				total.Success += relCounts.Success
-				total.Failure += relCounts.Failure
+				total = total.Add(relCounts)
 			}
 
 			rawResp[pubKey] = AppRelaysResponse{
@@ -262,23 +686,23 @@ func (r *relayMeter) AllAppsRelays(from, to time.Time) ([]AppRelaysResponse, err
 				From:        from,
 				To:          to,
 				Count:       total,
+				Notes:       notes,
 			}
 		}
 	}
 
-	// TODO: Add a 'Notes' []string field to output: to provide an explanation when the input 'from' or 'to' parameters are corrected.
 	if today.Equal(to) || today.Before(to) {
 		for pubKey, relCounts := range r.todaysUsage {
 			total := rawResp[pubKey].Count
 
-			total.Success += relCounts.Success
-			total.Failure += relCounts.Failure
+			total = total.Add(relCounts)
 
 			rawResp[pubKey] = AppRelaysResponse{
 				Application: pubKey,
 				From:        from,
 				To:          to,
 				Count:       total,
+				Notes:       notes,
 			}
 		}
 	}
@@ -288,10 +712,72 @@ func (r *relayMeter) AllAppsRelays(from, to time.Time) ([]AppRelaysResponse, err
 	for _, relResp := range rawResp {
 		resp = append(resp, relResp)
 	}
+	// Sort by application public key, so the order is the same on every call
+	sort.Slice(resp, func(i, j int) bool { return resp[i].Application < resp[j].Application })
 
 	return resp, nil
 }
 
+// TopAppsRelays returns the n applications with the highest relay counts over the specified time period: see topApps.
+func (r *relayMeter) TopAppsRelays(from, to time.Time, n int, by RankBy) ([]AppRelaysResponse, error) {
+	r.Logger.WithFields(logger.Fields{"from": from, "to": to, "n": n, "by": by}).Info("apiserver: Received TopAppsRelays request")
+
+	apps, err := r.AllAppsRelays(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return topApps(apps, n, by)
+}
+
+// topApps returns the n applications with the highest relay counts.
+//	Applications are sorted in descending order of the count specified by the 'by' parameter, with ties sorted by application public key.
+//	The apps slice is sorted in place.
+func topApps(apps []AppRelaysResponse, n int, by RankBy) ([]AppRelaysResponse, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("%w: invalid number of applications: %d", InvalidRequest, n)
+	}
+	if n == 0 {
+		n = TOP_APPS_DEFAULT_COUNT
+	}
+	if by == "" {
+		by = RANK_BY_TOTAL
+	}
+
+	rank, err := rankFunc(by)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(apps, func(i, j int) bool {
+		ri, rj := rank(apps[i].Count), rank(apps[j].Count)
+		if ri != rj {
+			return ri > rj
+		}
+		return apps[i].Application < apps[j].Application
+	})
+
+	if len(apps) > n {
+		apps = apps[:n]
+	}
+	return apps, nil
+}
+
+// rankFunc returns the function extracting the value specified by the 'by' parameter from relay counts
+func rankFunc(by RankBy) (func(RelayCounts) float64, error) {
+	switch by {
+	case RANK_BY_SUCCESS:
+		return func(c RelayCounts) float64 { return float64(c.Success) }, nil
+	case RANK_BY_FAILURE:
+		return func(c RelayCounts) float64 { return float64(c.Failure) }, nil
+	case RANK_BY_TOTAL:
+		return func(c RelayCounts) float64 { return float64(c.Total()) }, nil
+	case RANK_BY_FAILURE_RATE:
+		return func(c RelayCounts) float64 { return c.FailureRatio() }, nil
+	default:
+		return nil, fmt.Errorf("%w: invalid ranking: %s", InvalidRequest, by)
+	}
+}
+
 // TODO: refactor the common processing done by both AppRelays and UserRelays
 func (r *relayMeter) UserRelays(user string, from, to time.Time) (UserRelaysResponse, error) {
 	r.Logger.WithFields(logger.Fields{"user": user, "from": from, "to": to}).Info("apiserver: Received UserRelays request")
@@ -301,21 +787,19 @@ func (r *relayMeter) UserRelays(user string, from, to time.Time) (UserRelaysResp
 		User: user,
 	}
 
-	// TODO: enforce MaxArchiveAge on From parameter
-	// TODO: enforce Today as maximum value for To parameter
-	from, to, err := AdjustTimePeriod(from, to)
+	from, to, notes, err := r.clampTimePeriod(from, to)
 	if err != nil {
 		return resp, err
 	}
 
 	// Get today's date in day-only format
-	now := time.Now()
-	_, today, _ := AdjustTimePeriod(now, now)
+	now := time.Now().In(r.location())
+	_, today, _ := r.adjustTimePeriod(now, now)
 
 	apps, err := r.Backend.UserApps(user)
 	if err != nil {
 		r.Logger.WithFields(logger.Fields{"user": user, "from": from, "to": to, "error": err}).Warn("Error getting user applications processing UserRelays request")
-		return resp, err
+		return resp, fmt.Errorf("%w: %v", ErrBackendUnavailable, err)
 	}
 
 	r.rwMutex.RLock()
@@ -326,23 +810,21 @@ func (r *relayMeter) UserRelays(user string, from, to time.Time) (UserRelaysResp
 		// Note: Equal is not tested for 'to' parameter, as it is already adjusted to the start of the day after the specified date.
 		if (day.After(from) || day.Equal(from)) && day.Before(to) {
 			for _, app := range apps {
-				total.Success += counts[app].Success
-				total.Failure += counts[app].Failure
+				total = total.Add(counts[app])
 			}
 		}
 	}
 
-	// TODO: Add a 'Notes' []string field to output: to provide an explanation when the input 'from' or 'to' parameters are corrected.
 	if today.Equal(to) || today.Before(to) {
 		for _, app := range apps {
-			total.Success += r.todaysUsage[app].Success
-			total.Failure += r.todaysUsage[app].Failure
+			total = total.Add(r.todaysUsage[app])
 		}
 	}
 
 	resp.Count = total
 	resp.From = from
 	resp.To = to
+	resp.Notes = notes
 	resp.Applications = apps
 
 	return resp, nil
@@ -355,16 +837,14 @@ func (r *relayMeter) TotalRelays(from, to time.Time) (TotalRelaysResponse, error
 		To:   to,
 	}
 
-	// TODO: enforce MaxArchiveAge on From parameter
-	// TODO: enforce Today as maximum value for To parameter
-	from, to, err := AdjustTimePeriod(from, to)
+	from, to, notes, err := r.clampTimePeriod(from, to)
 	if err != nil {
 		return resp, err
 	}
 
 	// Get today's date in day-only format
-	now := time.Now()
-	_, today, _ := AdjustTimePeriod(now, now)
+	now := time.Now().In(r.location())
+	_, today, _ := r.adjustTimePeriod(now, now)
 
 	r.rwMutex.RLock()
 	defer r.rwMutex.RUnlock()
@@ -374,23 +854,21 @@ func (r *relayMeter) TotalRelays(from, to time.Time) (TotalRelaysResponse, error
 		// Note: Equal is not tested for 'to' parameter, as it is already adjusted to the start of the day after the specified date.
 		if (day.After(from) || day.Equal(from)) && day.Before(to) {
 			for _, count := range counts {
-				total.Success += count.Success
-				total.Failure += count.Failure
+				total = total.Add(count)
 			}
 		}
 	}
 
-	// TODO: Add a 'Notes' []string field to output: to provide an explanation when the input 'from' or 'to' parameters are corrected.
 	if today.Equal(to) || today.Before(to) {
 		for _, count := range r.todaysUsage {
-			total.Success += count.Success
-			total.Failure += count.Failure
+			total = total.Add(count)
 		}
 	}
 
 	resp.Count = total
 	resp.From = from
 	resp.To = to
+	resp.Notes = notes
 
 	return resp, nil
 }
@@ -404,21 +882,22 @@ func (r *relayMeter) LoadBalancerRelays(endpoint string, from, to time.Time) (Lo
 		Endpoint: endpoint,
 	}
 
-	// TODO: enforce MaxArchiveAge on From parameter
-	// TODO: enforce Today as maximum value for To parameter
-	from, to, err := AdjustTimePeriod(from, to)
+	from, to, notes, err := r.clampTimePeriod(from, to)
 	if err != nil {
 		return resp, err
 	}
 
 	// Get today's date in day-only format
-	now := time.Now()
-	_, today, _ := AdjustTimePeriod(now, now)
+	now := time.Now().In(r.location())
+	_, today, _ := r.adjustTimePeriod(now, now)
 
 	lb, err := r.Backend.LoadBalancer(endpoint)
+	if errors.Is(err, ErrLoadBalancerNotFound) {
+		return resp, ErrLoadBalancerNotFound
+	}
 	if err != nil {
 		r.Logger.WithFields(logger.Fields{"endpoint": endpoint, "from": from, "to": to, "error": err}).Warn("Error getting endpoint/loadbalancer applications processing LoadBalancerRelays request")
-		return resp, err
+		return resp, fmt.Errorf("%w: %v", ErrBackendUnavailable, err)
 	}
 	if lb == nil {
 		return resp, ErrLoadBalancerNotFound
@@ -440,23 +919,22 @@ func (r *relayMeter) LoadBalancerRelays(endpoint string, from, to time.Time) (Lo
 		// Note: Equal is not tested for 'to' parameter, as it is already adjusted to the start of the day after the specified date.
 		if (day.After(from) || day.Equal(from)) && day.Before(to) {
 			for _, app := range apps {
-				total.Success += counts[app].Success
-				total.Failure += counts[app].Failure
+				total = total.Add(counts[app])
 			}
 		}
 	}
 
-	// TODO: Add a 'Notes' []string field to output: to provide an explanation when the input 'from' or 'to' parameters are corrected.
 	if today.Equal(to) || today.Before(to) {
 		for _, app := range apps {
-			total.Success += r.todaysUsage[app].Success
-			total.Failure += r.todaysUsage[app].Failure
+			total = total.Add(r.todaysUsage[app])
 		}
 	}
 
 	resp.Count = total
+	resp.Latency = r.latency(apps, from, to)
 	resp.From = from
 	resp.To = to
+	resp.Notes = notes
 	resp.Applications = apps
 
 	return resp, nil
@@ -466,21 +944,19 @@ func (r *relayMeter) LoadBalancerRelays(endpoint string, from, to time.Time) (Lo
 func (r *relayMeter) AllLoadBalancersRelays(from, to time.Time) ([]LoadBalancerRelaysResponse, error) {
 	r.Logger.WithFields(logger.Fields{"from": from, "to": to}).Info("apiserver: Received AllLoadBalancerRelays request")
 
-	// TODO: enforce MaxArchiveAge on From parameter
-	// TODO: enforce Today as maximum value for To parameter
-	from, to, err := AdjustTimePeriod(from, to)
+	from, to, notes, err := r.clampTimePeriod(from, to)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get today's date in day-only format
-	now := time.Now()
-	_, today, _ := AdjustTimePeriod(now, now)
+	now := time.Now().In(r.location())
+	_, today, _ := r.adjustTimePeriod(now, now)
 
-	lbs, err := r.Backend.LoadBalancers()
+	lbs, err := r.cachedLoadBalancers()
 	if err != nil {
 		r.Logger.WithFields(logger.Fields{"from": from, "to": to, "error": err}).Warn("Error getting endpoint/loadbalancers applications processing AllLoadBalancerRelays request")
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", ErrBackendUnavailable, err)
 	}
 
 	r.rwMutex.RLock()
@@ -503,8 +979,7 @@ func (r *relayMeter) AllLoadBalancersRelays(from, to time.Time) ([]LoadBalancerR
 			// Note: Equal is not tested for 'to' parameter, as it is already adjusted to the start of the day after the specified date.
 			if (day.After(from) || day.Equal(from)) && day.Before(to) {
 				for _, app := range apps {
-					total.Success += counts[app].Success
-					total.Failure += counts[app].Failure
+					total = total.Add(counts[app])
 				}
 			}
 
@@ -514,11 +989,11 @@ func (r *relayMeter) AllLoadBalancersRelays(from, to time.Time) ([]LoadBalancerR
 				To:           to,
 				Count:        total,
 				Applications: apps,
+				Notes:        notes,
 			}
 		}
 	}
 
-	// TODO: Add a 'Notes' []string field to output: to provide an explanation when the input 'from' or 'to' parameters are corrected.
 	if today.Equal(to) || today.Before(to) {
 		for _, lb := range lbs {
 			total := rawResp[lb.ID].Count
@@ -532,8 +1007,7 @@ func (r *relayMeter) AllLoadBalancersRelays(from, to time.Time) ([]LoadBalancerR
 			}
 
 			for _, app := range apps {
-				total.Success += r.todaysUsage[app].Success
-				total.Failure += r.todaysUsage[app].Failure
+				total = total.Add(r.todaysUsage[app])
 			}
 
 			rawResp[lb.ID] = LoadBalancerRelaysResponse{
@@ -542,6 +1016,7 @@ func (r *relayMeter) AllLoadBalancersRelays(from, to time.Time) ([]LoadBalancerR
 				To:           to,
 				Count:        total,
 				Applications: apps,
+				Notes:        notes,
 			}
 		}
 	}
@@ -551,24 +1026,50 @@ func (r *relayMeter) AllLoadBalancersRelays(from, to time.Time) ([]LoadBalancerR
 	for _, relResp := range rawResp {
 		resp = append(resp, relResp)
 	}
+	// Sort by endpoint ID, so the order is the same on every call
+	sort.Slice(resp, func(i, j int) bool { return resp[i].Endpoint < resp[j].Endpoint })
 
 	return resp, nil
 }
 
+// cachedLoadBalancers returns the backend's load balancers, cached for TTL_LOAD_BALANCERS_SECONDS.
+//	Concurrent calls wait for a single backend call. Errors are not cached.
+func (r *relayMeter) cachedLoadBalancers() ([]*repository.LoadBalancer, error) {
+	r.loadBalancersMutex.Lock()
+	defer r.loadBalancersMutex.Unlock()
+
+	now := time.Now()
+	if now.Before(r.loadBalancersTTL) {
+		return r.loadBalancers, nil
+	}
+
+	lbs, err := r.Backend.LoadBalancers()
+	if err != nil {
+		return nil, err
+	}
+	r.loadBalancers = lbs
+	r.loadBalancersTTL = now.Add(TTL_LOAD_BALANCERS_SECONDS * time.Second)
+	return lbs, nil
+}
+
 // Starts a data loader in a go routine, to periodically load data from the backend
 // 	context allows stopping the data loader
 func (r *relayMeter) StartDataLoader(ctx context.Context) {
 	maxPastDays := maxArchiveAge(r.RelayMeterOptions.MaxPastDays)
 
 	load := func(max time.Duration) {
-		from := time.Now().Add(max)
-		from, to, err := AdjustTimePeriod(from, time.Now())
+		now := time.Now().In(r.location())
+		from, to, err := r.adjustTimePeriod(now.Add(max), now)
 		if err != nil {
 			r.Logger.WithFields(logger.Fields{"error": err}).Warn("Error setting timespan for data loader")
 			return
 		}
 		r.Logger.WithFields(logger.Fields{"from": from, "to": to}).Info("Starting data loader...")
-		if err := r.loadData(from, to); err != nil {
+		start := time.Now()
+		err = r.loadData(from, to)
+		loaderDuration.Observe(time.Since(start).Seconds())
+		if err != nil {
+			loaderErrors.Inc()
 			r.Logger.WithFields(logger.Fields{"error": err}).Warn("Error setting timespan for data loader")
 		}
 	}
@@ -595,46 +1096,72 @@ func (r *relayMeter) StartDataLoader(ctx context.Context) {
 	}
 }
 
-// AdjustTimePeriod sets the two parameters, i.e. from and to, according to the following rules:
-//	- From is adjusted to the start of the day that it originally specifies
-//	- To is adjusted to the start of the next day from the day it originally specifies
+// AdjustTimePeriod adjusts the time period as done by AdjustTimePeriodIn, using UTC as the reporting timezone.
 func AdjustTimePeriod(from, to time.Time) (time.Time, time.Time, error) {
+	return AdjustTimePeriodIn(from, to, time.UTC)
+}
 
-	// TODO: refactor: there is some duplication in the function
-	getDefault := func(parameter time.Time, defaultValue time.Time) (time.Time, error) {
-		if parameter.Equal(time.Time{}) {
-			return time.Parse(dayFormat, defaultValue.Format(dayFormat))
-		}
-		return parameter, nil
-	}
-
-	var err error
-	// TODO: set default from parameter to the actual MaxPastDays passed to the meter, i.e. r.RelayMeterOptions.MaxPastDays
-	from, err = getDefault(from, time.Now().Add(-24*time.Hour*time.Duration(MAX_PAST_DAYS_METRICS_DEFAULT_DAYS)))
-	if err != nil {
-		return time.Time{}, time.Time{}, err
+// AdjustTimePeriodIn sets the two parameters, i.e. from and to, according to the following rules:
+//	- From is adjusted to the start of the day that it originally specifies
+//	- To is adjusted to the start of the next day from the day it originally specifies
+//	The day specified by a parameter is its date in its own location, e.g. 2022-06-25T23:00:00-05:00 specifies 2022-06-25:
+//	the adjusted parameters are the start of that date in the reporting timezone, i.e. loc.
+//	Missing parameters default to dates in the reporting timezone: MAX_PAST_DAYS_METRICS_DEFAULT_DAYS ago for from, and today for to.
+func AdjustTimePeriodIn(from, to time.Time, loc *time.Location) (time.Time, time.Time, error) {
+	now := time.Now().In(loc)
+	if from.IsZero() {
+		// TODO: set default from parameter to the actual MaxPastDays passed to the meter, i.e. r.RelayMeterOptions.MaxPastDays
+		from = now.Add(-24 * time.Hour * time.Duration(MAX_PAST_DAYS_METRICS_DEFAULT_DAYS))
 	}
-
 	// Missing 'to' is set to include today
-	to, err = getDefault(to, time.Now())
-	if err != nil {
-		return time.Time{}, time.Time{}, err
+	if to.IsZero() {
+		to = now
 	}
 
 	if !from.Before(to) && !from.Equal(to) {
-		return time.Time{}, time.Time{}, fmt.Errorf("Invalid timespan: %v -- %v", from, to)
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: %v -- %v", ErrInvalidTimespan, from, to)
 	}
 
-	from, err = time.Parse(dayFormat, from.Format(dayFormat))
-	if err != nil {
-		return time.Time{}, time.Time{}, err
+	return startOfDay(from, loc), startOfDay(to, loc).AddDate(0, 0, 1), nil
+}
+
+// startOfDay returns the start, in the specified location, of the date of t in its own location.
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
+// StoredDay returns the day, i.e. its start in the reporting timezone, of a day of metrics as stored by the collector.
+//	Days are stored at midnight in the reporting timezone, except for those collected before the reporting timezone was configurable,
+//	which are stored at midnight UTC: those are returned as the same date in the reporting timezone, i.e. the day they mostly overlap.
+func StoredDay(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	if utc := t.UTC(); !local.Equal(startOfDay(local, loc)) && utc.Equal(startOfDay(utc, time.UTC)) {
+		return startOfDay(utc, loc)
 	}
+	return startOfDay(local, loc)
+}
 
-	to, err = time.Parse(dayFormat, to.Format(dayFormat))
-	if err != nil {
-		return time.Time{}, time.Time{}, err
+// byStoredDay returns the daily metrics keyed by their day in the reporting timezone, see StoredDay.
+//	Metrics stored under different times of the same day are combined using the add function.
+func byStoredDay[T any](days map[time.Time]map[string]T, loc *time.Location, add func(T, T) T) map[time.Time]map[string]T {
+	byDay := make(map[time.Time]map[string]T, len(days))
+	for t, metrics := range days {
+		day := StoredDay(t, loc)
+		if byDay[day] == nil {
+			byDay[day] = make(map[string]T, len(metrics))
+		}
+		for key, m := range metrics {
+			byDay[day][key] = add(byDay[day][key], m)
+		}
 	}
-	return from, to.AddDate(0, 0, 1), nil
+	return byDay
+}
+
+// daysBetween returns the number of calendar days from one start of day to another.
+//	Rounding is needed as days are not always 24 hours long, e.g. on daylight saving time changes.
+func daysBetween(from, to time.Time) int {
+	return int(math.Round(to.Sub(from).Hours() / 24))
 }
 
 func maxArchiveAge(maxPastDays time.Duration) time.Duration {
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -15,7 +16,7 @@ import (
 )
 
 func TestUserRelays(t *testing.T) {
-	now, _ := time.Parse(dayFormat, time.Now().Format(dayFormat))
+	now, _ := time.Parse(dayFormat, time.Now().UTC().Format(dayFormat))
 	usageData := fakeDailyMetrics()
 	todaysUsage := fakeTodaysMetrics()
 
@@ -104,7 +105,7 @@ func TestUserRelays(t *testing.T) {
 }
 
 func TestTotalRelays(t *testing.T) {
-	now, _ := time.Parse(dayFormat, time.Now().Format(dayFormat))
+	now, _ := time.Parse(dayFormat, time.Now().UTC().Format(dayFormat))
 	usageData := fakeDailyMetrics()
 	todaysUsage := fakeTodaysMetrics()
 
@@ -179,7 +180,7 @@ func TestTotalRelays(t *testing.T) {
 }
 
 func TestAppRelays(t *testing.T) {
-	now, _ := time.Parse(dayFormat, time.Now().Format(dayFormat))
+	now, _ := time.Parse(dayFormat, time.Now().UTC().Format(dayFormat))
 	usageData := fakeDailyMetrics()
 	todaysUsage := fakeTodaysMetrics()
 	requestedApp := "app1"
@@ -289,11 +290,12 @@ func TestAppRelays(t *testing.T) {
 			expected: AppRelaysResponse{
 				Application: "app1",
 				From:        now.AddDate(0, 0, -3),
-				To:          now.AddDate(0, 0, 3),
+				To:          now.AddDate(0, 0, 1),
 				Count: RelayCounts{
 					Success: 3*2 + 50,
 					Failure: 3*3 + 40,
 				},
+				Notes: afterTodayNotes(now, now.AddDate(0, 0, 2)),
 			},
 		},
 		{
@@ -303,11 +305,12 @@ func TestAppRelays(t *testing.T) {
 			expected: AppRelaysResponse{
 				Application: "app1",
 				From:        now,
-				To:          now.AddDate(0, 0, 3),
+				To:          now.AddDate(0, 0, 1),
 				Count: RelayCounts{
 					Success: 50,
 					Failure: 40,
 				},
+				Notes: afterTodayNotes(now, now.AddDate(0, 0, 2)),
 			},
 		},
 		{
@@ -363,8 +366,348 @@ func TestAppRelays(t *testing.T) {
 	}
 }
 
+func TestBatchAppRelays(t *testing.T) {
+	now, _ := time.Parse(dayFormat, time.Now().UTC().Format(dayFormat))
+
+	testCases := []struct {
+		name              string
+		requests          []AppRelaysRequest
+		expected          []AppRelaysResponse
+		expectedErr       error
+		expectedErrorCode string
+	}{
+		{
+			name: "Each application's relays over its own time period, in the order of the requests",
+			requests: []AppRelaysRequest{
+				{Application: "app2", From: now.AddDate(0, 0, -2), To: now},
+				{Application: "app1", From: now.AddDate(0, 0, -5), To: now.AddDate(0, 0, -1)},
+				{Application: "app3", From: now.AddDate(0, 0, -5), To: now.AddDate(0, 0, -1)},
+			},
+			expected: []AppRelaysResponse{
+				{
+					Application: "app2",
+					From:        now.AddDate(0, 0, -2),
+					To:          now.AddDate(0, 0, 1),
+					Count:       RelayCounts{Success: 2*1 + 30, Failure: 2*5 + 70},
+				},
+				{
+					Application: "app1",
+					From:        now.AddDate(0, 0, -5),
+					To:          now,
+					Count:       RelayCounts{Success: 5 * 2, Failure: 5 * 3},
+				},
+				{
+					Application: "app3",
+					From:        now.AddDate(0, 0, -5),
+					To:          now,
+				},
+			},
+		},
+		{
+			name:     "Empty batch",
+			expected: []AppRelaysResponse{},
+		},
+		{
+			name:              "Missing application",
+			requests:          []AppRelaysRequest{{Application: "app1"}, {}},
+			expectedErr:       InvalidRequest,
+			expectedErrorCode: ERROR_CODE_INVALID_REQUEST,
+		},
+		{
+			name:              "Invalid time period",
+			requests:          []AppRelaysRequest{{Application: "app1", From: now.AddDate(0, 0, -1), To: now.AddDate(0, 0, -2)}},
+			expectedErr:       ErrInvalidTimespan,
+			expectedErrorCode: ERROR_CODE_INVALID_TIMESPAN,
+		},
+		{
+			name:              "Too many applications",
+			requests:          make([]AppRelaysRequest, MAX_BATCH_APPLICATIONS+1),
+			expectedErr:       InvalidRequest,
+			expectedErrorCode: ERROR_CODE_INVALID_REQUEST,
+		},
+	}
+
+	fakeBackend := fakeBackend{
+		usage:       fakeDailyMetrics(),
+		todaysUsage: fakeTodaysMetrics(),
+	}
+	relayMeter := NewRelayMeter(&fakeBackend, logger.New(), RelayMeterOptions{LoadInterval: 100 * time.Millisecond})
+	time.Sleep(200 * time.Millisecond)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := relayMeter.BatchAppRelays(tc.requests)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("Expected error: %v, got: %v", tc.expectedErr, err)
+			}
+			if err != nil {
+				if _, code, _ := errorStatus(err); code != tc.expectedErrorCode {
+					t.Errorf("Expected error code: %s, got: %s", tc.expectedErrorCode, code)
+				}
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAppDailyRelays(t *testing.T) {
+	now, _ := time.Parse(dayFormat, time.Now().UTC().Format(dayFormat))
+	usageData := fakeDailyMetrics()
+	todaysUsage := fakeTodaysMetrics()
+
+	dailyEntry := func(day time.Time, success, failure int64) AppRelaysResponse {
+		return AppRelaysResponse{
+			Application: "app1",
+			From:        day,
+			To:          day.AddDate(0, 0, 1),
+			Count:       RelayCounts{Success: success, Failure: failure},
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		from        time.Time
+		to          time.Time
+		maxPastDays time.Duration
+		expected    []AppRelaysResponse
+		expectedErr error
+	}{
+		{
+			name: "One entry is returned per day",
+			from: now.AddDate(0, 0, -3),
+			to:   now.AddDate(0, 0, -2),
+			expected: []AppRelaysResponse{
+				dailyEntry(now.AddDate(0, 0, -3), 2, 3),
+				dailyEntry(now.AddDate(0, 0, -2), 2, 3),
+			},
+		},
+		{
+			name: "Today's entry holds today's metrics",
+			from: now.AddDate(0, 0, -1),
+			to:   now,
+			expected: []AppRelaysResponse{
+				dailyEntry(now.AddDate(0, 0, -1), 2, 3),
+				dailyEntry(now, 50, 40),
+			},
+		},
+		{
+			name: "Days with no metrics have zero counts",
+			from: now.AddDate(0, 0, -8),
+			to:   now.AddDate(0, 0, -6),
+			expected: []AppRelaysResponse{
+				dailyEntry(now.AddDate(0, 0, -8), 0, 0),
+				dailyEntry(now.AddDate(0, 0, -7), 0, 0),
+				dailyEntry(now.AddDate(0, 0, -6), 2, 3),
+			},
+		},
+		{
+			name: "No entries are returned for days after today",
+			from: now,
+			to:   now.AddDate(0, 0, 2),
+			expected: []AppRelaysResponse{
+				{
+					Application: "app1",
+					From:        now,
+					To:          now.AddDate(0, 0, 1),
+					Count:       RelayCounts{Success: 50, Failure: 40},
+					Notes:       afterTodayNotes(now, now.AddDate(0, 0, 2)),
+				},
+			},
+		},
+		{
+			name:     "No entries are returned for a time period with no metrics",
+			from:     time.Date(1000, 1, 2, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC),
+			expected: []AppRelaysResponse{},
+		},
+		{
+			name:        "Time period with too many days with metrics is rejected",
+			from:        now.AddDate(0, 0, -MAX_DAILY_RELAYS_DAYS-1),
+			to:          now,
+			maxPastDays: 1000 * 24 * time.Hour,
+			expectedErr: fmt.Errorf("more than the maximum of %d", MAX_DAILY_RELAYS_DAYS),
+		},
+		{
+			name:        "Invalid timespan is rejected",
+			from:        now.AddDate(0, 0, -1),
+			to:          now.AddDate(0, 0, -2),
+			expectedErr: fmt.Errorf("Invalid timespan"),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			fakeBackend := fakeBackend{
+				usage:       usageData,
+				todaysUsage: todaysUsage,
+			}
+
+			relayMeter := NewRelayMeter(&fakeBackend, logger.New(), RelayMeterOptions{LoadInterval: 100 * time.Millisecond, MaxPastDays: tc.maxPastDays})
+			time.Sleep(200 * time.Millisecond)
+			got, err := relayMeter.AppDailyRelays("app1", tc.from, tc.to)
+			if err != nil {
+				if tc.expectedErr == nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if !strings.Contains(err.Error(), tc.expectedErr.Error()) {
+					t.Fatalf("Expected error to contain: %q, got: %v", tc.expectedErr.Error(), err)
+				}
+				return
+			}
+
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStoredDay(t *testing.T) {
+	west := time.FixedZone("UTC-5", -5*60*60)
+	east := time.FixedZone("UTC+9", 9*60*60)
+
+	testCases := []struct {
+		name     string
+		stored   time.Time
+		loc      *time.Location
+		expected time.Time
+	}{
+		{
+			name:     "Midnight in the reporting timezone",
+			stored:   time.Date(2022, 7, 2, 5, 0, 0, 0, time.UTC),
+			loc:      west,
+			expected: time.Date(2022, 7, 2, 0, 0, 0, 0, west),
+		},
+		{
+			name:     "Midnight UTC is the same date west of UTC",
+			stored:   time.Date(2022, 7, 2, 0, 0, 0, 0, time.UTC),
+			loc:      west,
+			expected: time.Date(2022, 7, 2, 0, 0, 0, 0, west),
+		},
+		{
+			name:     "Midnight UTC is the same date east of UTC",
+			stored:   time.Date(2022, 7, 2, 0, 0, 0, 0, time.UTC),
+			loc:      east,
+			expected: time.Date(2022, 7, 2, 0, 0, 0, 0, east),
+		},
+		{
+			name:     "UTC reporting timezone",
+			stored:   time.Date(2022, 7, 2, 0, 0, 0, 0, time.UTC),
+			loc:      time.UTC,
+			expected: time.Date(2022, 7, 2, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := StoredDay(tc.stored, tc.loc); !got.Equal(tc.expected) {
+				t.Errorf("Expected: %v, got: %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestAppDailyRelaysDaysStoredAtMidnightUTC(t *testing.T) {
+	west := time.FixedZone("UTC-5", -5*60*60)
+	now := time.Now().In(west)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, west)
+	legacyDay := func(day time.Time) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	fakeBackend := fakeBackend{
+		usage: map[time.Time]map[string]RelayCounts{
+			// Stored at midnight UTC, before the reporting timezone was configured
+			legacyDay(today.AddDate(0, 0, -3)): {"app1": {Success: 3}},
+			legacyDay(today.AddDate(0, 0, -2)): {"app1": {Success: 2}},
+			today.AddDate(0, 0, -1).UTC():      {"app1": {Success: 1}},
+		},
+		todaysUsage: map[string]RelayCounts{"app1": {Success: 10}},
+	}
+	relayMeter := NewRelayMeter(&fakeBackend, logger.New(), RelayMeterOptions{LoadInterval: 100 * time.Millisecond, Location: west})
+	time.Sleep(200 * time.Millisecond)
+
+	got, err := relayMeter.AppDailyRelays("app1", today.AddDate(0, 0, -3), today.AddDate(0, 0, -1))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var counts []int64
+	for _, r := range got {
+		counts = append(counts, r.Count.Success)
+	}
+	if diff := cmp.Diff([]int64{3, 2, 1}, counts); diff != "" {
+		t.Errorf("unexpected value (-want +got):\n%s", diff)
+	}
+}
+
+func TestAppTodaysRelays(t *testing.T) {
+	now := time.Now()
+	today, _ := time.Parse(dayFormat, now.UTC().Format(dayFormat))
+	interval := 10 * time.Minute
+	currentInterval := today.Add(now.Sub(today).Truncate(interval))
+
+	fakeBackend := fakeBackend{
+		usage:       fakeDailyMetrics(),
+		todaysUsage: fakeTodaysMetrics(),
+		todaysIntervalUsage: map[time.Time]map[string]RelayCounts{
+			today.Add(-1 * interval): {
+				"app1": {Success: 1000, Failure: 1000},
+			},
+			today: {
+				"app1": {Success: 5, Failure: 4},
+				"app2": {Success: 3, Failure: 7},
+			},
+			currentInterval: {
+				"app1": {Success: 10, Failure: 20},
+			},
+		},
+	}
+
+	relayMeter := NewRelayMeter(&fakeBackend, logger.New(), RelayMeterOptions{LoadInterval: 100 * time.Millisecond, TodaysMetricsInterval: interval})
+	time.Sleep(200 * time.Millisecond)
+	got, err := relayMeter.AppTodaysRelays("app1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedEntries := int(currentInterval.Sub(today)/interval) + 1
+	if len(got) != expectedEntries {
+		t.Fatalf("Expected %d entries, got: %d", expectedEntries, len(got))
+	}
+
+	var total RelayCounts
+	for i, entry := range got {
+		expectedFrom := today.Add(time.Duration(i) * interval)
+		if !entry.From.Equal(expectedFrom) || !entry.To.Equal(expectedFrom.Add(interval)) {
+			t.Errorf("Unexpected interval for entry %d: %v -- %v", i, entry.From, entry.To)
+		}
+		if entry.Application != "app1" {
+			t.Errorf("Expected application: %s, got: %s", "app1", entry.Application)
+		}
+		total.Success += entry.Count.Success
+		total.Failure += entry.Count.Failure
+	}
+
+	// Yesterday's interval is not included
+	expectedTotal := RelayCounts{Success: 5 + 10, Failure: 4 + 20}
+	// Right after midnight, the current interval is the first interval of the day
+	if currentInterval.Equal(today) {
+		expectedTotal = RelayCounts{Success: 10, Failure: 20}
+	}
+	if diff := cmp.Diff(expectedTotal, total); diff != "" {
+		t.Errorf("unexpected value (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(RelayCounts{Success: 10, Failure: 20}, got[len(got)-1].Count); diff != "" {
+		t.Errorf("unexpected value for the current interval (-want +got):\n%s", diff)
+	}
+}
+
 func TestAllAppsRelays(t *testing.T) {
-	now, _ := time.Parse(dayFormat, time.Now().Format(dayFormat))
+	now, _ := time.Parse(dayFormat, time.Now().UTC().Format(dayFormat))
 	usageData := fakeDailyMetrics()
 	todaysUsage := fakeTodaysMetrics()
 
@@ -594,29 +937,32 @@ func TestAllAppsRelays(t *testing.T) {
 				"app1": {
 					Application: "app1",
 					From:        now.AddDate(0, 0, -3),
-					To:          now.AddDate(0, 0, 3),
+					To:          now.AddDate(0, 0, 1),
 					Count: RelayCounts{
 						Success: 56,
 						Failure: 49,
 					},
+					Notes: afterTodayNotes(now, now.AddDate(0, 0, 2)),
 				},
 				"app2": {
 					Application: "app2",
 					From:        now.AddDate(0, 0, -3),
-					To:          now.AddDate(0, 0, 3),
+					To:          now.AddDate(0, 0, 1),
 					Count: RelayCounts{
 						Success: 33,
 						Failure: 85,
 					},
+					Notes: afterTodayNotes(now, now.AddDate(0, 0, 2)),
 				},
 				"app4": {
 					Application: "app4",
 					From:        now.AddDate(0, 0, -3),
-					To:          now.AddDate(0, 0, 3),
+					To:          now.AddDate(0, 0, 1),
 					Count: RelayCounts{
 						Success: 515,
 						Failure: 721,
 					},
+					Notes: afterTodayNotes(now, now.AddDate(0, 0, 2)),
 				},
 			},
 		},
@@ -628,29 +974,32 @@ func TestAllAppsRelays(t *testing.T) {
 				"app1": {
 					Application: "app1",
 					From:        now,
-					To:          now.AddDate(0, 0, 3),
+					To:          now.AddDate(0, 0, 1),
 					Count: RelayCounts{
 						Success: 50,
 						Failure: 40,
 					},
+					Notes: afterTodayNotes(now, now.AddDate(0, 0, 2)),
 				},
 				"app2": {
 					Application: "app2",
 					From:        now,
-					To:          now.AddDate(0, 0, 3),
+					To:          now.AddDate(0, 0, 1),
 					Count: RelayCounts{
 						Success: 30,
 						Failure: 70,
 					},
+					Notes: afterTodayNotes(now, now.AddDate(0, 0, 2)),
 				},
 				"app4": {
 					Application: "app4",
 					From:        now,
-					To:          now.AddDate(0, 0, 3),
+					To:          now.AddDate(0, 0, 1),
 					Count: RelayCounts{
 						Success: 500,
 						Failure: 700,
 					},
+					Notes: afterTodayNotes(now, now.AddDate(0, 0, 2)),
 				},
 			},
 		},
@@ -730,8 +1079,89 @@ func TestAllAppsRelays(t *testing.T) {
 	}
 }
 
+func TestTopAppsRelays(t *testing.T) {
+	now, _ := time.Parse(dayFormat, time.Now().UTC().Format(dayFormat))
+	from := now.AddDate(0, 0, -6)
+
+	testCases := []struct {
+		name         string
+		n            int
+		by           RankBy
+		expectedApps []string
+		expectedErr  error
+	}{
+		{
+			name:         "Applications are ranked by total relays by default",
+			expectedApps: []string{"app4", "app2", "app1"},
+		},
+		{
+			name:         "Applications are ranked by successful relays",
+			by:           RANK_BY_SUCCESS,
+			expectedApps: []string{"app4", "app1", "app2"},
+		},
+		{
+			name:         "Applications are ranked by failed relays",
+			by:           RANK_BY_FAILURE,
+			expectedApps: []string{"app4", "app2", "app1"},
+		},
+		{
+			name:         "Applications are ranked by failure rate",
+			by:           RANK_BY_FAILURE_RATE,
+			expectedApps: []string{"app2", "app4", "app1"},
+		},
+		{
+			name:         "Number of returned applications is limited",
+			n:            2,
+			by:           RANK_BY_SUCCESS,
+			expectedApps: []string{"app4", "app1"},
+		},
+		{
+			name:        "Invalid ranking is rejected",
+			by:          RankBy("invalid"),
+			expectedErr: InvalidRequest,
+		},
+		{
+			name:        "Negative number of applications is rejected",
+			n:           -1,
+			expectedErr: InvalidRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			fakeBackend := fakeBackend{
+				usage:       fakeDailyMetrics(),
+				todaysUsage: fakeTodaysMetrics(),
+			}
+
+			relayMeter := NewRelayMeter(&fakeBackend, logger.New(), RelayMeterOptions{LoadInterval: 100 * time.Millisecond})
+			time.Sleep(200 * time.Millisecond)
+			got, err := relayMeter.TopAppsRelays(from, now, tc.n, tc.by)
+			if err != nil {
+				if tc.expectedErr == nil || !errors.Is(err, tc.expectedErr) {
+					t.Fatalf("Expected error: %v, got: %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if tc.expectedErr != nil {
+				t.Fatalf("Expected error: %v, got nil", tc.expectedErr)
+			}
+
+			var gotApps []string
+			for _, app := range got {
+				gotApps = append(gotApps, app.Application)
+			}
+			if diff := cmp.Diff(tc.expectedApps, gotApps); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestLoadBalancerRelays(t *testing.T) {
-	now, _ := time.Parse(dayFormat, time.Now().Format(dayFormat))
+	now, _ := time.Parse(dayFormat, time.Now().UTC().Format(dayFormat))
 	usageData := fakeDailyMetrics()
 	todaysUsage := fakeTodaysMetrics()
 	errBackendFailure := errors.New("backend error")
@@ -753,7 +1183,7 @@ func TestLoadBalancerRelays(t *testing.T) {
 		{
 			name:        "Backend service error",
 			backendErr:  errBackendFailure,
-			expectedErr: errBackendFailure,
+			expectedErr: ErrBackendUnavailable,
 		},
 		{
 			name:         "Correct summary for a loadbalancer",
@@ -845,8 +1275,30 @@ func TestLoadBalancerRelays(t *testing.T) {
 	}
 }
 
+func TestAllLoadBalancersRelaysCachesLoadBalancers(t *testing.T) {
+	fakeBackend := fakeBackend{
+		usage:       fakeDailyMetrics(),
+		todaysUsage: fakeTodaysMetrics(),
+		loadbalancers: map[string]*repository.LoadBalancer{
+			"lb1": {ID: "lb1", Applications: []*repository.Application{{GatewayAAT: repository.GatewayAAT{ApplicationPublicKey: "app1"}}}},
+		},
+	}
+	relayMeter := NewRelayMeter(&fakeBackend, logger.New(), RelayMeterOptions{LoadInterval: 100 * time.Millisecond})
+	time.Sleep(200 * time.Millisecond)
+
+	// e.g. the buckets of a request with a granularity
+	for i := 0; i < 3; i++ {
+		if _, err := relayMeter.AllLoadBalancersRelays(time.Time{}, time.Time{}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if fakeBackend.loadBalancersCalls != 1 {
+		t.Errorf("Expected 1 call to get the load balancers, got: %d", fakeBackend.loadBalancersCalls)
+	}
+}
+
 func TestAllLoadBalancersRelays(t *testing.T) {
-	now, _ := time.Parse(dayFormat, time.Now().Format(dayFormat))
+	now, _ := time.Parse(dayFormat, time.Now().UTC().Format(dayFormat))
 	usageData := fakeDailyMetrics()
 	todaysUsage := fakeTodaysMetrics()
 	errBackendFailure := errors.New("backend error")
@@ -863,7 +1315,7 @@ func TestAllLoadBalancersRelays(t *testing.T) {
 		{
 			name:        "Backend service error",
 			backendErr:  errBackendFailure,
-			expectedErr: errBackendFailure,
+			expectedErr: ErrBackendUnavailable,
 			expected:    map[string]LoadBalancerRelaysResponse{},
 		},
 		{
@@ -999,11 +1451,15 @@ func TestAllLoadBalancersRelays(t *testing.T) {
 }
 
 func TestStartDataLoader(t *testing.T) {
-	now, _ := time.Parse(dayFormat, time.Now().Format(dayFormat))
+	now, _ := time.Parse(dayFormat, time.Now().UTC().Format(dayFormat))
+	// A timezone whose current date is, for most of the day, not the same as in UTC
+	farEast := time.FixedZone("UTC+14", 14*60*60)
+	farEastToday, _ := time.ParseInLocation(dayFormat, time.Now().In(farEast).Format(dayFormat), farEast)
 
 	testCases := []struct {
 		name          string
 		maxArchiveAge time.Duration
+		location      *time.Location
 		expectedFrom  time.Time
 		expectedTo    time.Time
 	}{
@@ -1018,6 +1474,13 @@ func TestStartDataLoader(t *testing.T) {
 			expectedFrom:  now.AddDate(0, 0, -5),
 			expectedTo:    now,
 		},
+		{
+			name:          "Days start at midnight in the reporting timezone",
+			maxArchiveAge: 24 * 5 * time.Hour,
+			location:      farEast,
+			expectedFrom:  farEastToday.AddDate(0, 0, -5),
+			expectedTo:    farEastToday,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1029,6 +1492,7 @@ func TestStartDataLoader(t *testing.T) {
 				RelayMeterOptions: RelayMeterOptions{
 					LoadInterval: 1 * time.Second,
 					MaxPastDays:  tc.maxArchiveAge,
+					Location:     tc.location,
 				},
 			}
 
@@ -1048,18 +1512,308 @@ func TestStartDataLoader(t *testing.T) {
 	}
 }
 
+func TestAdjustTimePeriodIn(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Unexpected error loading timezone: %v", err)
+	}
+	utcMinus5 := time.FixedZone("UTC-5", -5*60*60)
+	utcPlus9 := time.FixedZone("UTC+9", 9*60*60)
+
+	testCases := []struct {
+		name         string
+		from         time.Time
+		to           time.Time
+		location     *time.Location
+		expectedFrom time.Time
+		expectedTo   time.Time
+		expectedDays int
+		expectedErr  bool
+	}{
+		{
+			name:         "Dates are taken in the location of the parameters",
+			from:         time.Date(2022, time.June, 25, 23, 0, 0, 0, utcMinus5),
+			to:           time.Date(2022, time.June, 26, 23, 0, 0, 0, utcMinus5),
+			location:     time.UTC,
+			expectedFrom: time.Date(2022, time.June, 25, 0, 0, 0, 0, time.UTC),
+			expectedTo:   time.Date(2022, time.June, 27, 0, 0, 0, 0, time.UTC),
+			expectedDays: 2,
+		},
+		{
+			name:         "Days start at midnight in the reporting timezone",
+			from:         time.Date(2022, time.June, 25, 12, 0, 0, 0, time.UTC),
+			to:           time.Date(2022, time.June, 25, 12, 0, 0, 0, time.UTC),
+			location:     utcPlus9,
+			expectedFrom: time.Date(2022, time.June, 25, 0, 0, 0, 0, utcPlus9),
+			expectedTo:   time.Date(2022, time.June, 26, 0, 0, 0, 0, utcPlus9),
+			expectedDays: 1,
+		},
+		{
+			name:         "Days across a daylight saving time change",
+			from:         time.Date(2022, time.March, 12, 0, 0, 0, 0, time.UTC),
+			to:           time.Date(2022, time.March, 13, 0, 0, 0, 0, time.UTC),
+			location:     newYork,
+			expectedFrom: time.Date(2022, time.March, 12, 0, 0, 0, 0, newYork),
+			expectedTo:   time.Date(2022, time.March, 14, 0, 0, 0, 0, newYork),
+			expectedDays: 2,
+		},
+		{
+			name:        "Invalid time period returns an error",
+			from:        time.Date(2022, time.June, 26, 0, 0, 0, 0, time.UTC),
+			to:          time.Date(2022, time.June, 25, 0, 0, 0, 0, time.UTC),
+			location:    time.UTC,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			from, to, err := AdjustTimePeriodIn(tc.from, tc.to, tc.location)
+			if (err != nil) != tc.expectedErr {
+				t.Fatalf("Expected error: %v, got: %v", tc.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if !from.Equal(tc.expectedFrom) || !to.Equal(tc.expectedTo) {
+				t.Errorf("Expected: %v -- %v, got: %v -- %v", tc.expectedFrom, tc.expectedTo, from, to)
+			}
+			if days := daysBetween(from, to); days != tc.expectedDays {
+				t.Errorf("Expected %d days, got: %d", tc.expectedDays, days)
+			}
+		})
+	}
+}
+
+func TestClampTimePeriod(t *testing.T) {
+	today, _ := time.Parse(dayFormat, time.Now().UTC().Format(dayFormat))
+	meter := &relayMeter{RelayMeterOptions: RelayMeterOptions{MaxPastDays: 10 * 24 * time.Hour}}
+
+	testCases := []struct {
+		name          string
+		from          time.Time
+		to            time.Time
+		expectedFrom  time.Time
+		expectedTo    time.Time
+		expectedNotes []string
+		expectedErr   bool
+	}{
+		{
+			name:         "Time period with metrics is not changed",
+			from:         today.AddDate(0, 0, -10),
+			to:           today,
+			expectedFrom: today.AddDate(0, 0, -10),
+			expectedTo:   today.AddDate(0, 0, 1),
+		},
+		{
+			name:         "From is limited to the oldest day with metrics",
+			from:         today.AddDate(0, 0, -15),
+			to:           today.AddDate(0, 0, -1),
+			expectedFrom: today.AddDate(0, 0, -10),
+			expectedTo:   today,
+			expectedNotes: []string{
+				fmt.Sprintf("The time period starts on %s instead of %s: metrics are only kept for the past 10 days", today.AddDate(0, 0, -10).Format(dayFormat), today.AddDate(0, 0, -15).Format(dayFormat)),
+			},
+		},
+		{
+			name:          "To is limited to today",
+			from:          today.AddDate(0, 0, -1),
+			to:            today.AddDate(0, 0, 5),
+			expectedFrom:  today.AddDate(0, 0, -1),
+			expectedTo:    today.AddDate(0, 0, 1),
+			expectedNotes: afterTodayNotes(today, today.AddDate(0, 0, 5)),
+		},
+		{
+			name:         "Time period with no metrics is not changed",
+			from:         today.AddDate(0, 0, -30),
+			to:           today.AddDate(0, 0, -20),
+			expectedFrom: today.AddDate(0, 0, -30),
+			expectedTo:   today.AddDate(0, 0, -19),
+			expectedNotes: []string{
+				fmt.Sprintf("No metrics are available for the time period: metrics are only available from %s to %s", today.AddDate(0, 0, -10).Format(dayFormat), today.Format(dayFormat)),
+			},
+		},
+		{
+			name:        "Invalid time period returns an error",
+			from:        today,
+			to:          today.AddDate(0, 0, -1),
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			from, to, notes, err := meter.clampTimePeriod(tc.from, tc.to)
+			if (err != nil) != tc.expectedErr {
+				t.Fatalf("Expected error: %v, got: %v", tc.expectedErr, err)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrInvalidTimespan) {
+					t.Errorf("Expected an ErrInvalidTimespan error, got: %v", err)
+				}
+				return
+			}
+			if !from.Equal(tc.expectedFrom) || !to.Equal(tc.expectedTo) {
+				t.Errorf("Expected: %v -- %v, got: %v -- %v", tc.expectedFrom, tc.expectedTo, from, to)
+			}
+			if diff := cmp.Diff(tc.expectedNotes, notes); diff != "" {
+				t.Errorf("unexpected notes (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestResultRelayCounts(t *testing.T) {
+	testCases := []struct {
+		result   string
+		expected RelayCounts
+	}{
+		{result: "200", expected: RelayCounts{Success: 5}},
+		{result: "204", expected: RelayCounts{Success: 5}},
+		{result: "400", expected: RelayCounts{Failure: 5, ClientError: 5}},
+		{result: "408", expected: RelayCounts{Failure: 5, Timeout: 5}},
+		{result: "500", expected: RelayCounts{Failure: 5, ServerError: 5}},
+		{result: "504", expected: RelayCounts{Failure: 5, Timeout: 5}},
+		{result: "302", expected: RelayCounts{Failure: 5, Other: 5}},
+		{result: "unknown", expected: RelayCounts{Failure: 5, Other: 5}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.result, func(t *testing.T) {
+			got := ResultRelayCounts(tc.result, 5)
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	var total RelayCounts
+	for _, result := range []string{"200", "200", "404", "500", "504", "unknown"} {
+		total = total.Add(ResultRelayCounts(result, 1))
+	}
+	expected := RelayCounts{Success: 2, Failure: 4, ClientError: 1, ServerError: 1, Timeout: 1, Other: 1}
+	if diff := cmp.Diff(expected, total); diff != "" {
+		t.Errorf("unexpected value (-want +got):\n%s", diff)
+	}
+}
+
+func TestRelayCountsAddChains(t *testing.T) {
+	testCases := []struct {
+		name     string
+		a        RelayCounts
+		b        RelayCounts
+		expected RelayCounts
+	}{
+		{
+			name:     "Counts with no breakdown by chain",
+			a:        RelayCounts{Success: 1, Failure: 2},
+			b:        RelayCounts{Success: 3, Failure: 4},
+			expected: RelayCounts{Success: 4, Failure: 6},
+		},
+		{
+			name: "Breakdowns by chain are merged",
+			a:    ChainRelayCounts("0021", RelayCounts{Success: 1, Failure: 2}),
+			b:    ChainRelayCounts("0009", RelayCounts{Success: 3}).Add(ChainRelayCounts("0021", RelayCounts{Success: 5})),
+			expected: RelayCounts{
+				Success: 9,
+				Failure: 2,
+				Chains: map[string]RelayCounts{
+					"0021": {Success: 6, Failure: 2},
+					"0009": {Success: 3},
+				},
+			},
+		},
+		{
+			name: "Counts with no breakdown are added under the empty chain ID",
+			a:    ChainRelayCounts("0021", RelayCounts{Success: 1}),
+			b:    RelayCounts{Success: 2, Failure: 1},
+			expected: RelayCounts{
+				Success: 3,
+				Failure: 1,
+				Chains: map[string]RelayCounts{
+					"0021": {Success: 1},
+					"":     {Success: 2, Failure: 1},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.a.Add(tc.b)
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRelayCountsJSON(t *testing.T) {
+	testCases := []struct {
+		name     string
+		counts   RelayCounts
+		expected string
+	}{
+		{
+			name:     "Total and ratios are included",
+			counts:   RelayCounts{Success: 3, Failure: 1, ServerError: 1},
+			expected: `{"Success":3,"Failure":1,"ClientError":0,"ServerError":1,"Timeout":0,"Other":0,"Total":4,"FailureRatio":0.25,"SuccessRatio":0.75}`,
+		},
+		{
+			name:     "Ratios are zero for an empty time period",
+			counts:   RelayCounts{},
+			expected: `{"Success":0,"Failure":0,"ClientError":0,"ServerError":0,"Timeout":0,"Other":0,"Total":0,"FailureRatio":0,"SuccessRatio":0}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := json.Marshal(AppRelaysResponse{Count: tc.counts})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var response struct {
+				Count json.RawMessage
+			}
+			if err := json.Unmarshal(got, &response); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, string(response.Count)); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+
+			// The added fields are ignored when reading the counts back
+			var counts RelayCounts
+			if err := json.Unmarshal(response.Count, &counts); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.counts, counts); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 type fakeBackend struct {
 	usage       map[time.Time]map[string]RelayCounts
 	err         error
 	todaysUsage map[string]RelayCounts
 	userApps    map[string][]string
 
+	todaysIntervalUsage map[time.Time]map[string]RelayCounts
+
 	todaysMetricsCalls int
 	dailyMetricsCalls  int
+	loadBalancersCalls int
 	dailyMetricsFrom   time.Time
 	dailyMetricsTo     time.Time
 
 	loadbalancers map[string]*repository.LoadBalancer
+
+	appQuotas  map[string]*Quota
+	userQuotas map[string]*Quota
+
+	latency map[time.Time]map[string]RelayLatency
 }
 
 func (f *fakeBackend) DailyUsage(from, to time.Time) (map[time.Time]map[string]RelayCounts, error) {
@@ -1074,15 +1828,32 @@ func (f *fakeBackend) TodaysUsage() (map[string]RelayCounts, error) {
 	return f.todaysUsage, nil
 }
 
+func (f *fakeBackend) TodaysIntervalUsage() (map[time.Time]map[string]RelayCounts, error) {
+	return f.todaysIntervalUsage, nil
+}
+
+func (f *fakeBackend) DailyLatency(from, to time.Time) (map[time.Time]map[string]RelayLatency, error) {
+	return f.latency, nil
+}
+
 func (f *fakeBackend) UserApps(user string) ([]string, error) {
 	return f.userApps[user], nil
 }
 
 func (f *fakeBackend) LoadBalancer(endpoint string) (*repository.LoadBalancer, error) {
-	return f.loadbalancers[endpoint], f.err
+	if f.err != nil {
+		return nil, f.err
+	}
+	// Unknown endpoints are reported as the backend apiserver does
+	lb, ok := f.loadbalancers[endpoint]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrLoadBalancerNotFound, endpoint)
+	}
+	return lb, nil
 }
 
 func (f *fakeBackend) LoadBalancers() ([]*repository.LoadBalancer, error) {
+	f.loadBalancersCalls++
 	var lbs []*repository.LoadBalancer
 
 	for _, lb := range f.loadbalancers {
@@ -1092,6 +1863,19 @@ func (f *fakeBackend) LoadBalancers() ([]*repository.LoadBalancer, error) {
 	return lbs, f.err
 }
 
+func (f *fakeBackend) AppQuota(app string) (*Quota, error) {
+	return f.appQuotas[app], f.err
+}
+
+func (f *fakeBackend) UserQuota(user string) (*Quota, error) {
+	return f.userQuotas[user], f.err
+}
+
+// afterTodayNotes returns the notes of a time period limited to today, whose requested last day was 'to'
+func afterTodayNotes(today, to time.Time) []string {
+	return []string{fmt.Sprintf("The time period ends on %s instead of %s: no metrics are available after today", today.Format(dayFormat), to.Format(dayFormat))}
+}
+
 func fakeDailyMetrics() map[time.Time]map[string]RelayCounts {
 	dayMetrics := map[string]RelayCounts{
 		"app1": {Success: 2, Failure: 3},
@@ -1099,7 +1883,7 @@ func fakeDailyMetrics() map[time.Time]map[string]RelayCounts {
 		"app4": {Success: 5, Failure: 7},
 	}
 
-	now, _ := time.Parse(dayFormat, time.Now().Format(dayFormat))
+	now, _ := time.Parse(dayFormat, time.Now().UTC().Format(dayFormat))
 	metrics := make(map[time.Time]map[string]RelayCounts)
 	for i := 1; i < 7; i++ {
 		metrics[now.AddDate(0, 0, -1*i)] = dayMetrics
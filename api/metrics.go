@@ -0,0 +1,109 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	METRICS_PATH      = "/metrics"
+	METRICS_NAMESPACE = "relay_meter"
+
+	// Values of the metrics label of loadedTimestamp
+	LOADED_METRICS_DAILY  = "daily"
+	LOADED_METRICS_TODAYS = "todays"
+
+	// Value of the route label for requests not matching any route
+	ROUTE_LABEL_UNKNOWN = "unknown"
+)
+
+var (
+	loaderDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: METRICS_NAMESPACE,
+		Name:      "loader_duration_seconds",
+		Help:      "Duration of the data loader iterations",
+		Buckets:   prometheus.ExponentialBuckets(0.05, 2, 12),
+	})
+	loaderErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: METRICS_NAMESPACE,
+		Name:      "loader_errors_total",
+		Help:      "Number of failed data loader iterations",
+	})
+	// The age of the loaded metrics, e.g. to alert on a stuck loader, is: time() - relay_meter_loaded_timestamp_seconds
+	loadedTimestamp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: METRICS_NAMESPACE,
+		Name:      "loaded_timestamp_seconds",
+		Help:      "Unix time the daily or today's metrics were last loaded from the backend",
+	}, []string{"metrics"})
+	todaysAppRelays = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: METRICS_NAMESPACE,
+		Name:      "todays_app_relays",
+		Help:      "Today's relays of each application so far: only exported if enabled in the meter's options",
+	}, []string{"application"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: METRICS_NAMESPACE,
+		Name:      "http_request_duration_seconds",
+		Help:      "Duration of the HTTP requests, by route and status code",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "status"})
+)
+
+// statusRecorder records the status code written to the response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// Flush is needed for streaming responses, e.g. NDJSON
+func (s *statusRecorder) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// InstrumentHandler returns a handler which records the duration and status code of requests passed to the next handler,
+//	labelled by route, i.e. the route's path in the OpenAPI specification, to keep the number of label values bounded.
+func InstrumentHandler(next func(w http.ResponseWriter, req *http.Request)) func(w http.ResponseWriter, req *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(recorder, req)
+
+		route := ROUTE_LABEL_UNKNOWN
+		if r, _ := matchRoute(req.URL.Path); r != nil {
+			route = r.openAPIPath
+		}
+		httpRequestDuration.WithLabelValues(route, strconv.Itoa(recorder.status)).Observe(time.Since(start).Seconds())
+	}
+}
+
+// recordLoadedMetrics updates the metrics of a successful data loader iteration
+func recordLoadedMetrics(updateDaily, updateToday bool, todaysUsage map[string]RelayCounts, exportApps bool) {
+	now := float64(time.Now().Unix())
+	if updateDaily {
+		loadedTimestamp.WithLabelValues(LOADED_METRICS_DAILY).Set(now)
+	}
+	if !updateToday {
+		return
+	}
+	loadedTimestamp.WithLabelValues(LOADED_METRICS_TODAYS).Set(now)
+
+	if !exportApps {
+		return
+	}
+	// Applications with no relays today are removed
+	todaysAppRelays.Reset()
+	for app, counts := range todaysUsage {
+		todaysAppRelays.WithLabelValues(app).Set(float64(counts.Total()))
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"bufio"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	logger "github.com/sirupsen/logrus"
+)
+
+// metricValue returns the value of the time series, as exported on the metrics endpoint, or zero if the series is not exported
+func metricValue(t *testing.T, series string) float64 {
+	w := httptest.NewRecorder()
+	promhttp.Handler().ServeHTTP(w, httptest.NewRequest("GET", "http://relay-meter.pokt.network"+METRICS_PATH, nil))
+
+	scanner := bufio.NewScanner(w.Result().Body)
+	for scanner.Scan() {
+		if value := strings.TrimPrefix(scanner.Text(), series+" "); value != scanner.Text() {
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				t.Fatalf("Unexpected error parsing value of %s: %v", series, err)
+			}
+			return v
+		}
+	}
+	return 0
+}
+
+func TestInstrumentHandler(t *testing.T) {
+	handler := InstrumentHandler(GetHttpServer(&fakeRelayMeter{responseErr: ErrQuotaNotFound}, logger.New()))
+
+	testCases := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{
+			name:     "Requests are labelled by route",
+			path:     "/v0/quota/apps/app1",
+			expected: `relay_meter_http_request_duration_seconds_count{route="/v0/quota/apps/{app}",status="404"}`,
+		},
+		{
+			name:     "Requests to unknown paths",
+			path:     "/v1/quota",
+			expected: `relay_meter_http_request_duration_seconds_count{route="unknown",status="400"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			before := metricValue(t, tc.expected)
+			req := httptest.NewRequest("GET", "http://relay-meter.pokt.network"+tc.path, nil)
+			handler(httptest.NewRecorder(), req)
+
+			if got := metricValue(t, tc.expected); got != before+1 {
+				t.Errorf("Expected %s to be: %v, got: %v", tc.expected, before+1, got)
+			}
+		})
+	}
+}
+
+func TestRecordLoadedMetrics(t *testing.T) {
+	todaysUsage := map[string]RelayCounts{
+		"app1": {Success: 10, Failure: 2},
+		"app2": {Success: 5},
+	}
+
+	recordLoadedMetrics(true, true, todaysUsage, false)
+	if got := metricValue(t, `relay_meter_todays_app_relays{application="app1"}`); got != 0 {
+		t.Errorf("Expected no application metrics unless enabled, got: %v", got)
+	}
+
+	recordLoadedMetrics(true, true, todaysUsage, true)
+	if got := metricValue(t, `relay_meter_todays_app_relays{application="app1"}`); got != 12 {
+		t.Errorf("Expected today's relays of app1: 12, got: %v", got)
+	}
+	if got := metricValue(t, `relay_meter_loaded_timestamp_seconds{metrics="daily"}`); got == 0 {
+		t.Errorf("Expected the loaded timestamp of the daily metrics to be set")
+	}
+
+	// Applications with no relays today are removed
+	recordLoadedMetrics(false, true, map[string]RelayCounts{"app1": {Success: 10}}, true)
+	if got := metricValue(t, `relay_meter_todays_app_relays{application="app2"}`); got != 0 {
+		t.Errorf("Expected no metrics for app2, got: %v", got)
+	}
+}
@@ -0,0 +1,233 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+)
+
+const (
+	WEBHOOK_TIMEOUT_DEFAULT_SECONDS = 10
+	// Maximum number of refreshes waiting to be notified: further refreshes are dropped until the webhook catches up
+	WEBHOOK_QUEUE_SIZE = 10
+)
+
+// Notifier is notified by the meter after every refresh of today's metrics.
+//	Notify is called from the meter's refresh: it should not block, e.g. on a slow webhook.
+//	lastUpdated is the time of the refresh, as returned by the meter's LastUpdated: usage loaded before midnight is yesterday's.
+type Notifier interface {
+	Notify(todaysUsage map[string]RelayCounts, lastUpdated time.Time)
+}
+
+// NotificationRule specifies a condition on today's relays of an application, e.g. "app X above 1M relays today", or "failure ratio above 5%"
+type NotificationRule struct {
+	// Name identifies the rule in notifications: it needs to be unique
+	Name string
+	// Application the rule applies to: the rule applies to all applications if empty
+	Application string
+	// Metric to evaluate: uses the same values as the ranking of top applications, e.g. "total" or "failure_rate"
+	Metric RankBy
+	// The rule fires when the metric is above the threshold. Ratios are specified as fractions, e.g. 0.05 for 5%
+	Threshold float64
+	// MinRelays is the minimum number of relays today for an application to be evaluated: avoids e.g. failure ratio alerts on a handful of relays
+	MinRelays int64
+}
+
+// Notification is the payload posted to the webhook when a rule fires
+type Notification struct {
+	Rule        string
+	Application string
+	Metric      RankBy
+	Threshold   float64
+	Value       float64
+	Count       RelayCounts
+	Day         time.Time
+	Time        time.Time
+}
+
+// NewWebhookNotifier returns a notifier which posts a Notification, as JSON, to the webhook URL for every rule that fires.
+//	A rule fires at most once per application per day, days starting at midnight in the specified location, i.e. the reporting timezone, or UTC if nil:
+//	if posting to the webhook fails, the notification is retried on the next refresh.
+//	Usage loaded before the start of the current day is not evaluated, as its totals are the previous day's.
+//	Notifications are posted in the background, so a slow webhook does not delay the refresh of the metrics:
+//	refreshes are queued, up to WEBHOOK_QUEUE_SIZE, and dropped while the queue is full.
+func NewWebhookNotifier(url string, rules []NotificationRule, loc *time.Location, l *logger.Logger) (Notifier, error) {
+	if url == "" {
+		return nil, fmt.Errorf("Missing webhook URL")
+	}
+
+	names := make(map[string]bool)
+	for _, rule := range rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("Missing name on notification rule: %+v", rule)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("Duplicate notification rule name: %s", rule.Name)
+		}
+		names[rule.Name] = true
+
+		if _, err := rankFunc(rule.Metric); err != nil {
+			return nil, fmt.Errorf("Invalid metric on notification rule %s: %v", rule.Name, err)
+		}
+	}
+
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	w := &webhookNotifier{
+		URL:      url,
+		Rules:    rules,
+		Client:   &http.Client{Timeout: WEBHOOK_TIMEOUT_DEFAULT_SECONDS * time.Second},
+		Location: loc,
+		Logger:   l,
+		sent:     make(map[string]bool),
+		queue:    make(chan todaysRefresh, WEBHOOK_QUEUE_SIZE),
+	}
+	go func() {
+		for refresh := range w.queue {
+			w.notify(refresh.usage, refresh.lastUpdated)
+		}
+	}()
+	return w, nil
+}
+
+type webhookNotifier struct {
+	URL      string
+	Rules    []NotificationRule
+	Client   *http.Client
+	Location *time.Location
+	*logger.Logger
+
+	// Rule and application pairs already notified on the current day
+	day   time.Time
+	sent  map[string]bool
+	mutex sync.Mutex
+
+	// Refreshes waiting to be notified
+	queue chan todaysRefresh
+}
+
+type todaysRefresh struct {
+	usage       map[string]RelayCounts
+	lastUpdated time.Time
+}
+
+// Notify queues today's usage to be evaluated against the rules: it does not wait for the webhook
+func (w *webhookNotifier) Notify(todaysUsage map[string]RelayCounts, lastUpdated time.Time) {
+	select {
+	case w.queue <- todaysRefresh{usage: todaysUsage, lastUpdated: lastUpdated}:
+	default:
+		w.Logger.WithFields(logger.Fields{"queue_size": cap(w.queue)}).Warn("Notifications queue is full: dropping refresh of todays usage")
+	}
+}
+
+func (w *webhookNotifier) notify(todaysUsage map[string]RelayCounts, lastUpdated time.Time) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	now := time.Now().In(w.Location)
+	today, _, err := AdjustTimePeriodIn(now, now, w.Location)
+	if err != nil {
+		w.Logger.WithFields(logger.Fields{"error": err}).Warn("Error getting current day evaluating notification rules")
+		return
+	}
+	// Rules can fire again on a new day
+	if !today.Equal(w.day) {
+		w.day = today
+		w.sent = make(map[string]bool)
+	}
+	// Usage loaded before midnight, e.g. queued across it, holds yesterday's totals: it would fire the rules again, labelled as today
+	if lastUpdated.Before(today) {
+		w.Logger.WithFields(logger.Fields{"last_updated": lastUpdated, "day": today}).Info("Skipping notification rules on usage loaded before the start of the day")
+		return
+	}
+
+	for _, n := range evaluateRules(w.Rules, todaysUsage) {
+		key := n.Rule + "/" + n.Application
+		if w.sent[key] {
+			continue
+		}
+
+		n.Day = today
+		n.Time = now
+		if err := w.post(n); err != nil {
+			w.Logger.WithFields(logger.Fields{"error": err, "rule": n.Rule, "app": n.Application}).Warn("Error posting notification to webhook")
+			continue
+		}
+		w.Logger.WithFields(logger.Fields{"rule": n.Rule, "app": n.Application, "value": n.Value}).Info("Posted notification to webhook")
+		w.sent[key] = true
+	}
+}
+
+func (w *webhookNotifier) post(n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.Client.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Error from webhook: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// evaluateRules returns a notification for every rule and application pair which is above the rule's threshold.
+//	Notifications are sorted by rule name and application, so they are sent in the same order on every refresh.
+func evaluateRules(rules []NotificationRule, todaysUsage map[string]RelayCounts) []Notification {
+	var notifications []Notification
+	for _, rule := range rules {
+		value, err := rankFunc(rule.Metric)
+		if err != nil {
+			continue
+		}
+
+		for app, counts := range todaysUsage {
+			if rule.Application != "" && rule.Application != app {
+				continue
+			}
+			if counts.Total() < rule.MinRelays {
+				continue
+			}
+			if v := value(counts); v > rule.Threshold {
+				notifications = append(notifications, Notification{
+					Rule:        rule.Name,
+					Application: app,
+					Metric:      rule.Metric,
+					Threshold:   rule.Threshold,
+					Value:       v,
+					Count:       counts,
+				})
+			}
+		}
+	}
+
+	sort.Slice(notifications, func(i, j int) bool {
+		if notifications[i].Rule != notifications[j].Rule {
+			return notifications[i].Rule < notifications[j].Rule
+		}
+		return notifications[i].Application < notifications[j].Application
+	})
+	return notifications
+}
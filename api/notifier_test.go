@@ -0,0 +1,251 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	logger "github.com/sirupsen/logrus"
+)
+
+func TestEvaluateRules(t *testing.T) {
+	todaysUsage := map[string]RelayCounts{
+		"app1": {Success: 1_500_000, Failure: 10},
+		"app2": {Success: 90, Failure: 10},
+		"app3": {Success: 1, Failure: 1},
+	}
+
+	testCases := []struct {
+		name     string
+		rules    []NotificationRule
+		expected []Notification
+	}{
+		{
+			name:  "Rule on a single application",
+			rules: []NotificationRule{{Name: "app1-total", Application: "app1", Metric: RANK_BY_TOTAL, Threshold: 1_000_000}},
+			expected: []Notification{
+				{Rule: "app1-total", Application: "app1", Metric: RANK_BY_TOTAL, Threshold: 1_000_000, Value: 1_500_010, Count: todaysUsage["app1"]},
+			},
+		},
+		{
+			name:  "Rule on all applications",
+			rules: []NotificationRule{{Name: "failure-ratio", Metric: RANK_BY_FAILURE_RATE, Threshold: 0.05}},
+			expected: []Notification{
+				{Rule: "failure-ratio", Application: "app2", Metric: RANK_BY_FAILURE_RATE, Threshold: 0.05, Value: 0.1, Count: todaysUsage["app2"]},
+				{Rule: "failure-ratio", Application: "app3", Metric: RANK_BY_FAILURE_RATE, Threshold: 0.05, Value: 0.5, Count: todaysUsage["app3"]},
+			},
+		},
+		{
+			name:  "Applications below the minimum number of relays are not evaluated",
+			rules: []NotificationRule{{Name: "failure-ratio", Metric: RANK_BY_FAILURE_RATE, Threshold: 0.05, MinRelays: 100}},
+			expected: []Notification{
+				{Rule: "failure-ratio", Application: "app2", Metric: RANK_BY_FAILURE_RATE, Threshold: 0.05, Value: 0.1, Count: todaysUsage["app2"]},
+			},
+		},
+		{
+			name:  "No rule fires",
+			rules: []NotificationRule{{Name: "app2-failure", Application: "app2", Metric: RANK_BY_FAILURE, Threshold: 10}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := evaluateRules(tc.rules, todaysUsage)
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var (
+		mutex    sync.Mutex
+		received []Notification
+		failing  bool
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, _ := io.ReadAll(req.Body)
+		var n Notification
+		if err := json.Unmarshal(body, &n); err != nil {
+			t.Errorf("Unexpected error unmarshalling the notification: %v", err)
+		}
+		received = append(received, n)
+	}))
+	defer server.Close()
+
+	rules := []NotificationRule{{Name: "total", Metric: RANK_BY_TOTAL, Threshold: 100}}
+	n, err := NewWebhookNotifier(server.URL, rules, time.UTC, logger.New())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Rules are evaluated synchronously, skipping the queue, so the notifications can be checked after each call
+	notifier := n.(*webhookNotifier)
+
+	// The webhook fails: the notification is retried on the next call
+	setFailing := func(f bool) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		failing = f
+	}
+	// Usage loaded before midnight holds yesterday's totals: it is not evaluated
+	yesterday := time.Now().UTC().AddDate(0, 0, -1)
+	notifier.notify(map[string]RelayCounts{"app1": {Success: 200}}, yesterday)
+	if len(received) != 0 {
+		t.Fatalf("Expected no notifications on usage loaded yesterday, got: %v", received)
+	}
+
+	setFailing(true)
+	notifier.notify(map[string]RelayCounts{"app1": {Success: 200}}, time.Now())
+	if len(received) != 0 {
+		t.Fatalf("Expected no notifications, got: %v", received)
+	}
+
+	setFailing(false)
+	notifier.notify(map[string]RelayCounts{"app1": {Success: 200}}, time.Now())
+	// Already notified rule and application pairs are not notified again on the same day
+	notifier.notify(map[string]RelayCounts{"app1": {Success: 300}, "app2": {Success: 500}}, time.Now())
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	var got []string
+	for _, n := range received {
+		got = append(got, n.Application)
+	}
+	if diff := cmp.Diff([]string{"app1", "app2"}, got); diff != "" {
+		t.Errorf("unexpected notifications (-want +got):\n%s", diff)
+	}
+
+	today, _ := time.Parse(dayFormat, time.Now().UTC().Format(dayFormat))
+	if len(received) > 0 && !received[0].Day.Equal(today) {
+		t.Errorf("Expected day: %v, got: %v", today, received[0].Day)
+	}
+}
+
+func TestWebhookNotifierDoesNotBlock(t *testing.T) {
+	var (
+		mutex    sync.Mutex
+		received int
+	)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// A slow webhook: blocks until the test is done calling Notify
+		<-release
+		mutex.Lock()
+		defer mutex.Unlock()
+		received++
+	}))
+	defer server.Close()
+
+	rules := []NotificationRule{{Name: "total", Metric: RANK_BY_TOTAL, Threshold: 100}}
+	notifier, err := NewWebhookNotifier(server.URL, rules, time.UTC, logger.New())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// More refreshes than the queue holds: the extra ones are dropped rather than blocking the caller
+	start := time.Now()
+	for i := 0; i < WEBHOOK_QUEUE_SIZE+5; i++ {
+		notifier.Notify(map[string]RelayCounts{"app1": {Success: 200}}, time.Now())
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected Notify not to wait for the webhook, took: %v", elapsed)
+	}
+	close(release)
+
+	// The first refresh is posted once the webhook responds, the rest are already notified
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		mutex.Lock()
+		got := received
+		mutex.Unlock()
+		if got > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if received != 1 {
+		t.Errorf("Expected 1 notification, got: %d", received)
+	}
+}
+
+func TestNewWebhookNotifierInvalidRules(t *testing.T) {
+	testCases := []struct {
+		name  string
+		url   string
+		rules []NotificationRule
+	}{
+		{
+			name:  "Missing URL",
+			rules: []NotificationRule{{Name: "total", Metric: RANK_BY_TOTAL}},
+		},
+		{
+			name:  "Missing rule name",
+			url:   "http://localhost/webhook",
+			rules: []NotificationRule{{Metric: RANK_BY_TOTAL}},
+		},
+		{
+			name:  "Duplicate rule name",
+			url:   "http://localhost/webhook",
+			rules: []NotificationRule{{Name: "total", Metric: RANK_BY_TOTAL}, {Name: "total", Metric: RANK_BY_FAILURE}},
+		},
+		{
+			name:  "Invalid metric",
+			url:   "http://localhost/webhook",
+			rules: []NotificationRule{{Name: "total", Metric: "latency"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewWebhookNotifier(tc.url, tc.rules, time.UTC, logger.New()); err == nil {
+				t.Errorf("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestMeterNotifiesOnRefresh(t *testing.T) {
+	notifier := fakeNotifier{}
+	fakeBackend := fakeBackend{
+		usage:       fakeDailyMetrics(),
+		todaysUsage: fakeTodaysMetrics(),
+	}
+	NewRelayMeter(&fakeBackend, logger.New(), RelayMeterOptions{LoadInterval: 100 * time.Millisecond, Notifier: &notifier})
+	time.Sleep(200 * time.Millisecond)
+
+	notifier.mutex.Lock()
+	defer notifier.mutex.Unlock()
+	if diff := cmp.Diff(fakeTodaysMetrics(), notifier.todaysUsage); diff != "" {
+		t.Errorf("unexpected value (-want +got):\n%s", diff)
+	}
+	if notifier.lastUpdated.IsZero() {
+		t.Errorf("Expected the time of the refresh, got zero")
+	}
+}
+
+type fakeNotifier struct {
+	todaysUsage map[string]RelayCounts
+	lastUpdated time.Time
+	mutex       sync.Mutex
+}
+
+func (f *fakeNotifier) Notify(todaysUsage map[string]RelayCounts, lastUpdated time.Time) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.todaysUsage = todaysUsage
+	f.lastUpdated = lastUpdated
+}
@@ -0,0 +1,22 @@
+package api
+
+import (
+	_ "embed"
+	"net/http"
+
+	logger "github.com/sirupsen/logrus"
+)
+
+// openAPISpec is the OpenAPI 3 specification of the apiserver's routes.
+//	It needs to be updated alongside the routes and their response types: this is verified by the package's tests.
+//go:embed openapi.json
+var openAPISpec []byte
+
+// handleOpenAPISpec serves the OpenAPI specification, e.g. for generating API clients.
+func handleOpenAPISpec(l *logger.Logger, w http.ResponseWriter, req *http.Request) {
+	w.Header().Set(HEADER_CONTENT_TYPE, CONTENT_TYPE_JSON)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(openAPISpec); err != nil {
+		l.WithFields(logger.Fields{"error": err}).Warn("Error writing the OpenAPI specification")
+	}
+}
@@ -0,0 +1,345 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	logger "github.com/sirupsen/logrus"
+)
+
+// openAPIDocument holds the parts of the OpenAPI specification verified by the tests
+type openAPIDocument struct {
+	Paths map[string]struct {
+		Get  *openAPIOperation
+		Post *openAPIOperation
+	}
+	Components struct {
+		Parameters map[string]openAPIParameter
+		Schemas    map[string]openAPISchema
+	}
+}
+
+type openAPIOperation struct {
+	Parameters  []openAPIParameter
+	RequestBody struct {
+		Content map[string]struct {
+			Example json.RawMessage
+		}
+	}
+	Responses map[string]struct {
+		Content map[string]struct {
+			Schema openAPISchema
+		}
+	}
+}
+
+// openAPISchema holds the fields of schemas used by the tests
+type openAPISchema struct {
+	Ref                  string `json:"$ref"`
+	Type                 string
+	Nullable             bool
+	Properties           map[string]*openAPISchema
+	Required             []string
+	Items                *openAPISchema
+	AdditionalProperties *openAPISchema
+	AnyOf                []openAPISchema
+	Enum                 []any
+}
+
+type openAPIParameter struct {
+	Ref  string `json:"$ref"`
+	Name string
+	In   string
+}
+
+func parseOpenAPISpec(t *testing.T) openAPIDocument {
+	var spec openAPIDocument
+	if err := json.Unmarshal(openAPISpec, &spec); err != nil {
+		t.Fatalf("Unexpected error parsing the OpenAPI specification: %v", err)
+	}
+	return spec
+}
+
+// operation returns the operation specified for the route's method
+func (spec openAPIDocument) operation(r route) *openAPIOperation {
+	if r.method == http.MethodPost {
+		return spec.Paths[r.openAPIPath].Post
+	}
+	return spec.Paths[r.openAPIPath].Get
+}
+
+func TestOpenAPISpecRoutes(t *testing.T) {
+	spec := parseOpenAPISpec(t)
+
+	var specPaths, routePaths []string
+	for path := range spec.Paths {
+		specPaths = append(specPaths, path)
+	}
+	for _, r := range routes {
+		routePaths = append(routePaths, r.openAPIPath)
+	}
+	sort.Strings(specPaths)
+	sort.Strings(routePaths)
+	if diff := cmp.Diff(routePaths, specPaths); diff != "" {
+		t.Fatalf("OpenAPI paths do not match the routes (-routes +spec):\n%s", diff)
+	}
+
+	pathParameter := regexp.MustCompile(`{([[:alnum:]]+)}`)
+	for i, r := range routes {
+		operation := spec.operation(r)
+		if operation == nil {
+			t.Errorf("Path %s: missing operation for the route's method", r.openAPIPath)
+			continue
+		}
+
+		// Path parameters in the specification need to match the values captured by the route's regexp
+		specParameters := pathParameter.FindAllStringSubmatch(r.openAPIPath, -1)
+		if len(specParameters) != r.path.NumSubexp() {
+			t.Errorf("Path %s: expected %d path parameters, got: %d", r.openAPIPath, r.path.NumSubexp(), len(specParameters))
+		}
+		for _, p := range specParameters {
+			found := false
+			for _, param := range operation.Parameters {
+				if resolveParameter(spec, param).Name == p[1] {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("Path %s: path parameter %s is not specified", r.openAPIPath, p[1])
+			}
+		}
+
+		// The path is served by its route, and not by an earlier, more generic one
+		got, _ := matchRoute(pathParameter.ReplaceAllString(r.openAPIPath, "id1"))
+		if got != &routes[i] {
+			t.Errorf("Path %s: expected to be served by its route", r.openAPIPath)
+		}
+	}
+}
+
+// TestOpenAPISpecResponses verifies the responses of every route, as returned by the actual handlers, match their specified schemas.
+func TestOpenAPISpecResponses(t *testing.T) {
+	spec := parseOpenAPISpec(t)
+
+	now := time.Now()
+	counts := RelayCounts{Success: 5, Failure: 1, Chains: map[string]RelayCounts{"0021": {Success: 5, Failure: 1}}}
+	latency := &RelayLatency{Relays: 6, Average: 0.1}
+	usage := &QuotaUsage{Limit: 100, Used: 6, From: now, To: now, ProjectedExhaustion: &now}
+	notes := []string{"The time period ends on 2022-07-20 instead of 2022-07-22: no metrics are available after today"}
+	fakeMeter := fakeRelayMeter{
+		response:                   AppRelaysResponse{Count: counts, Latency: latency, From: now, To: now, Application: "app1", Notes: notes},
+		allResponse:                []AppRelaysResponse{{Count: counts, Latency: latency, From: now, To: now, Application: "app1"}},
+		loadbalancerRelaysResponse: LoadBalancerRelaysResponse{Count: counts, Latency: latency, From: now, To: now, Endpoint: "lb1", Applications: []string{"app1"}},
+		allLoadBalancersResponse:   []LoadBalancerRelaysResponse{{Count: counts, From: now, To: now, Endpoint: "lb1", Applications: []string{"app1"}}},
+		appQuotaResponse:           AppQuotaResponse{Application: "app1", Daily: usage},
+		userQuotaResponse:          UserQuotaResponse{User: "user1", Applications: []string{"app1"}, Monthly: usage},
+		comparisonResponse: RelaysComparisonResponse{
+			Current:     TotalRelaysResponse{Count: counts, From: now, To: now, Notes: notes},
+			Previous:    TotalRelaysResponse{Count: RelayCounts{Success: 2}, From: now, To: now},
+			Application: "app1",
+		},
+		bucketsResponse: []RelaysBucket{{From: now, To: now, Partial: true}},
+	}
+	httpServer := GetHttpServer(&fakeMeter, logger.New())
+
+	pathParameter := regexp.MustCompile(`{([[:alnum:]]+)}`)
+	for _, r := range routes {
+		path, operation := r.openAPIPath, spec.operation(r)
+		t.Run(path, func(t *testing.T) {
+			if operation == nil {
+				t.Fatalf("Missing operation for the route's method")
+			}
+			// Chains are included, so their breakdown is verified too. Endpoints supporting a granularity are also verified with buckets
+			queries := []string{"?groupBy=chain"}
+			for _, param := range operation.Parameters {
+				if resolveParameter(spec, param).Name == PARAMETER_GRANULARITY {
+					queries = append(queries, "?groupBy=chain&granularity=week")
+				}
+			}
+
+			for _, query := range queries {
+				// Requests with a body use the specification's example
+				method, reqBody := http.MethodGet, io.Reader(nil)
+				if r.method != "" {
+					method, reqBody = r.method, bytes.NewReader(operation.RequestBody.Content[CONTENT_TYPE_JSON].Example)
+				}
+				req := httptest.NewRequest(method, "http://relay-meter.pokt.network"+pathParameter.ReplaceAllString(path, "id1")+query, reqBody)
+				w := httptest.NewRecorder()
+				httpServer(w, req)
+
+				resp := w.Result()
+				if resp.StatusCode != http.StatusOK {
+					t.Fatalf("Query %s: expected status code: %d, got: %d", query, http.StatusOK, resp.StatusCode)
+				}
+				body, _ := io.ReadAll(resp.Body)
+				var value any
+				if err := json.Unmarshal(body, &value); err != nil {
+					t.Fatalf("Query %s: unexpected error unmarhsalling the response: %v", query, err)
+				}
+
+				content, ok := operation.Responses["200"].Content[CONTENT_TYPE_JSON]
+				if !ok {
+					t.Fatalf("Missing JSON response in the specification")
+				}
+				if err := validateSchema(spec, content.Schema, value, "response"); err != nil {
+					t.Errorf("Query %s: response does not match the specification: %v", query, err)
+				}
+			}
+		})
+	}
+}
+
+// TestOpenAPISpecErrorResponses verifies error responses, as returned by the actual handlers, match the specified error schema.
+func TestOpenAPISpecErrorResponses(t *testing.T) {
+	spec := parseOpenAPISpec(t)
+	schema := openAPISchema{Ref: "#/components/schemas/ErrorResponse"}
+
+	testCases := []struct {
+		name     string
+		method   string
+		path     string
+		meterErr error
+	}{
+		{name: "Invalid path", path: "/invalid-path"},
+		{name: "Incorrect method", method: http.MethodPost, path: "/v0/relays"},
+		{name: "Invalid timespan", path: "/v0/relays?from=yesterday"},
+		{name: "Load balancer not found", path: "/v0/relays/endpoints/lb1", meterErr: ErrLoadBalancerNotFound},
+		{name: "Backend unavailable", path: "/v0/relays/users/user1", meterErr: ErrBackendUnavailable},
+		{name: "Internal error", path: "/v0/relays", meterErr: fmt.Errorf("Internal meter error")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			method := tc.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			httpServer := GetHttpServer(&fakeRelayMeter{responseErr: tc.meterErr}, logger.New())
+			req := httptest.NewRequest(method, "http://relay-meter.pokt.network"+tc.path, nil)
+			w := httptest.NewRecorder()
+			httpServer(w, req)
+
+			body, _ := io.ReadAll(w.Result().Body)
+			var value any
+			if err := json.Unmarshal(body, &value); err != nil {
+				t.Fatalf("Unexpected error unmarhsalling the response: %v", err)
+			}
+			if err := validateSchema(spec, schema, value, "response"); err != nil {
+				t.Errorf("Response does not match the specification: %v", err)
+			}
+		})
+	}
+}
+
+func resolveParameter(spec openAPIDocument, param openAPIParameter) openAPIParameter {
+	if name := strings.TrimPrefix(param.Ref, "#/components/parameters/"); name != param.Ref {
+		return spec.Components.Parameters[name]
+	}
+	return param
+}
+
+// validateSchema verifies the unmarshalled JSON value matches the schema: only the schema features used by the specification are supported.
+func validateSchema(spec openAPIDocument, schema openAPISchema, value any, location string) error {
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		resolved, ok := spec.Components.Schemas[name]
+		if !ok {
+			return fmt.Errorf("%s: unknown schema reference: %s", location, schema.Ref)
+		}
+		return validateSchema(spec, resolved, value, location)
+	}
+	if len(schema.AnyOf) > 0 {
+		var errs []string
+		for _, s := range schema.AnyOf {
+			err := validateSchema(spec, s, value, location)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, err.Error())
+		}
+		return fmt.Errorf("%s: no matching schema: %s", location, strings.Join(errs, "; "))
+	}
+	if value == nil {
+		if schema.Nullable || schema.Type == "array" {
+			return nil
+		}
+		return fmt.Errorf("%s: unexpected null value", location)
+	}
+
+	switch schema.Type {
+	case "":
+		// Any value
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: expected an object, got: %v", location, value)
+		}
+		for _, field := range schema.Required {
+			if _, ok := object[field]; !ok {
+				return fmt.Errorf("%s: missing required field %s", location, field)
+			}
+		}
+		for field, fieldValue := range object {
+			fieldSchema, ok := schema.Properties[field]
+			if !ok {
+				fieldSchema = schema.AdditionalProperties
+			}
+			if fieldSchema == nil {
+				if len(schema.Properties) == 0 {
+					// Free-form object
+					continue
+				}
+				return fmt.Errorf("%s: unspecified field %s", location, field)
+			}
+			if err := validateSchema(spec, *fieldSchema, fieldValue, location+"."+field); err != nil {
+				return err
+			}
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("%s: expected an array, got: %v", location, value)
+		}
+		for i, item := range items {
+			if err := validateSchema(spec, *schema.Items, item, fmt.Sprintf("%s[%d]", location, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%s: expected a string, got: %v", location, value)
+		}
+		if len(schema.Enum) > 0 {
+			found := false
+			for _, e := range schema.Enum {
+				if e == value {
+					found = true
+				}
+			}
+			if !found {
+				return fmt.Errorf("%s: unexpected value: %v, expected one of: %v", location, value, schema.Enum)
+			}
+		}
+	case "integer", "number":
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("%s: expected a number, got: %v", location, value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: expected a boolean, got: %v", location, value)
+		}
+	default:
+		return fmt.Errorf("%s: unsupported schema type: %s", location, schema.Type)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+)
+
+// Quota holds the relay limits of an application or a user: a zero limit means there is no limit for the period.
+type Quota struct {
+	DailyLimit   int64
+	MonthlyLimit int64
+}
+
+// QuotaUsage reports the relays performed during a quota period, i.e. a day or a calendar month, against the period's limit.
+type QuotaUsage struct {
+	Limit     int64
+	Used      int64
+	Remaining int64
+	// UsedRatio is the ratio of used relays to the limit, e.g. 0.82 for 82% of the limit
+	UsedRatio float64
+	// Start and end of the quota period
+	From time.Time
+	To   time.Time
+	// ProjectedExhaustion is the time the limit is expected to be reached, assuming relays continue at the average rate of the period so far.
+	//	It is nil if the limit is not expected to be reached before the end of the period.
+	ProjectedExhaustion *time.Time
+}
+
+// AppQuotaResponse reports the usage of an application against its quota: periods with no limit are nil.
+type AppQuotaResponse struct {
+	Application string
+	Daily       *QuotaUsage
+	Monthly     *QuotaUsage
+}
+
+// UserQuotaResponse reports the usage of all of a user's applications against the user's quota: periods with no limit are nil.
+type UserQuotaResponse struct {
+	User         string
+	Applications []string
+	Daily        *QuotaUsage
+	Monthly      *QuotaUsage
+}
+
+// AppQuota returns the usage of the application against its daily and monthly limits.
+func (r *relayMeter) AppQuota(app string) (AppQuotaResponse, error) {
+	r.Logger.WithFields(logger.Fields{"app": app}).Info("apiserver: Received AppQuota request")
+	resp := AppQuotaResponse{Application: app}
+
+	quota, err := r.Backend.AppQuota(app)
+	if err != nil {
+		r.Logger.WithFields(logger.Fields{"app": app, "error": err}).Warn("Error getting application quota processing AppQuota request")
+		return resp, fmt.Errorf("%w: %v", ErrBackendUnavailable, err)
+	}
+	if quota == nil {
+		return resp, ErrQuotaNotFound
+	}
+
+	usage := func(from, to time.Time) (RelayCounts, error) {
+		appRelays, err := r.AppRelays(app, from, to)
+		return appRelays.Count, err
+	}
+	resp.Daily, resp.Monthly, err = evaluateQuota(*quota, time.Now().In(r.location()), usage)
+	return resp, err
+}
+
+// UserQuota returns the usage of all the user's applications against the user's daily and monthly limits.
+func (r *relayMeter) UserQuota(user string) (UserQuotaResponse, error) {
+	r.Logger.WithFields(logger.Fields{"user": user}).Info("apiserver: Received UserQuota request")
+	resp := UserQuotaResponse{User: user}
+
+	quota, err := r.Backend.UserQuota(user)
+	if err != nil {
+		r.Logger.WithFields(logger.Fields{"user": user, "error": err}).Warn("Error getting user quota processing UserQuota request")
+		return resp, fmt.Errorf("%w: %v", ErrBackendUnavailable, err)
+	}
+	if quota == nil {
+		return resp, ErrQuotaNotFound
+	}
+
+	usage := func(from, to time.Time) (RelayCounts, error) {
+		userRelays, err := r.UserRelays(user, from, to)
+		resp.Applications = userRelays.Applications
+		return userRelays.Count, err
+	}
+	resp.Daily, resp.Monthly, err = evaluateQuota(*quota, time.Now().In(r.location()), usage)
+	return resp, err
+}
+
+// evaluateQuota returns the usage for the daily and monthly periods containing now: the usage of a period with no limit is nil.
+//	The usage function is expected to follow the meter's conventions on time periods, i.e. 'to' is the last day to include.
+//	Days and months start at midnight in the location of now, i.e. the reporting timezone.
+//	Note: the monthly usage only includes the days loaded by the meter, see RelayMeterOptions.MaxPastDays.
+func evaluateQuota(quota Quota, now time.Time, usage func(from, to time.Time) (RelayCounts, error)) (*QuotaUsage, *QuotaUsage, error) {
+	today, _, err := AdjustTimePeriodIn(now, now, now.Location())
+	if err != nil {
+		return nil, nil, err
+	}
+	monthStart := today.AddDate(0, 0, 1-today.Day())
+
+	var daily, monthly *QuotaUsage
+	if quota.DailyLimit > 0 {
+		counts, err := usage(today, today)
+		if err != nil {
+			return nil, nil, err
+		}
+		daily = quotaUsage(quota.DailyLimit, counts.Total(), today, today.AddDate(0, 0, 1), now)
+	}
+	if quota.MonthlyLimit > 0 {
+		counts, err := usage(monthStart, today)
+		if err != nil {
+			return nil, nil, err
+		}
+		monthly = quotaUsage(quota.MonthlyLimit, counts.Total(), monthStart, monthStart.AddDate(0, 1, 0), now)
+	}
+	return daily, monthly, nil
+}
+
+func quotaUsage(limit, used int64, from, to, now time.Time) *QuotaUsage {
+	usage := &QuotaUsage{
+		Limit:     limit,
+		Used:      used,
+		UsedRatio: float64(used) / float64(limit),
+		From:      from,
+		To:        to,
+	}
+	if used >= limit {
+		exhausted := now
+		usage.ProjectedExhaustion = &exhausted
+		return usage
+	}
+	usage.Remaining = limit - used
+
+	elapsed := now.Sub(from)
+	if used == 0 || elapsed <= 0 {
+		return usage
+	}
+	// Time needed for the remaining relays, at the average rate of the period so far.
+	//	It is calculated in seconds, and compared with the rest of the period before conversion, as it can overflow a Duration,
+	//	e.g. for a large limit with low usage.
+	remainingSeconds := elapsed.Seconds() * float64(usage.Remaining) / float64(used)
+	if remainingSeconds < to.Sub(now).Seconds() {
+		exhaustion := now.Add(time.Duration(remainingSeconds * float64(time.Second)))
+		usage.ProjectedExhaustion = &exhaustion
+	}
+	return usage
+}
@@ -0,0 +1,233 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	logger "github.com/sirupsen/logrus"
+)
+
+func TestQuotaUsage(t *testing.T) {
+	from := time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	now := from.AddDate(0, 0, 10)
+	timePtr := func(t time.Time) *time.Time { return &t }
+
+	testCases := []struct {
+		name     string
+		limit    int64
+		used     int64
+		expected *QuotaUsage
+	}{
+		{
+			name:  "Limit is projected to be reached within the period",
+			limit: 1000,
+			used:  500,
+			expected: &QuotaUsage{
+				Limit:               1000,
+				Used:                500,
+				Remaining:           500,
+				UsedRatio:           0.5,
+				From:                from,
+				To:                  to,
+				ProjectedExhaustion: timePtr(from.AddDate(0, 0, 20)),
+			},
+		},
+		{
+			name:  "Limit is not projected to be reached within the period",
+			limit: 1000,
+			used:  100,
+			expected: &QuotaUsage{
+				Limit:     1000,
+				Used:      100,
+				Remaining: 900,
+				UsedRatio: 0.1,
+				From:      from,
+				To:        to,
+			},
+		},
+		{
+			name:  "Large limit with low usage is not projected to be reached",
+			limit: 1e9,
+			used:  1000,
+			expected: &QuotaUsage{
+				Limit:     1e9,
+				Used:      1000,
+				Remaining: 1e9 - 1000,
+				UsedRatio: 1e-6,
+				From:      from,
+				To:        to,
+			},
+		},
+		{
+			name:  "No relays in the period",
+			limit: 1000,
+			expected: &QuotaUsage{
+				Limit:     1000,
+				Remaining: 1000,
+				From:      from,
+				To:        to,
+			},
+		},
+		{
+			name:  "Exhausted limit",
+			limit: 1000,
+			used:  1200,
+			expected: &QuotaUsage{
+				Limit:               1000,
+				Used:                1200,
+				UsedRatio:           1.2,
+				From:                from,
+				To:                  to,
+				ProjectedExhaustion: timePtr(now),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := quotaUsage(tc.limit, tc.used, from, to, now)
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAppQuota(t *testing.T) {
+	today, _ := time.Parse(dayFormat, time.Now().UTC().Format(dayFormat))
+	monthStart := today.AddDate(0, 0, 1-today.Day())
+	// The fake daily metrics cover the 6 days before today: only those within the current month count towards the monthly limit
+	monthDays := int64(today.Day() - 1)
+	if monthDays > 6 {
+		monthDays = 6
+	}
+
+	testCases := []struct {
+		name            string
+		app             string
+		expectedDaily   *QuotaUsage
+		expectedMonthly *QuotaUsage
+		expectedErr     error
+	}{
+		{
+			name: "Daily and monthly limits are evaluated",
+			app:  "app1",
+			expectedDaily: &QuotaUsage{
+				Limit:     1000,
+				Used:      50 + 40,
+				Remaining: 1000 - 90,
+				From:      today,
+				To:        today.AddDate(0, 0, 1),
+			},
+			expectedMonthly: &QuotaUsage{
+				Limit:     10000,
+				Used:      monthDays*(2+3) + 90,
+				Remaining: 10000 - monthDays*(2+3) - 90,
+				From:      monthStart,
+				To:        monthStart.AddDate(0, 1, 0),
+			},
+		},
+		{
+			name: "Period with no limit is not evaluated",
+			app:  "app2",
+			expectedMonthly: &QuotaUsage{
+				Limit:     5000,
+				Used:      monthDays*(1+5) + 100,
+				Remaining: 5000 - monthDays*(1+5) - 100,
+				From:      monthStart,
+				To:        monthStart.AddDate(0, 1, 0),
+			},
+		},
+		{
+			name:        "Application with no quota returns an error",
+			app:         "app4",
+			expectedErr: ErrQuotaNotFound,
+		},
+	}
+
+	fakeBackend := fakeBackend{
+		usage:       fakeDailyMetrics(),
+		todaysUsage: fakeTodaysMetrics(),
+		appQuotas: map[string]*Quota{
+			"app1": {DailyLimit: 1000, MonthlyLimit: 10000},
+			"app2": {MonthlyLimit: 5000},
+		},
+	}
+	meter := NewRelayMeter(&fakeBackend, logger.New(), RelayMeterOptions{LoadInterval: 100 * time.Millisecond})
+	time.Sleep(200 * time.Millisecond)
+
+	// The ratio and the projection depend on the current time
+	ignoreProjection := cmp.Transformer("ignoreProjection", func(u QuotaUsage) QuotaUsage {
+		u.UsedRatio = 0
+		u.ProjectedExhaustion = nil
+		return u
+	})
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := meter.AppQuota(tc.app)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("Expected error: %v, got: %v", tc.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if got.Application != tc.app {
+				t.Errorf("Expected application: %s, got: %s", tc.app, got.Application)
+			}
+			if diff := cmp.Diff(tc.expectedDaily, got.Daily, ignoreProjection); diff != "" {
+				t.Errorf("unexpected daily usage (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedMonthly, got.Monthly, ignoreProjection); diff != "" {
+				t.Errorf("unexpected monthly usage (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUserQuota(t *testing.T) {
+	today, _ := time.Parse(dayFormat, time.Now().UTC().Format(dayFormat))
+
+	fakeBackend := fakeBackend{
+		usage:       fakeDailyMetrics(),
+		todaysUsage: fakeTodaysMetrics(),
+		userApps: map[string][]string{
+			"user1": {"app1", "app2"},
+		},
+		userQuotas: map[string]*Quota{
+			"user1": {DailyLimit: 100},
+		},
+	}
+	meter := NewRelayMeter(&fakeBackend, logger.New(), RelayMeterOptions{LoadInterval: 100 * time.Millisecond})
+	time.Sleep(200 * time.Millisecond)
+
+	got, err := meter.UserQuota("user1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got.Daily == nil {
+		t.Fatalf("Expected daily usage, got nil")
+	}
+	expected := UserQuotaResponse{
+		User:         "user1",
+		Applications: []string{"app1", "app2"},
+		Daily: &QuotaUsage{
+			Limit:               100,
+			Used:                50 + 40 + 30 + 70,
+			UsedRatio:           1.9,
+			From:                today,
+			To:                  today.AddDate(0, 0, 1),
+			ProjectedExhaustion: got.Daily.ProjectedExhaustion,
+		},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected value (-want +got):\n%s", diff)
+	}
+
+	if _, err := meter.UserQuota("user2"); !errors.Is(err, ErrQuotaNotFound) {
+		t.Errorf("Expected error: %v, got: %v", ErrQuotaNotFound, err)
+	}
+}
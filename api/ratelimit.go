@@ -0,0 +1,159 @@
+package api
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+)
+
+const (
+	HEADER_RETRY_AFTER     = "Retry-After"
+	HEADER_X_FORWARDED_FOR = "X-Forwarded-For"
+
+	// Rate limit classes of the routes: list routes iterate over all applications or load balancers, and are the most expensive to serve.
+	RATE_LIMIT_CLASS_DEFAULT = "default"
+	RATE_LIMIT_CLASS_LIST    = "list"
+
+	// Buckets of clients with no requests for this long are removed, to keep memory usage bounded
+	RATE_LIMIT_CLEANUP_INTERVAL = 10 * time.Minute
+)
+
+// RateLimit is a token bucket: up to Burst requests are allowed at once, refilled at RequestsPerSecond.
+type RateLimit struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+type RateLimiterOptions struct {
+	// Limits per route class, e.g. RATE_LIMIT_CLASS_LIST: routes of a class with no limit are not rate limited.
+	Limits map[string]RateLimit
+	// Use the last address of the X-Forwarded-For header, if set, as the client's IP: only enable this behind a trusted proxy.
+	//	The last address is the one appended by the proxy: the ones before it are set by the client, which could rotate them to get a new bucket.
+	TrustForwardedFor bool
+	// Authenticator verifies the credentials of requests: only clients with valid credentials are rate limited per credential,
+	//	all others per IP, so rotating invalid credentials does not bypass the limit. If nil, all clients are rate limited per IP.
+	Authenticator Authenticator
+}
+
+// RateLimiter keeps a token bucket per client identity, i.e. verified API key or token, or IP, and route class.
+type RateLimiter struct {
+	options RateLimiterOptions
+
+	mutex       sync.Mutex
+	buckets     map[string]*tokenBucket
+	lastCleanup time.Time
+}
+
+func NewRateLimiter(options RateLimiterOptions) (*RateLimiter, error) {
+	for class, limit := range options.Limits {
+		if limit.RequestsPerSecond <= 0 || limit.Burst < 1 {
+			return nil, fmt.Errorf("Invalid rate limit for class %s: requests per second and burst need to be positive: %+v", class, limit)
+		}
+	}
+	return &RateLimiter{
+		options:     options,
+		buckets:     make(map[string]*tokenBucket),
+		lastCleanup: time.Now(),
+	}, nil
+}
+
+type tokenBucket struct {
+	tokens     float64
+	lastUpdate time.Time
+}
+
+// take removes a token from the bucket if one is available: otherwise the time until the next token is available is returned.
+func (b *tokenBucket) take(limit RateLimit, now time.Time) (bool, time.Duration) {
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.lastUpdate).Seconds()*limit.RequestsPerSecond)
+	b.lastUpdate = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / limit.RequestsPerSecond * float64(time.Second))
+}
+
+// Allow returns true if the client is allowed another request on the route class: otherwise the time to wait before retrying is returned.
+func (r *RateLimiter) Allow(client, class string, now time.Time) (bool, time.Duration) {
+	limit, ok := r.options.Limits[class]
+	if !ok {
+		return true, 0
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if now.Sub(r.lastCleanup) >= RATE_LIMIT_CLEANUP_INTERVAL {
+		for key, b := range r.buckets {
+			if now.Sub(b.lastUpdate) >= RATE_LIMIT_CLEANUP_INTERVAL {
+				delete(r.buckets, key)
+			}
+		}
+		r.lastCleanup = now
+	}
+
+	key := class + "|" + client
+	b, ok := r.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: float64(limit.Burst), lastUpdate: now}
+		r.buckets[key] = b
+	}
+	return b.take(limit, now)
+}
+
+// clientIdentity returns the API key or bearer token of the request, if verified by the authenticator, otherwise the client's IP.
+//	Credentials are hashed, so they are not kept in memory. Requests with invalid credentials share the limit of their IP,
+//	so clients cannot get a new bucket, nor grow the limiter's memory, by sending made-up credentials.
+func (r *RateLimiter) clientIdentity(req *http.Request) string {
+	key := req.Header.Get(HEADER_API_KEY)
+	if key == "" {
+		key = bearerToken(req)
+	}
+	if key != "" && r.options.Authenticator != nil {
+		if _, err := r.options.Authenticator.Authenticate(req); err == nil {
+			return fmt.Sprintf("key:%x", sha256.Sum256([]byte(key)))
+		}
+	}
+
+	if r.options.TrustForwardedFor {
+		if forwardedFor := req.Header.Get(HEADER_X_FORWARDED_FOR); forwardedFor != "" {
+			addresses := strings.Split(forwardedFor, ",")
+			return "ip:" + strings.TrimSpace(addresses[len(addresses)-1])
+		}
+	}
+	ip, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		ip = req.RemoteAddr
+	}
+	return "ip:" + ip
+}
+
+// RequireRateLimit returns a handler which rejects requests over the client's rate limit for the route's class with a 429 status code,
+//	and passes all other requests to the next handler, e.g. the one returned by RequireAuthorization.
+func RequireRateLimit(next func(w http.ResponseWriter, req *http.Request), limiter *RateLimiter, l *logger.Logger) func(w http.ResponseWriter, req *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		class := RATE_LIMIT_CLASS_DEFAULT
+		if r, _ := matchRoute(req.URL.Path); r != nil && r.rateLimitClass != "" {
+			class = r.rateLimitClass
+		}
+
+		allowed, retryAfter := limiter.Allow(limiter.clientIdentity(req), class, time.Now())
+		if !allowed {
+			l.WithFields(logger.Fields{"path": req.URL.Path, "class": class, "retryAfter": retryAfter}).Warn("Rate limit exceeded")
+			// Retry-After is specified in whole seconds
+			w.Header().Set(HEADER_RETRY_AFTER, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			WriteError(l, w, http.StatusTooManyRequests, ERROR_CODE_RATE_LIMITED, "Too many requests")
+			return
+		}
+
+		next(w, req)
+	}
+}
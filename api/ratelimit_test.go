@@ -0,0 +1,201 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	limiter, err := NewRateLimiter(RateLimiterOptions{
+		Limits: map[string]RateLimit{RATE_LIMIT_CLASS_DEFAULT: {RequestsPerSecond: 2, Burst: 3}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	now := time.Now()
+
+	testCases := []struct {
+		name               string
+		client             string
+		class              string
+		elapsed            time.Duration
+		expected           bool
+		expectedRetryAfter time.Duration
+	}{
+		{name: "First request of the burst", client: "client1", class: RATE_LIMIT_CLASS_DEFAULT, expected: true},
+		{name: "Second request of the burst", client: "client1", class: RATE_LIMIT_CLASS_DEFAULT, expected: true},
+		{name: "Last request of the burst", client: "client1", class: RATE_LIMIT_CLASS_DEFAULT, expected: true},
+		{name: "Over the limit", client: "client1", class: RATE_LIMIT_CLASS_DEFAULT, expectedRetryAfter: 500 * time.Millisecond},
+		{name: "Other clients have their own bucket", client: "client2", class: RATE_LIMIT_CLASS_DEFAULT, expected: true},
+		{name: "Classes with no limit are not rate limited", client: "client1", class: RATE_LIMIT_CLASS_LIST, expected: true},
+		{name: "Partially refilled", client: "client1", class: RATE_LIMIT_CLASS_DEFAULT, elapsed: 250 * time.Millisecond, expectedRetryAfter: 250 * time.Millisecond},
+		{name: "Refilled", client: "client1", class: RATE_LIMIT_CLASS_DEFAULT, elapsed: 500 * time.Millisecond, expected: true},
+		{name: "Refilled tokens are used up", client: "client1", class: RATE_LIMIT_CLASS_DEFAULT, expectedRetryAfter: 250 * time.Millisecond},
+	}
+
+	for _, tc := range testCases {
+		now = now.Add(tc.elapsed)
+		allowed, retryAfter := limiter.Allow(tc.client, tc.class, now)
+		if allowed != tc.expected {
+			t.Fatalf("%s: expected allowed: %t, got: %t", tc.name, tc.expected, allowed)
+		}
+		if retryAfter != tc.expectedRetryAfter {
+			t.Errorf("%s: expected retry after: %v, got: %v", tc.name, tc.expectedRetryAfter, retryAfter)
+		}
+	}
+
+	if _, err := NewRateLimiter(RateLimiterOptions{Limits: map[string]RateLimit{RATE_LIMIT_CLASS_LIST: {RequestsPerSecond: 1}}}); err == nil {
+		t.Errorf("Expected an error for a rate limit with no burst")
+	}
+}
+
+func TestClientIdentityForwardedFor(t *testing.T) {
+	limiter, err := NewRateLimiter(RateLimiterOptions{TrustForwardedFor: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		name         string
+		forwardedFor string
+		expected     string
+	}{
+		{
+			name:     "Remote address without the header",
+			expected: "ip:10.0.0.9",
+		},
+		{
+			name:         "Address appended by the proxy",
+			forwardedFor: "192.168.1.1",
+			expected:     "ip:192.168.1.1",
+		},
+		{
+			name:         "Addresses set by the client are ignored",
+			forwardedFor: "1.2.3.4, 5.6.7.8, 192.168.1.1",
+			expected:     "ip:192.168.1.1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://relay-meter.pokt.network/v0/relays", nil)
+			req.RemoteAddr = "10.0.0.9:1234"
+			if tc.forwardedFor != "" {
+				req.Header.Set(HEADER_X_FORWARDED_FOR, tc.forwardedFor)
+			}
+			if got := limiter.clientIdentity(req); got != tc.expected {
+				t.Errorf("Expected: %s, got: %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestRequireRateLimit(t *testing.T) {
+	authenticator, err := NewAPIKeyAuthenticator([]APIKey{
+		{Key: "key1", User: "user1"},
+		{Key: "key2", User: "user2"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	limiter, err := NewRateLimiter(RateLimiterOptions{
+		Limits: map[string]RateLimit{
+			RATE_LIMIT_CLASS_DEFAULT: {RequestsPerSecond: 1, Burst: 2},
+			RATE_LIMIT_CLASS_LIST:    {RequestsPerSecond: 0.1, Burst: 1},
+		},
+		Authenticator: authenticator,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	handler := RequireRateLimit(GetHttpServer(&fakeRelayMeter{}, logger.New()), limiter, logger.New())
+
+	testCases := []struct {
+		name               string
+		path               string
+		apiKey             string
+		remoteAddr         string
+		expectedStatus     int
+		expectedRetryAfter string
+	}{
+		{
+			name:           "List route within the limit",
+			path:           "/v0/relays/apps",
+			apiKey:         "key1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:               "List route over the limit",
+			path:               "/v0/relays/endpoints",
+			apiKey:             "key1",
+			expectedStatus:     http.StatusTooManyRequests,
+			expectedRetryAfter: "10",
+		},
+		{
+			name:           "Other route classes have their own limit",
+			path:           "/v0/relays/apps/app1",
+			apiKey:         "key1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Other API keys have their own limit",
+			path:           "/v0/relays/apps",
+			apiKey:         "key2",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Clients with no API key are identified by IP",
+			path:           "/v0/relays/apps",
+			remoteAddr:     "10.0.0.1:1234",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:               "Requests from the same IP share the limit",
+			path:               "/v0/relays/apps",
+			remoteAddr:         "10.0.0.1:5678",
+			expectedStatus:     http.StatusTooManyRequests,
+			expectedRetryAfter: "10",
+		},
+		{
+			name:           "Clients with an invalid API key are identified by IP",
+			path:           "/v0/relays/apps",
+			apiKey:         "made-up-key1",
+			remoteAddr:     "10.0.0.2:1234",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:               "Rotating invalid API keys does not bypass the limit",
+			path:               "/v0/relays/apps",
+			apiKey:             "made-up-key2",
+			remoteAddr:         "10.0.0.2:1234",
+			expectedStatus:     http.StatusTooManyRequests,
+			expectedRetryAfter: "10",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://relay-meter.pokt.network"+tc.path, nil)
+			if tc.apiKey != "" {
+				req.Header.Set(HEADER_API_KEY, tc.apiKey)
+			}
+			if tc.remoteAddr != "" {
+				req.RemoteAddr = tc.remoteAddr
+			}
+			w := httptest.NewRecorder()
+			handler(w, req)
+
+			resp := w.Result()
+			if resp.StatusCode != tc.expectedStatus {
+				t.Fatalf("Expected status code: %d, got: %d", tc.expectedStatus, resp.StatusCode)
+			}
+			if got := resp.Header.Get(HEADER_RETRY_AFTER); got != tc.expectedRetryAfter {
+				t.Errorf("Expected %s: %q, got: %q", HEADER_RETRY_AFTER, tc.expectedRetryAfter, got)
+			}
+		})
+	}
+}
@@ -2,10 +2,11 @@ package api
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"regexp"
+	"strconv"
 	"time"
 
 	logger "github.com/sirupsen/logrus"
@@ -15,16 +16,36 @@ const (
 	DATE_LAYOUT    = time.RFC3339
 	PARAMETER_FROM = "from"
 	PARAMETER_TO   = "to"
+	PARAMETER_N    = "n"
+	PARAMETER_BY   = "by"
+	// Timezone, as an IANA name, e.g. America/New_York, whose dates the from and to parameters refer to: see timePeriod
+	PARAMETER_TZ = "tz"
+	// Splits the response into buckets, e.g. one per calendar month: see byGranularity
+	PARAMETER_GRANULARITY = "granularity"
+
+	// Maximum size of the body of batch requests
+	MAX_BATCH_REQUEST_BYTES = 1 << 20
 )
 
 var (
 	// TODO: should we limit the length of application public key or user id in the path regexp?
-	appsRelaysPath    = regexp.MustCompile(`^/v0/relays/apps/([[:alnum:]]+)$`)
-	allAppsRelaysPath = regexp.MustCompile(`^/v0/relays/apps`)
-	usersRelaysPath   = regexp.MustCompile(`^/v0/relays/users/([[:alnum:]]+)$`)
-	lbRelaysPath      = regexp.MustCompile(`^/v0/relays/endpoints/([[:alnum:]]+)$`)
-	allLbsRelaysPath  = regexp.MustCompile(`^/v0/relays/endpoints`)
-	totalRelaysPath   = regexp.MustCompile(`^/v0/relays`)
+	topAppsRelaysPath      = regexp.MustCompile(`^/v0/relays/apps/top$`)
+	appsRelaysPath         = regexp.MustCompile(`^/v0/relays/apps/([[:alnum:]]+)$`)
+	appDailyRelaysPath     = regexp.MustCompile(`^/v0/relays/apps/([[:alnum:]]+)/daily$`)
+	appTodaysRelaysPath    = regexp.MustCompile(`^/v0/relays/apps/([[:alnum:]]+)/today$`)
+	appCompareRelaysPath   = regexp.MustCompile(`^/v0/relays/apps/([[:alnum:]]+)/compare$`)
+	batchAppsRelaysPath    = regexp.MustCompile(`^/v0/relays/apps:batch$`)
+	allAppsRelaysPath      = regexp.MustCompile(`^/v0/relays/apps`)
+	usersRelaysPath        = regexp.MustCompile(`^/v0/relays/users/([[:alnum:]]+)$`)
+	userCompareRelaysPath  = regexp.MustCompile(`^/v0/relays/users/([[:alnum:]]+)/compare$`)
+	lbRelaysPath           = regexp.MustCompile(`^/v0/relays/endpoints/([[:alnum:]]+)$`)
+	lbCompareRelaysPath    = regexp.MustCompile(`^/v0/relays/endpoints/([[:alnum:]]+)/compare$`)
+	allLbsRelaysPath       = regexp.MustCompile(`^/v0/relays/endpoints`)
+	totalCompareRelaysPath = regexp.MustCompile(`^/v0/relays/compare$`)
+	totalRelaysPath        = regexp.MustCompile(`^/v0/relays`)
+	appQuotaPath           = regexp.MustCompile(`^/v0/quota/apps/([[:alnum:]]+)$`)
+	userQuotaPath          = regexp.MustCompile(`^/v0/quota/users/([[:alnum:]]+)$`)
+	openAPISpecPath        = regexp.MustCompile(`^/v0/openapi\.json$`)
 )
 
 // TODO: move these custom error codes to the api package
@@ -33,101 +54,418 @@ type ApiError error
 var (
 	AppNotFound    ApiError = fmt.Errorf("Application not found")
 	InvalidRequest ApiError = fmt.Errorf("Invalid request")
+	NotAcceptable  ApiError = fmt.Errorf("Not acceptable")
 )
 
-type ErrorResponse struct {
-	Message string
+// BatchAppRelaysRequest is the body of batch requests: applications with no time period use the from and to query parameters, if set.
+type BatchAppRelaysRequest struct {
+	Applications []AppRelaysRequest
 }
 
 func handleAppRelays(meter RelayMeter, l *logger.Logger, app string, w http.ResponseWriter, req *http.Request) {
 	meterEndpoint := func(from, to time.Time) (any, error) {
-		return meter.AppRelays(app, from, to)
+		options, err := parseChainOptions(req)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := meter.AppRelays(app, from, to)
+		resp.Count = options.apply(resp.Count)
+		resp.Latency = options.applyLatency(resp.Latency)
+		return resp, err
+	}
+	handleEndpoint(l, meter.LastUpdated(), byGranularity(meter, req, meterEndpoint), w, req)
+}
+
+func handleAppDailyRelays(meter RelayMeter, l *logger.Logger, app string, w http.ResponseWriter, req *http.Request) {
+	meterEndpoint := func(from, to time.Time) (any, error) {
+		options, err := parseChainOptions(req)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := meter.AppDailyRelays(app, from, to)
+		for i := range resp {
+			resp[i].Count = options.apply(resp[i].Count)
+			resp[i].Latency = options.applyLatency(resp[i].Latency)
+		}
+		return resp, err
 	}
-	handleEndpoint(l, meterEndpoint, w, req)
+	handleEndpoint(l, meter.LastUpdated(), byGranularity(meter, req, meterEndpoint), w, req)
 }
 
+func handleAppTodaysRelays(meter RelayMeter, l *logger.Logger, app string, w http.ResponseWriter, req *http.Request) {
+	meterEndpoint := func(from, to time.Time) (any, error) {
+		if req.URL.Query().Get(PARAMETER_GRANULARITY) != "" {
+			return nil, fmt.Errorf("%w: the %s parameter is not supported by this endpoint", InvalidRequest, PARAMETER_GRANULARITY)
+		}
+		options, err := parseChainOptions(req)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := meter.AppTodaysRelays(app)
+		for i := range resp {
+			resp[i].Count = options.apply(resp[i].Count)
+			resp[i].Latency = options.applyLatency(resp[i].Latency)
+		}
+		return resp, err
+	}
+	handleEndpoint(l, meter.LastUpdated(), meterEndpoint, w, req)
+}
+
+// handleAllAppsRelays serves the relays of all applications, with optional pagination, sorting and filtering: see parseListOptions
 func handleAllAppsRelays(meter RelayMeter, l *logger.Logger, w http.ResponseWriter, req *http.Request) {
 	meterEndpoint := func(from, to time.Time) (any, error) {
-		return meter.AllAppsRelays(from, to)
+		options, err := parseListOptions(req, PARAMETER_APPS)
+		if err != nil {
+			return nil, err
+		}
+		chainOptions, err := parseChainOptions(req)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := meter.AllAppsRelays(from, to)
+		if err != nil {
+			return nil, err
+		}
+		// Applied before the list options, so items are sorted and filtered on the requested chain's counts
+		for i := range resp {
+			resp[i].Count = chainOptions.apply(resp[i].Count)
+			resp[i].Latency = chainOptions.applyLatency(resp[i].Latency)
+		}
+
+		page, nextCursor := applyListOptions(resp,
+			func(r AppRelaysResponse) string { return r.Application },
+			func(r AppRelaysResponse) RelayCounts { return r.Count },
+			options,
+		)
+		if nextCursor != "" {
+			w.Header().Set(HEADER_NEXT_CURSOR, nextCursor)
+		}
+		return page, nil
 	}
-	handleEndpoint(l, meterEndpoint, w, req)
+	handleEndpoint(l, meter.LastUpdated(), byGranularity(meter, req, meterEndpoint), w, req)
+}
+
+// handleBatchAppRelays serves the relays of the applications listed in the request's body, see BatchAppRelaysRequest
+func handleBatchAppRelays(meter RelayMeter, l *logger.Logger, w http.ResponseWriter, req *http.Request) {
+	meterEndpoint := func(from, to time.Time) (any, error) {
+		if req.URL.Query().Get(PARAMETER_GRANULARITY) != "" {
+			return nil, fmt.Errorf("%w: the %s parameter is not supported by this endpoint", InvalidRequest, PARAMETER_GRANULARITY)
+		}
+		chainOptions, err := parseChainOptions(req)
+		if err != nil {
+			return nil, err
+		}
+
+		var batch BatchAppRelaysRequest
+		decoder := json.NewDecoder(http.MaxBytesReader(w, req.Body, MAX_BATCH_REQUEST_BYTES))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&batch); err != nil {
+			return nil, fmt.Errorf("%w: invalid request body: %v", InvalidRequest, err)
+		}
+		for i := range batch.Applications {
+			if batch.Applications[i].From.IsZero() {
+				batch.Applications[i].From = from
+			}
+			if batch.Applications[i].To.IsZero() {
+				batch.Applications[i].To = to
+			}
+		}
+
+		resp, err := meter.BatchAppRelays(batch.Applications)
+		for i := range resp {
+			resp[i].Count = chainOptions.apply(resp[i].Count)
+			resp[i].Latency = chainOptions.applyLatency(resp[i].Latency)
+		}
+		return resp, err
+	}
+	// Responses depend on the request's body, so they are not cacheable
+	handleEndpoint(l, time.Time{}, meterEndpoint, w, req)
+}
+
+func handleTopAppsRelays(meter RelayMeter, l *logger.Logger, w http.ResponseWriter, req *http.Request) {
+	meterEndpoint := func(from, to time.Time) (any, error) {
+		var n int
+		if nParameter := req.URL.Query().Get(PARAMETER_N); nParameter != "" {
+			var err error
+			n, err = strconv.Atoi(nParameter)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid %s parameter: %v", InvalidRequest, PARAMETER_N, err)
+			}
+		}
+		by := RankBy(req.URL.Query().Get(PARAMETER_BY))
+
+		options, err := parseChainOptions(req)
+		if err != nil {
+			return nil, err
+		}
+		if options.chain == "" {
+			resp, err := meter.TopAppsRelays(from, to, n, by)
+			for i := range resp {
+				resp[i].Count = options.apply(resp[i].Count)
+				resp[i].Latency = options.applyLatency(resp[i].Latency)
+			}
+			return resp, err
+		}
+
+		// Applications are ranked on the requested chain's counts
+		apps, err := meter.AllAppsRelays(from, to)
+		if err != nil {
+			return nil, err
+		}
+		for i := range apps {
+			apps[i].Count = options.apply(apps[i].Count)
+			apps[i].Latency = options.applyLatency(apps[i].Latency)
+		}
+		return topApps(apps, n, by)
+	}
+	handleEndpoint(l, meter.LastUpdated(), byGranularity(meter, req, meterEndpoint), w, req)
 }
 
 func handleUserRelays(meter RelayMeter, l *logger.Logger, user string, w http.ResponseWriter, req *http.Request) {
 	meterEndpoint := func(from, to time.Time) (any, error) {
-		return meter.UserRelays(user, from, to)
+		options, err := parseChainOptions(req)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := meter.UserRelays(user, from, to)
+		resp.Count = options.apply(resp.Count)
+		return resp, err
 	}
-	handleEndpoint(l, meterEndpoint, w, req)
+	handleEndpoint(l, meter.LastUpdated(), byGranularity(meter, req, meterEndpoint), w, req)
 }
 
 func handleLoadBalancerRelays(meter RelayMeter, l *logger.Logger, endpoint string, w http.ResponseWriter, req *http.Request) {
 	meterEndpoint := func(from, to time.Time) (any, error) {
-		return meter.LoadBalancerRelays(endpoint, from, to)
+		options, err := parseChainOptions(req)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := meter.LoadBalancerRelays(endpoint, from, to)
+		resp.Count = options.apply(resp.Count)
+		resp.Latency = options.applyLatency(resp.Latency)
+		return resp, err
 	}
-	handleEndpoint(l, meterEndpoint, w, req)
+	handleEndpoint(l, meter.LastUpdated(), byGranularity(meter, req, meterEndpoint), w, req)
 }
 
+// handleAllLoadBalancersRelays serves the relays of all load balancers, with optional pagination, sorting and filtering: see parseListOptions
 func handleAllLoadBalancersRelays(meter RelayMeter, l *logger.Logger, w http.ResponseWriter, req *http.Request) {
 	meterEndpoint := func(from, to time.Time) (any, error) {
-		return meter.AllLoadBalancersRelays(from, to)
+		options, err := parseListOptions(req, PARAMETER_ENDPOINTS)
+		if err != nil {
+			return nil, err
+		}
+		chainOptions, err := parseChainOptions(req)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := meter.AllLoadBalancersRelays(from, to)
+		if err != nil {
+			return nil, err
+		}
+		// Applied before the list options, so items are sorted and filtered on the requested chain's counts
+		for i := range resp {
+			resp[i].Count = chainOptions.apply(resp[i].Count)
+			resp[i].Latency = chainOptions.applyLatency(resp[i].Latency)
+		}
+
+		page, nextCursor := applyListOptions(resp,
+			func(r LoadBalancerRelaysResponse) string { return r.Endpoint },
+			func(r LoadBalancerRelaysResponse) RelayCounts { return r.Count },
+			options,
+		)
+		if nextCursor != "" {
+			w.Header().Set(HEADER_NEXT_CURSOR, nextCursor)
+		}
+		return page, nil
 	}
-	handleEndpoint(l, meterEndpoint, w, req)
+	handleEndpoint(l, meter.LastUpdated(), byGranularity(meter, req, meterEndpoint), w, req)
 }
 
 func handleTotalRelays(meter RelayMeter, l *logger.Logger, w http.ResponseWriter, req *http.Request) {
 	meterEndpoint := func(from, to time.Time) (any, error) {
-		return meter.TotalRelays(from, to)
+		options, err := parseChainOptions(req)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := meter.TotalRelays(from, to)
+		resp.Count = options.apply(resp.Count)
+		return resp, err
 	}
-	handleEndpoint(l, meterEndpoint, w, req)
+	handleEndpoint(l, meter.LastUpdated(), byGranularity(meter, req, meterEndpoint), w, req)
 }
 
-func handleEndpoint(l *logger.Logger, meterEndpoint func(from, to time.Time) (any, error), w http.ResponseWriter, req *http.Request) {
+// handleCompareRelays serves the comparison of the relays of the requested time period with the previous one, as returned by the compare function.
+//
+//	The chain options are applied to both time periods before computing their changes.
+func handleCompareRelays(meter RelayMeter, l *logger.Logger, compare func(from, to time.Time) (RelaysComparisonResponse, error), w http.ResponseWriter, req *http.Request) {
+	meterEndpoint := func(from, to time.Time) (any, error) {
+		options, err := parseChainOptions(req)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := compare(from, to)
+		if err != nil {
+			return nil, err
+		}
+		resp.Current.Count = options.apply(resp.Current.Count)
+		resp.Previous.Count = options.apply(resp.Previous.Count)
+		resp.Delta = newRelaysDelta(resp.Current.Count, resp.Previous.Count)
+		return resp, nil
+	}
+	handleEndpoint(l, meter.LastUpdated(), byGranularity(meter, req, meterEndpoint), w, req)
+}
+
+func handleAppQuota(meter RelayMeter, l *logger.Logger, app string, w http.ResponseWriter, req *http.Request) {
+	meterEndpoint := func(from, to time.Time) (any, error) {
+		return meter.AppQuota(app)
+	}
+	// Quotas are read from the backend on every request, and their projections depend on the current time
+	handleEndpoint(l, time.Time{}, meterEndpoint, w, req)
+}
+
+func handleUserQuota(meter RelayMeter, l *logger.Logger, user string, w http.ResponseWriter, req *http.Request) {
+	meterEndpoint := func(from, to time.Time) (any, error) {
+		return meter.UserQuota(user)
+	}
+	handleEndpoint(l, time.Time{}, meterEndpoint, w, req)
+}
+
+// byGranularity returns the meter endpoint split into buckets of the granularity set by the request's granularity parameter, e.g. one per calendar month.
+// Each bucket holds the endpoint's response for the bucket's time period, see RelayMeter.Buckets. The meter endpoint is returned as is
+// if the parameter is not set. Pagination is not supported along with a granularity, as each bucket would need its own cursor.
+func byGranularity(meter RelayMeter, req *http.Request, meterEndpoint func(from, to time.Time) (any, error)) func(from, to time.Time) (any, error) {
+	granularity := Granularity(req.URL.Query().Get(PARAMETER_GRANULARITY))
+	if granularity == "" {
+		return meterEndpoint
+	}
+
+	return func(from, to time.Time) (any, error) {
+		for _, parameter := range []string{PARAMETER_LIMIT, PARAMETER_CURSOR} {
+			if req.URL.Query().Get(parameter) != "" {
+				return nil, fmt.Errorf("%w: the %s parameter is not supported along with the %s parameter", InvalidRequest, parameter, PARAMETER_GRANULARITY)
+			}
+		}
+
+		buckets, err := meter.Buckets(from, to, granularity)
+		if err != nil {
+			return nil, err
+		}
+		for i := range buckets {
+			// The meter's time periods include their last day, while the buckets' end at the start of the next period
+			resp, err := meterEndpoint(buckets[i].From, buckets[i].To.AddDate(0, 0, -1))
+			if err != nil {
+				return nil, err
+			}
+			buckets[i].Relays = resp
+		}
+		return buckets, nil
+	}
+}
+
+// handleEndpoint serves the response of the meter endpoint, in the content type requested by the client.
+// lastUpdated is the time the metrics the response is based on were last updated: responses with a zero lastUpdated, e.g. quotas,
+// do not support conditional requests. It needs to be read before the response is calculated, so a response is never
+// served with the validator of more recent metrics. Conditional requests are only evaluated once the response is known to be
+// successful in the requested content type, e.g. an endpoint which cannot produce CSV returns 406 rather than 304.
+func handleEndpoint(l *logger.Logger, lastUpdated time.Time, meterEndpoint func(from, to time.Time) (any, error), w http.ResponseWriter, req *http.Request) {
 	log := l.WithFields(logger.Fields{"Request": req})
-	w.Header().Add("Content-Type", "application/json")
+
+	contentType, err := negotiateContentType(req)
+	if err != nil {
+		log.WithFields(logger.Fields{"error": err}).Warn("Unsupported content type")
+		WriteError(l, w, http.StatusNotAcceptable, ERROR_CODE_NOT_ACCEPTABLE, err.Error())
+		return
+	}
+	w.Header().Set(HEADER_CONTENT_TYPE, contentType)
+	w.Header().Set(HEADER_VARY, HEADER_ACCEPT+", "+HEADER_ACCEPT_ENCODING)
+
+	var validator *cacheValidator
+	if !lastUpdated.IsZero() {
+		validator = newCacheValidator(lastUpdated, contentType, req)
+	}
 
 	from, to, err := timePeriod(req)
 	if err != nil {
 		log.WithFields(logger.Fields{"error": err}).Warn("Invalid timespan")
-		http.Error(w, fmt.Sprintf("Invalid timespan: %v", err), http.StatusBadRequest)
+		WriteError(l, w, http.StatusBadRequest, ERROR_CODE_INVALID_TIMESPAN, err.Error())
 		return
 	}
 
-	// TODO: separate Internal errors from Request errors using custom errors returned by the meter service
 	meterResponse, meterErr := meterEndpoint(from, to)
 	if meterErr != nil {
-		errLogger := l.WithFields(logger.Fields{"error": meterErr})
-
-		switch {
-		case meterErr != nil && errors.Is(meterErr, InvalidRequest):
-			errLogger.Warn("Invalid request")
-			http.Error(w, fmt.Sprintf("Bad request: %v", meterErr), http.StatusBadRequest)
-		case meterErr != nil && errors.Is(meterErr, AppNotFound):
-			errLogger.Warn("Invalid request: application not found")
-			http.Error(w, fmt.Sprintf("Bad request: %v", meterErr), http.StatusBadRequest)
-		case meterErr != nil && errors.Is(meterErr, ErrLoadBalancerNotFound):
-			errLogger.Warn("Invalid request: load balancer not found")
-			http.Error(w, fmt.Sprintf("Bad request: %v", meterErr), http.StatusNotFound)
-		default:
-			errLogger.Warn("Internal server error")
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
-		}
+		statusCode, code, message := errorStatus(meterErr)
+		l.WithFields(logger.Fields{"error": meterErr, "code": code}).Warn("Error processing request")
+		WriteError(l, w, statusCode, code, message)
 		return
 	}
 
-	bytes, err := json.Marshal(meterResponse)
-	if err != nil {
-		log.WithFields(logger.Fields{"error": err}).Warn("Internal error marshalling response")
-		http.Error(w, fmt.Sprintf("Internal error marshalling the response %v", err), http.StatusInternalServerError)
-		return
+	// Errors need to be detected before the status code is written
+	var write func(w io.Writer) error
+	switch contentType {
+	case CONTENT_TYPE_CSV:
+		keyColumn, rows, err := relaysRows(meterResponse)
+		if err != nil {
+			log.WithFields(logger.Fields{"error": err}).Warn("Unsupported content type")
+			WriteError(l, w, http.StatusNotAcceptable, ERROR_CODE_NOT_ACCEPTABLE, err.Error())
+			return
+		}
+		write = func(w io.Writer) error { return writeCSV(w, keyColumn, rows) }
+	case CONTENT_TYPE_NDJSON:
+		write = func(w io.Writer) error { return writeNDJSON(w, meterResponse) }
+	default:
+		bytes, err := json.Marshal(meterResponse)
+		if err != nil {
+			log.WithFields(logger.Fields{"error": err}).Warn("Internal error marshalling response")
+			WriteError(l, w, http.StatusInternalServerError, ERROR_CODE_INTERNAL_ERROR, "Internal server error")
+			return
+		}
+		write = func(w io.Writer) error {
+			_, err := w.Write(bytes)
+			return err
+		}
 	}
 
+	if validator != nil {
+		validator.setHeaders(w)
+		if validator.notModified(req) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	body := newBodyWriter(w, req, meterResponse)
 	w.WriteHeader(http.StatusOK)
-	fmt.Fprintf(w, string(bytes))
+	// The status code has already been sent: errors can only be logged
+	if err := write(body); err != nil {
+		log.WithFields(logger.Fields{"error": err, "contentType": contentType}).Warn("Error writing response")
+	}
+	if err := body.Close(); err != nil {
+		log.WithFields(logger.Fields{"error": err}).Warn("Error writing compressed response")
+	}
 }
 
+// timePeriod returns the from and to query parameters: a missing parameter is returned as the zero time. Errors wrap ErrInvalidTimespan.
+//
+//	If the tz parameter is set, the parameters are converted to that timezone, and a missing 'to' is set to the current time there,
+//	so the meter uses their dates in that timezone, e.g. today in the client's region. The tz parameter only selects the dates:
+//	day boundaries are not moved, i.e. each date maps to the metrics of the same date in the reporting timezone, from its midnight,
+//	as metrics are stored per day in the reporting timezone. Returned time periods are in the reporting timezone too.
 func timePeriod(req *http.Request) (time.Time, time.Time, error) {
 	parse := func(s string) (time.Time, error) {
-		return time.Parse(DATE_LAYOUT, s)
+		t, err := time.Parse(DATE_LAYOUT, s)
+		if err != nil {
+			return t, fmt.Errorf("%w: %v", ErrInvalidTimespan, err)
+		}
+		return t, nil
 	}
 
 	var (
@@ -153,68 +491,181 @@ func timePeriod(req *http.Request) (time.Time, time.Time, error) {
 		}
 	}
 
-	return from, to, nil
-}
-
-// TODO: Return 404 on Application not found error
-// TODO: Return 304, i.e. Not Modified, if relevant
-// TODO: 'Accepts' Header in the request
-// serves: /relays/apps
-func GetHttpServer(meter RelayMeter, l *logger.Logger) func(w http.ResponseWriter, req *http.Request) {
-	match := func(r *regexp.Regexp, p string) string {
-		matches := r.FindStringSubmatch(p)
-		if len(matches) != 2 {
-			return ""
+	if tz := req.URL.Query().Get(PARAMETER_TZ); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return from, to, fmt.Errorf("%w: invalid %s parameter: %v", ErrInvalidTimespan, PARAMETER_TZ, err)
 		}
-		return matches[1]
-	}
-
-	return func(w http.ResponseWriter, req *http.Request) {
-		log := l.WithFields(logger.Fields{"Request": *req})
-		if req.Method != http.MethodGet {
-			log.Warn("Incorrect request method, expected: " + http.MethodGet)
-			http.Error(w, fmt.Sprintf("Incorrect request method, expected: %s, got: %s", http.MethodPost, req.Method), http.StatusBadRequest)
+		if !from.IsZero() {
+			from = from.In(loc)
 		}
-
-		if appID := match(appsRelaysPath, req.URL.Path); appID != "" {
-			handleAppRelays(meter, l, appID, w, req)
-			return
+		if to.IsZero() {
+			to = time.Now()
 		}
+		to = to.In(loc)
+	}
 
-		if userID := match(usersRelaysPath, req.URL.Path); userID != "" {
-			handleUserRelays(meter, l, userID, w, req)
-			return
-		}
+	return from, to, nil
+}
 
-		if lbID := match(lbRelaysPath, req.URL.Path); lbID != "" {
-			handleLoadBalancerRelays(meter, l, lbID, w, req)
-			return
-		}
+// route maps the requests matching a path regexp to their handler.
+// The id is the value captured by the regexp, e.g. the application public key, or empty if the regexp captures no value.
+// Every route is documented in the OpenAPI specification under openAPIPath: see openapi.go
+// Routes with no authorization rule are only available to admins, unless public: see RequireAuthorization
+// Routes with no rate limit class are rate limited under RATE_LIMIT_CLASS_DEFAULT: see RequireRateLimit
+// Routes with no method only accept GET requests
+type route struct {
+	path           *regexp.Regexp
+	openAPIPath    string
+	method         string
+	handler        func(meter RelayMeter, l *logger.Logger, id string, w http.ResponseWriter, req *http.Request)
+	authorize      func(owners Owners, p Principal, id string) error
+	public         bool
+	rateLimitClass string
+}
 
-		if allAppsRelaysPath.Match([]byte(req.URL.Path)) {
+// routes are matched in order: e.g. allAppsRelaysPath matches any path starting with /v0/relays/apps, so it needs to come after the specific application paths.
+var routes = []route{
+	{
+		path:           topAppsRelaysPath,
+		openAPIPath:    "/v0/relays/apps/top",
+		rateLimitClass: RATE_LIMIT_CLASS_LIST,
+		handler: func(meter RelayMeter, l *logger.Logger, _ string, w http.ResponseWriter, req *http.Request) {
+			handleTopAppsRelays(meter, l, w, req)
+		},
+	},
+	{path: appsRelaysPath, openAPIPath: "/v0/relays/apps/{app}", handler: handleAppRelays, authorize: authorizeApp},
+	{path: appDailyRelaysPath, openAPIPath: "/v0/relays/apps/{app}/daily", handler: handleAppDailyRelays, authorize: authorizeApp},
+	{path: appTodaysRelaysPath, openAPIPath: "/v0/relays/apps/{app}/today", handler: handleAppTodaysRelays, authorize: authorizeApp},
+	{path: usersRelaysPath, openAPIPath: "/v0/relays/users/{user}", handler: handleUserRelays, authorize: authorizeSelf},
+	{path: lbRelaysPath, openAPIPath: "/v0/relays/endpoints/{endpoint}", handler: handleLoadBalancerRelays, authorize: authorizeLoadBalancer},
+	{
+		path:        appCompareRelaysPath,
+		openAPIPath: "/v0/relays/apps/{app}/compare",
+		handler: func(meter RelayMeter, l *logger.Logger, app string, w http.ResponseWriter, req *http.Request) {
+			compare := func(from, to time.Time) (RelaysComparisonResponse, error) {
+				return meter.CompareAppRelays(app, from, to)
+			}
+			handleCompareRelays(meter, l, compare, w, req)
+		},
+		authorize: authorizeApp,
+	},
+	{
+		path:        userCompareRelaysPath,
+		openAPIPath: "/v0/relays/users/{user}/compare",
+		handler: func(meter RelayMeter, l *logger.Logger, user string, w http.ResponseWriter, req *http.Request) {
+			compare := func(from, to time.Time) (RelaysComparisonResponse, error) {
+				return meter.CompareUserRelays(user, from, to)
+			}
+			handleCompareRelays(meter, l, compare, w, req)
+		},
+		authorize: authorizeSelf,
+	},
+	{
+		path:        lbCompareRelaysPath,
+		openAPIPath: "/v0/relays/endpoints/{endpoint}/compare",
+		handler: func(meter RelayMeter, l *logger.Logger, endpoint string, w http.ResponseWriter, req *http.Request) {
+			compare := func(from, to time.Time) (RelaysComparisonResponse, error) {
+				return meter.CompareLoadBalancerRelays(endpoint, from, to)
+			}
+			handleCompareRelays(meter, l, compare, w, req)
+		},
+		authorize: authorizeLoadBalancer,
+	},
+	{
+		path:           totalCompareRelaysPath,
+		openAPIPath:    "/v0/relays/compare",
+		rateLimitClass: RATE_LIMIT_CLASS_LIST,
+		handler: func(meter RelayMeter, l *logger.Logger, _ string, w http.ResponseWriter, req *http.Request) {
+			handleCompareRelays(meter, l, meter.CompareTotalRelays, w, req)
+		},
+	},
+	{
+		path:           batchAppsRelaysPath,
+		openAPIPath:    "/v0/relays/apps:batch",
+		method:         http.MethodPost,
+		rateLimitClass: RATE_LIMIT_CLASS_LIST,
+		handler: func(meter RelayMeter, l *logger.Logger, _ string, w http.ResponseWriter, req *http.Request) {
+			handleBatchAppRelays(meter, l, w, req)
+		},
+	},
+	{
+		path:           allAppsRelaysPath,
+		openAPIPath:    "/v0/relays/apps",
+		rateLimitClass: RATE_LIMIT_CLASS_LIST,
+		handler: func(meter RelayMeter, l *logger.Logger, _ string, w http.ResponseWriter, req *http.Request) {
 			handleAllAppsRelays(meter, l, w, req)
-			return
+		},
+	},
+	{
+		path:           allLbsRelaysPath,
+		openAPIPath:    "/v0/relays/endpoints",
+		rateLimitClass: RATE_LIMIT_CLASS_LIST,
+		handler: func(meter RelayMeter, l *logger.Logger, _ string, w http.ResponseWriter, req *http.Request) {
+			handleAllLoadBalancersRelays(meter, l, w, req)
+		},
+	},
+	{
+		path:           totalRelaysPath,
+		openAPIPath:    "/v0/relays",
+		rateLimitClass: RATE_LIMIT_CLASS_LIST,
+		handler: func(meter RelayMeter, l *logger.Logger, _ string, w http.ResponseWriter, req *http.Request) {
+			handleTotalRelays(meter, l, w, req)
+		},
+	},
+	{path: appQuotaPath, openAPIPath: "/v0/quota/apps/{app}", handler: handleAppQuota, authorize: authorizeApp},
+	{path: userQuotaPath, openAPIPath: "/v0/quota/users/{user}", handler: handleUserQuota, authorize: authorizeSelf},
+	{
+		path:        openAPISpecPath,
+		openAPIPath: "/v0/openapi.json",
+		handler: func(_ RelayMeter, l *logger.Logger, _ string, w http.ResponseWriter, req *http.Request) {
+			handleOpenAPISpec(l, w, req)
+		},
+		public: true,
+	},
+}
+
+// matchRoute returns the route matching the path, and the value captured by the route's regexp, if any.
+func matchRoute(path string) (*route, string) {
+	for i := range routes {
+		r := &routes[i]
+		if r.path.NumSubexp() == 0 {
+			if r.path.MatchString(path) {
+				return r, ""
+			}
+			continue
 		}
 
-		if allLbsRelaysPath.Match([]byte(req.URL.Path)) {
-			handleAllLoadBalancersRelays(meter, l, w, req)
-			return
+		matches := r.path.FindStringSubmatch(path)
+		if len(matches) == 2 && matches[1] != "" {
+			return r, matches[1]
 		}
+	}
+	return nil, ""
+}
 
-		if totalRelaysPath.Match([]byte(req.URL.Path)) {
-			handleTotalRelays(meter, l, w, req)
+// The response format is selected using the Accept header: see negotiateContentType
+// serves: the paths listed in routes
+func GetHttpServer(meter RelayMeter, l *logger.Logger) func(w http.ResponseWriter, req *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log := l.WithFields(logger.Fields{"Request": *req})
+		if r, id := matchRoute(req.URL.Path); r != nil {
+			method := r.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			if req.Method != method {
+				log.Warn("Incorrect request method, expected: " + method)
+				w.Header().Set("Allow", method)
+				WriteError(l, w, http.StatusMethodNotAllowed, ERROR_CODE_METHOD_NOT_ALLOWED, fmt.Sprintf("Incorrect request method, expected: %s, got: %s", method, req.Method))
+				return
+			}
+
+			r.handler(meter, l, id, w, req)
 			return
 		}
 
 		log.Warn("Invalid request endpoint")
-		bytes, err := json.Marshal(ErrorResponse{Message: fmt.Sprintf("Invalid request path: %s", req.URL.Path)})
-		if err != nil {
-			log.WithFields(logger.Fields{"error": err}).Warn("Internal error marshalling response")
-			http.Error(w, fmt.Sprintf("Internal error marshalling the response %v", err), http.StatusInternalServerError)
-			return
-		}
-		w.Header().Add("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprintf(w, string(bytes))
+		WriteError(l, w, http.StatusBadRequest, ERROR_CODE_INVALID_PATH, fmt.Sprintf("Invalid request path: %s", req.URL.Path))
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -31,6 +32,24 @@ func TestGetHttpServer(t *testing.T) {
 			),
 			expectedStatusCode: http.StatusOK,
 		},
+		{
+			name: "App daily relays path is handled correctly",
+			url: fmt.Sprintf("http://relay-meter.pokt.network/v0/relays/apps/app/daily?from=%s&to=%s",
+				url.QueryEscape(now.Format(time.RFC3339)),
+				url.QueryEscape(now.Format(time.RFC3339)),
+			),
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "App todays relays path is handled correctly",
+			url:                "http://relay-meter.pokt.network/v0/relays/apps/app/today",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Top apps relays path is handled correctly",
+			url:                "http://relay-meter.pokt.network/v0/relays/apps/top?n=5&by=failure",
+			expectedStatusCode: http.StatusOK,
+		},
 		{
 			name: "User relays path is handled correctly",
 			url: fmt.Sprintf("http://relay-meter.pokt.network/v0/relays/users/user?from=%s&to=%s",
@@ -63,6 +82,16 @@ func TestGetHttpServer(t *testing.T) {
 			),
 			expectedStatusCode: http.StatusOK,
 		},
+		{
+			name:               "App quota path is handled correctly",
+			url:                "http://relay-meter.pokt.network/v0/quota/apps/app",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "User quota path is handled correctly",
+			url:                "http://relay-meter.pokt.network/v0/quota/users/user",
+			expectedStatusCode: http.StatusOK,
+		},
 		{
 			name:               "Invalid request path returns an error",
 			url:                "http://relay-meter.pokt.network/invalid-path",
@@ -95,6 +124,58 @@ func TestGetHttpServer(t *testing.T) {
 
 }
 
+func TestGetHttpServerMethods(t *testing.T) {
+	testCases := []struct {
+		name               string
+		method             string
+		url                string
+		body               string
+		expectedStatusCode int
+		expectedAllow      string
+	}{
+		{
+			name:               "Batch apps relays path accepts POST requests",
+			method:             http.MethodPost,
+			url:                "http://relay-meter.pokt.network/v0/relays/apps:batch",
+			body:               `{"applications": [{"application": "app1"}]}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Batch apps relays path rejects GET requests",
+			method:             http.MethodGet,
+			url:                "http://relay-meter.pokt.network/v0/relays/apps:batch",
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedAllow:      http.MethodPost,
+		},
+		{
+			name:               "App relays path rejects POST requests",
+			method:             http.MethodPost,
+			url:                "http://relay-meter.pokt.network/v0/relays/apps/app",
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedAllow:      http.MethodGet,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpServer := GetHttpServer(&fakeRelayMeter{}, logger.New())
+
+			req := httptest.NewRequest(tc.method, tc.url, strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+
+			httpServer(w, req)
+
+			resp := w.Result()
+			if resp.StatusCode != tc.expectedStatusCode {
+				t.Errorf("Expected status code: %d, got: %d", tc.expectedStatusCode, resp.StatusCode)
+			}
+			if allow := resp.Header.Get("Allow"); allow != tc.expectedAllow {
+				t.Errorf("Expected Allow header: %q, got: %q", tc.expectedAllow, allow)
+			}
+		})
+	}
+}
+
 func TestHandleAppRelays(t *testing.T) {
 	now, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
 
@@ -134,7 +215,7 @@ func TestHandleAppRelays(t *testing.T) {
 				Application: "non-existent-app",
 			},
 			meterErr:           AppNotFound,
-			expectedStatusCode: http.StatusBadRequest,
+			expectedStatusCode: http.StatusNotFound,
 		},
 		{
 			name: "Bad request returns reqest error response",
@@ -176,7 +257,7 @@ func TestHandleAppRelays(t *testing.T) {
 				t.Errorf("Expected status code: %d, got: %d", tc.expectedStatusCode, resp.StatusCode)
 			}
 
-			// TODO: Should we return json even if there is a request/internal server error?
+			// Error responses are verified by TestHandleEndpointErrors
 			if tc.expectedStatusCode != http.StatusOK {
 				return
 			}
@@ -189,8 +270,94 @@ func TestHandleAppRelays(t *testing.T) {
 			if err := json.Unmarshal(body, &r); err != nil {
 				t.Fatalf("Unexpected error unmarhsalling the response: %v", err)
 			}
-			if r.Count != tc.meterResponse.Count {
-				t.Errorf("Expected Count: %d, got: %d", tc.meterResponse.Count, r.Count)
+			if diff := cmp.Diff(tc.meterResponse.Count, r.Count); diff != "" {
+				t.Errorf("unexpected Count (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHandleEndpointErrors(t *testing.T) {
+	testCases := []struct {
+		name               string
+		query              string
+		accept             string
+		meterErr           error
+		expectedStatusCode int
+		expectedCode       string
+	}{
+		{
+			name:               "Invalid time period from the meter",
+			meterErr:           fmt.Errorf("%w: 2022-06-26 -- 2022-06-25", ErrInvalidTimespan),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedCode:       ERROR_CODE_INVALID_TIMESPAN,
+		},
+		{
+			name:               "Invalid from parameter",
+			query:              "?from=yesterday",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedCode:       ERROR_CODE_INVALID_TIMESPAN,
+		},
+		{
+			name:               "Invalid request",
+			meterErr:           fmt.Errorf("%w: invalid limit parameter", InvalidRequest),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedCode:       ERROR_CODE_INVALID_REQUEST,
+		},
+		{
+			name:               "Load balancer not found",
+			meterErr:           ErrLoadBalancerNotFound,
+			expectedStatusCode: http.StatusNotFound,
+			expectedCode:       ERROR_CODE_LB_NOT_FOUND,
+		},
+		{
+			name:               "Backend unavailable",
+			meterErr:           fmt.Errorf("%w: connection refused", ErrBackendUnavailable),
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedCode:       ERROR_CODE_BACKEND_UNAVAILABLE,
+		},
+		{
+			name:               "Internal error",
+			meterErr:           fmt.Errorf("Internal meter error"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedCode:       ERROR_CODE_INTERNAL_ERROR,
+		},
+		{
+			name:               "Unsupported content type returns a JSON error",
+			accept:             "application/xml",
+			expectedStatusCode: http.StatusNotAcceptable,
+			expectedCode:       ERROR_CODE_NOT_ACCEPTABLE,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeMeter := fakeRelayMeter{responseErr: tc.meterErr}
+			req := httptest.NewRequest("GET", "http://relay-meter.pokt.network/v0/relays/endpoints/lb1"+tc.query, nil)
+			if tc.accept != "" {
+				req.Header.Set(HEADER_ACCEPT, tc.accept)
+			}
+			w := httptest.NewRecorder()
+
+			handleLoadBalancerRelays(&fakeMeter, logger.New(), "lb1", w, req)
+
+			resp := w.Result()
+			if resp.StatusCode != tc.expectedStatusCode {
+				t.Errorf("Expected status code: %d, got: %d", tc.expectedStatusCode, resp.StatusCode)
+			}
+			if contentType := resp.Header.Get(HEADER_CONTENT_TYPE); contentType != CONTENT_TYPE_JSON {
+				t.Errorf("Expected Content-Type: %s, got: %s", CONTENT_TYPE_JSON, contentType)
+			}
+
+			var r ErrorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+				t.Fatalf("Unexpected error unmarhsalling the response: %v", err)
+			}
+			if r.Code != tc.expectedCode {
+				t.Errorf("Expected error code: %s, got: %s", tc.expectedCode, r.Code)
+			}
+			if r.Message == "" {
+				t.Errorf("Expected an error message")
 			}
 		})
 	}
@@ -228,7 +395,7 @@ func TestHandleAllAppsRelays(t *testing.T) {
 		{
 			name:               "Application not found returns a not found response",
 			meterErr:           AppNotFound,
-			expectedStatusCode: http.StatusBadRequest,
+			expectedStatusCode: http.StatusNotFound,
 		},
 		{
 			name:               "Bad request returns reqest error response",
@@ -264,7 +431,7 @@ func TestHandleAllAppsRelays(t *testing.T) {
 				t.Errorf("Expected status code: %d, got: %d", tc.expectedStatusCode, resp.StatusCode)
 			}
 
-			// TODO: Should we return json even if there is a request/internal server error?
+			// Error responses are verified by TestHandleEndpointErrors
 			if tc.expectedStatusCode != http.StatusOK {
 				return
 			}
@@ -285,6 +452,358 @@ func TestHandleAllAppsRelays(t *testing.T) {
 	}
 }
 
+func TestHandleBatchAppRelays(t *testing.T) {
+	now, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+
+	testCases := []struct {
+		name               string
+		body               string
+		meterResponse      []AppRelaysResponse
+		meterErr           error
+		expectedRequests   []AppRelaysRequest
+		expectedStatusCode int
+	}{
+		{
+			name: "Applications without a time period default to the query parameters",
+			body: fmt.Sprintf(`{"applications": [{"application": "app1"}, {"application": "app2", "from": %q}]}`,
+				now.AddDate(0, 0, -2).Format(time.RFC3339),
+			),
+			meterResponse: []AppRelaysResponse{
+				{Application: "app1", Count: RelayCounts{Success: 10, Failure: 2}},
+				{Application: "app2", Count: RelayCounts{Success: 5}},
+			},
+			expectedRequests: []AppRelaysRequest{
+				{Application: "app1", From: now, To: now},
+				{Application: "app2", From: now.AddDate(0, 0, -2), To: now},
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Invalid body returns a bad request response",
+			body:               `{"applications": "app1"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Unknown fields return a bad request response",
+			body:               `{"apps": [{"application": "app1"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Bad request from the meter returns a bad request response",
+			body:               `{"applications": [{"application": ""}]}`,
+			meterErr:           InvalidRequest,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Error from the meter returns an internal error response",
+			body:               `{"applications": [{"application": "app1"}]}`,
+			meterErr:           fmt.Errorf("Internal meter error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeMeter := fakeRelayMeter{
+				allResponse: tc.meterResponse,
+				responseErr: tc.meterErr,
+			}
+
+			url := fmt.Sprintf("http://relay-meter.pokt.network/v0/relays/apps:batch?from=%s&to=%s",
+				url.QueryEscape(now.Format(time.RFC3339)),
+				url.QueryEscape(now.Format(time.RFC3339)),
+			)
+			req := httptest.NewRequest(http.MethodPost, url, strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+
+			handleBatchAppRelays(&fakeMeter, logger.New(), w, req)
+
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+
+			if resp.StatusCode != tc.expectedStatusCode {
+				t.Fatalf("Expected status code: %d, got: %d", tc.expectedStatusCode, resp.StatusCode)
+			}
+			if tc.expectedStatusCode != http.StatusOK {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expectedRequests, fakeMeter.requestedBatch); diff != "" {
+				t.Errorf("unexpected requests (-want +got):\n%s", diff)
+			}
+
+			var r []AppRelaysResponse
+			if err := json.Unmarshal(body, &r); err != nil {
+				t.Fatalf("Unexpected error unmarhsalling the response: %v", err)
+			}
+			if diff := cmp.Diff(tc.meterResponse, r); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHandleCompareRelays(t *testing.T) {
+	now, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+	chainCounts := func(success, failure int64) RelayCounts {
+		return RelayCounts{
+			Success: success,
+			Failure: failure,
+			Chains:  map[string]RelayCounts{"0021": {Success: success, Failure: failure / 2}, "0040": {Failure: failure - failure/2}},
+		}
+	}
+	meterResponse := RelaysComparisonResponse{
+		Current:     TotalRelaysResponse{Count: chainCounts(30, 10), From: now, To: now},
+		Previous:    TotalRelaysResponse{Count: chainCounts(20, 4), From: now, To: now},
+		Delta:       newRelaysDelta(chainCounts(30, 10), chainCounts(20, 4)),
+		Application: "app1",
+	}
+
+	testCases := []struct {
+		name               string
+		query              string
+		meterErr           error
+		expected           RelaysComparisonResponse
+		expectedStatusCode int
+	}{
+		{
+			name:  "Comparison is returned without the breakdown by chain",
+			query: "",
+			expected: RelaysComparisonResponse{
+				Current:     TotalRelaysResponse{Count: RelayCounts{Success: 30, Failure: 10}, From: now, To: now},
+				Previous:    TotalRelaysResponse{Count: RelayCounts{Success: 20, Failure: 4}, From: now, To: now},
+				Delta:       newRelaysDelta(chainCounts(30, 10), chainCounts(20, 4)),
+				Application: "app1",
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:  "Changes are calculated on the requested chain's counts",
+			query: "&chain=0040",
+			expected: RelaysComparisonResponse{
+				Current:     TotalRelaysResponse{Count: RelayCounts{Failure: 5}, From: now, To: now},
+				Previous:    TotalRelaysResponse{Count: RelayCounts{Failure: 2}, From: now, To: now},
+				Delta:       newRelaysDelta(RelayCounts{Failure: 5}, RelayCounts{Failure: 2}),
+				Application: "app1",
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Bad request returns reqest error response",
+			meterErr:           InvalidRequest,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeMeter := fakeRelayMeter{
+				comparisonResponse: meterResponse,
+				responseErr:        tc.meterErr,
+			}
+
+			url := fmt.Sprintf("http://relay-meter.pokt.network/v0/relays/apps/app1/compare?from=%s&to=%s%s",
+				url.QueryEscape(now.Format(time.RFC3339)),
+				url.QueryEscape(now.Format(time.RFC3339)),
+				tc.query,
+			)
+			req := httptest.NewRequest("GET", url, nil)
+			w := httptest.NewRecorder()
+
+			GetHttpServer(&fakeMeter, logger.New())(w, req)
+
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tc.expectedStatusCode {
+				t.Fatalf("Expected status code: %d, got: %d", tc.expectedStatusCode, resp.StatusCode)
+			}
+			if tc.expectedStatusCode != http.StatusOK {
+				return
+			}
+
+			if fakeMeter.requestedApp != "app1" || !fakeMeter.requestedFrom.Equal(now) {
+				t.Errorf("Unexpected meter request: app: %s, from: %v", fakeMeter.requestedApp, fakeMeter.requestedFrom)
+			}
+
+			var r RelaysComparisonResponse
+			if err := json.Unmarshal(body, &r); err != nil {
+				t.Fatalf("Unexpected error unmarhsalling the response: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, r); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHandleGranularity(t *testing.T) {
+	day := time.Date(2022, time.July, 15, 0, 0, 0, 0, time.UTC)
+	buckets := []RelaysBucket{
+		{From: day, To: day.AddDate(0, 0, 17), Partial: true},
+		{From: day.AddDate(0, 0, 17), To: day.AddDate(0, 1, 17)},
+	}
+	meterResponse := AppRelaysResponse{Count: RelayCounts{Success: 5, Failure: 1}, From: day, To: day, Application: "app1"}
+
+	type bucket struct {
+		From    time.Time
+		To      time.Time
+		Partial bool
+		Relays  AppRelaysResponse
+	}
+
+	testCases := []struct {
+		name               string
+		path               string
+		accept             string
+		meterErr           error
+		expectedStatusCode int
+		expectedBuckets    []bucket
+		expectedPeriods    [][2]time.Time
+		expectedCSV        string
+	}{
+		{
+			name:               "Relays are returned per bucket",
+			path:               "/v0/relays/apps/app1?granularity=month",
+			expectedStatusCode: http.StatusOK,
+			expectedBuckets: []bucket{
+				{From: buckets[0].From, To: buckets[0].To, Partial: true, Relays: meterResponse},
+				{From: buckets[1].From, To: buckets[1].To, Relays: meterResponse},
+			},
+			// The last day of each bucket is requested from the meter
+			expectedPeriods: [][2]time.Time{
+				{day, day.AddDate(0, 0, 16)},
+				{day.AddDate(0, 0, 17), day.AddDate(0, 1, 16)},
+			},
+		},
+		{
+			name:               "CSV output flags partial buckets",
+			path:               "/v0/relays/apps/app1?granularity=month",
+			accept:             CONTENT_TYPE_CSV,
+			expectedStatusCode: http.StatusOK,
+			expectedCSV: "application,from,to,chain,success,failure,client_error,server_error,timeout,other,total,partial\n" +
+				"app1,2022-07-15T00:00:00Z,2022-07-15T00:00:00Z,,5,1,0,0,0,0,6,true\n" +
+				"app1,2022-07-15T00:00:00Z,2022-07-15T00:00:00Z,,5,1,0,0,0,0,6,false\n",
+		},
+		{
+			name:               "Invalid granularity returns bad request",
+			path:               "/v0/relays/apps/app1?granularity=year",
+			meterErr:           InvalidRequest,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Pagination is not supported along with a granularity",
+			path:               "/v0/relays/apps?granularity=week&limit=10",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Today's relays do not support a granularity",
+			path:               "/v0/relays/apps/app1/today?granularity=day",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeMeter := fakeRelayMeter{
+				response:        meterResponse,
+				bucketsResponse: buckets,
+				responseErr:     tc.meterErr,
+			}
+
+			req := httptest.NewRequest("GET", "http://relay-meter.pokt.network"+tc.path, nil)
+			if tc.accept != "" {
+				req.Header.Set(HEADER_ACCEPT, tc.accept)
+			}
+			w := httptest.NewRecorder()
+
+			GetHttpServer(&fakeMeter, logger.New())(w, req)
+
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tc.expectedStatusCode {
+				t.Fatalf("Expected status code: %d, got: %d", tc.expectedStatusCode, resp.StatusCode)
+			}
+			if tc.expectedStatusCode != http.StatusOK {
+				return
+			}
+
+			if fakeMeter.requestedGranularity != GRANULARITY_MONTH {
+				t.Errorf("Expected granularity: %s, got: %s", GRANULARITY_MONTH, fakeMeter.requestedGranularity)
+			}
+			if tc.expectedCSV != "" {
+				if diff := cmp.Diff(tc.expectedCSV, string(body)); diff != "" {
+					t.Errorf("unexpected value (-want +got):\n%s", diff)
+				}
+				return
+			}
+
+			var got []bucket
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("Unexpected error unmarhsalling the response: %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedBuckets, got); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedPeriods, fakeMeter.requestedPeriods); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHandleTopAppsRelays(t *testing.T) {
+	testCases := []struct {
+		name               string
+		query              string
+		expectedN          int
+		expectedBy         RankBy
+		expectedStatusCode int
+	}{
+		{
+			name:               "Ranking parameters are passed to the meter",
+			query:              "n=5&by=failure_rate",
+			expectedN:          5,
+			expectedBy:         RANK_BY_FAILURE_RATE,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Missing ranking parameters are left to the meter's defaults",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Invalid number of applications returns a bad request response",
+			query:              "n=many",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeMeter := fakeRelayMeter{}
+
+			req := httptest.NewRequest("GET", "http://relay-meter.pokt.network/v0/relays/apps/top?"+tc.query, nil)
+			w := httptest.NewRecorder()
+
+			handleTopAppsRelays(&fakeMeter, logger.New(), w, req)
+
+			resp := w.Result()
+			if resp.StatusCode != tc.expectedStatusCode {
+				t.Fatalf("Expected status code: %d, got: %d", tc.expectedStatusCode, resp.StatusCode)
+			}
+			if tc.expectedStatusCode != http.StatusOK {
+				return
+			}
+
+			if fakeMeter.requestedN != tc.expectedN {
+				t.Errorf("Expected n: %d, got: %d", tc.expectedN, fakeMeter.requestedN)
+			}
+			if fakeMeter.requestedBy != tc.expectedBy {
+				t.Errorf("Expected by: %q, got: %q", tc.expectedBy, fakeMeter.requestedBy)
+			}
+		})
+	}
+}
+
 func TestHandleLoadBalancerRelays(t *testing.T) {
 	now, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
 
@@ -361,7 +880,7 @@ func TestHandleLoadBalancerRelays(t *testing.T) {
 				t.Errorf("Expected status code: %d, got: %d", tc.expectedStatusCode, resp.StatusCode)
 			}
 
-			// TODO: Should we return json even if there is a request/internal server error?
+			// Error responses are verified by TestHandleEndpointErrors
 			if tc.expectedStatusCode != http.StatusOK {
 				return
 			}
@@ -374,8 +893,8 @@ func TestHandleLoadBalancerRelays(t *testing.T) {
 			if err := json.Unmarshal(body, &r); err != nil {
 				t.Fatalf("Unexpected error unmarhsalling the response: %v", err)
 			}
-			if r.Count != tc.meterResponse.Count {
-				t.Errorf("Expected Count: %d, got: %d", tc.meterResponse.Count, r.Count)
+			if diff := cmp.Diff(tc.meterResponse.Count, r.Count); diff != "" {
+				t.Errorf("unexpected Count (-want +got):\n%s", diff)
 			}
 		})
 	}
@@ -463,7 +982,7 @@ func TestHandleAllLoadBalancersRelays(t *testing.T) {
 				t.Errorf("Expected status code: %d, got: %d", tc.expectedStatusCode, resp.StatusCode)
 			}
 
-			// TODO: Should we return json even if there is a request/internal server error?
+			// Error responses are verified by TestHandleEndpointErrors
 			if tc.expectedStatusCode != http.StatusOK {
 				return
 			}
@@ -483,26 +1002,118 @@ func TestHandleAllLoadBalancersRelays(t *testing.T) {
 	}
 }
 
+func TestHandleAppQuota(t *testing.T) {
+	testCases := []struct {
+		name               string
+		meterResponse      AppQuotaResponse
+		meterErr           error
+		expectedStatusCode int
+	}{
+		{
+			name: "Quota usage is returned",
+			meterResponse: AppQuotaResponse{
+				Application: "app1",
+				Daily:       &QuotaUsage{Limit: 100, Used: 82, Remaining: 18, UsedRatio: 0.82},
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Quota not found returns a not found response",
+			meterErr:           ErrQuotaNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "Error from the meter returns an internal error response",
+			meterErr:           fmt.Errorf("Internal meter error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeMeter := fakeRelayMeter{
+				appQuotaResponse: tc.meterResponse,
+				responseErr:      tc.meterErr,
+			}
+
+			req := httptest.NewRequest("GET", "http://relay-meter.pokt.network/v0/quota/apps/app1", nil)
+			w := httptest.NewRecorder()
+
+			handleAppQuota(&fakeMeter, logger.New(), "app1", w, req)
+
+			resp := w.Result()
+			if resp.StatusCode != tc.expectedStatusCode {
+				t.Errorf("Expected status code: %d, got: %d", tc.expectedStatusCode, resp.StatusCode)
+			}
+			if fakeMeter.requestedApp != "app1" {
+				t.Errorf("Expected app: app1, got: %s", fakeMeter.requestedApp)
+			}
+			if tc.expectedStatusCode != http.StatusOK {
+				return
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+			var got AppQuotaResponse
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("Unexpected error unmarhsalling the response: %v", err)
+			}
+			if diff := cmp.Diff(tc.meterResponse, got); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 type fakeRelayMeter struct {
-	requestedFrom time.Time
-	requestedTo   time.Time
-	requestedApp  string
+	requestedFrom  time.Time
+	requestedTo    time.Time
+	requestedApp   string
+	requestedN     int
+	requestedBy    RankBy
+	requestedBatch []AppRelaysRequest
+	// Time periods requested from the meter, in order: used for verifying the requests of each bucket
+	requestedPeriods     [][2]time.Time
+	requestedGranularity Granularity
 
 	response                   AppRelaysResponse
 	allResponse                []AppRelaysResponse
 	loadbalancerRelaysResponse LoadBalancerRelaysResponse
 	allLoadBalancersResponse   []LoadBalancerRelaysResponse
+	comparisonResponse         RelaysComparisonResponse
+	bucketsResponse            []RelaysBucket
+	appQuotaResponse           AppQuotaResponse
+	userQuotaResponse          UserQuotaResponse
 	responseErr                error
+	lastUpdated                time.Time
+}
+
+func (f *fakeRelayMeter) LastUpdated() time.Time {
+	return f.lastUpdated
 }
 
 func (f *fakeRelayMeter) AppRelays(app string, from, to time.Time) (AppRelaysResponse, error) {
 	f.requestedFrom = from
 	f.requestedTo = to
 	f.requestedApp = app
+	f.requestedPeriods = append(f.requestedPeriods, [2]time.Time{from, to})
 
 	return f.response, f.responseErr
 }
 
+func (f *fakeRelayMeter) AppDailyRelays(app string, from, to time.Time) ([]AppRelaysResponse, error) {
+	f.requestedFrom = from
+	f.requestedTo = to
+	f.requestedApp = app
+
+	return f.allResponse, f.responseErr
+}
+
+func (f *fakeRelayMeter) AppTodaysRelays(app string) ([]AppRelaysResponse, error) {
+	f.requestedApp = app
+
+	return f.allResponse, f.responseErr
+}
+
 func (f *fakeRelayMeter) AllAppsRelays(from, to time.Time) ([]AppRelaysResponse, error) {
 	f.requestedFrom = from
 	f.requestedTo = to
@@ -510,12 +1121,26 @@ func (f *fakeRelayMeter) AllAppsRelays(from, to time.Time) ([]AppRelaysResponse,
 	return f.allResponse, f.responseErr
 }
 
+func (f *fakeRelayMeter) BatchAppRelays(requests []AppRelaysRequest) ([]AppRelaysResponse, error) {
+	f.requestedBatch = requests
+	return f.allResponse, f.responseErr
+}
+
+func (f *fakeRelayMeter) TopAppsRelays(from, to time.Time, n int, by RankBy) ([]AppRelaysResponse, error) {
+	f.requestedFrom = from
+	f.requestedTo = to
+	f.requestedN = n
+	f.requestedBy = by
+
+	return f.allResponse, f.responseErr
+}
+
 func (f *fakeRelayMeter) UserRelays(user string, from, to time.Time) (UserRelaysResponse, error) {
-	return UserRelaysResponse{}, nil
+	return UserRelaysResponse{}, f.responseErr
 }
 
 func (f *fakeRelayMeter) TotalRelays(from, to time.Time) (TotalRelaysResponse, error) {
-	return TotalRelaysResponse{}, nil
+	return TotalRelaysResponse{}, f.responseErr
 }
 
 func (f *fakeRelayMeter) LoadBalancerRelays(endpoint string, from, to time.Time) (LoadBalancerRelaysResponse, error) {
@@ -530,17 +1155,57 @@ func (f *fakeRelayMeter) AllLoadBalancersRelays(from, to time.Time) ([]LoadBalan
 	return f.allLoadBalancersResponse, f.responseErr
 }
 
+func (f *fakeRelayMeter) CompareAppRelays(app string, from, to time.Time) (RelaysComparisonResponse, error) {
+	f.requestedFrom = from
+	f.requestedTo = to
+	f.requestedApp = app
+
+	return f.comparisonResponse, f.responseErr
+}
+
+func (f *fakeRelayMeter) CompareUserRelays(user string, from, to time.Time) (RelaysComparisonResponse, error) {
+	return f.comparisonResponse, f.responseErr
+}
+
+func (f *fakeRelayMeter) CompareLoadBalancerRelays(endpoint string, from, to time.Time) (RelaysComparisonResponse, error) {
+	return f.comparisonResponse, f.responseErr
+}
+
+func (f *fakeRelayMeter) CompareTotalRelays(from, to time.Time) (RelaysComparisonResponse, error) {
+	return f.comparisonResponse, f.responseErr
+}
+
+func (f *fakeRelayMeter) Buckets(from, to time.Time, granularity Granularity) ([]RelaysBucket, error) {
+	f.requestedFrom = from
+	f.requestedTo = to
+	f.requestedGranularity = granularity
+
+	// A copy is returned, as the caller sets the buckets' relays
+	return append([]RelaysBucket(nil), f.bucketsResponse...), f.responseErr
+}
+
+func (f *fakeRelayMeter) AppQuota(app string) (AppQuotaResponse, error) {
+	f.requestedApp = app
+	return f.appQuotaResponse, f.responseErr
+}
+
+func (f *fakeRelayMeter) UserQuota(user string) (UserQuotaResponse, error) {
+	return f.userQuotaResponse, f.responseErr
+}
+
 func TestTimePeriod(t *testing.T) {
 	// Convert to time.RFC3339, i.e. the maximum granularity for our routines, before using the timestamp
 	now, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
 
 	testCases := []struct {
-		name         string
-		from         string
-		to           string
-		expectedFrom time.Time
-		expectedTo   time.Time
-		expectedErr  bool
+		name             string
+		from             string
+		to               string
+		tz               string
+		expectedFrom     time.Time
+		expectedTo       time.Time
+		expectedLocation string
+		expectedErr      bool
 	}{
 		{
 			name:         "Both parameters specified correctly",
@@ -569,17 +1234,32 @@ func TestTimePeriod(t *testing.T) {
 			from:        "invalid-date",
 			expectedErr: true,
 		},
+		{
+			name:             "Parameters are converted to the 'tz' timezone",
+			from:             now.Format(time.RFC3339),
+			to:               now.Add(96 * time.Hour).Format(time.RFC3339),
+			tz:               "Asia/Tokyo",
+			expectedFrom:     now,
+			expectedTo:       now.Add(96 * time.Hour),
+			expectedLocation: "Asia/Tokyo",
+		},
+		{
+			name:        "Invalid 'tz' parameter returns error",
+			from:        now.Format(time.RFC3339),
+			tz:          "Invalid/Timezone",
+			expectedErr: true,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			url := fmt.Sprintf("http://relay-meter.pokt.network/v0/relays/apps/app1?from=%s&to=%s", url.QueryEscape(tc.from), url.QueryEscape(tc.to))
+			url := fmt.Sprintf("http://relay-meter.pokt.network/v0/relays/apps/app1?from=%s&to=%s&tz=%s", url.QueryEscape(tc.from), url.QueryEscape(tc.to), url.QueryEscape(tc.tz))
 			req := httptest.NewRequest("GET", url, nil)
 			gotFrom, gotTo, err := timePeriod(req)
+			if (err != nil) != tc.expectedErr {
+				t.Fatalf("Expected error: %v, got: %v", tc.expectedErr, err)
+			}
 			if err != nil {
-				if !tc.expectedErr {
-					t.Fatalf("Unexpected error: %v", err)
-				}
 				return
 			}
 			if !tc.expectedFrom.Equal(gotFrom) {
@@ -588,6 +1268,9 @@ func TestTimePeriod(t *testing.T) {
 			if !gotTo.Equal(tc.expectedTo) {
 				t.Errorf("Expected: %v, got: %v", tc.expectedTo, gotTo)
 			}
+			if tc.expectedLocation != "" && (gotFrom.Location().String() != tc.expectedLocation || gotTo.Location().String() != tc.expectedLocation) {
+				t.Errorf("Expected location: %s, got: %v, %v", tc.expectedLocation, gotFrom.Location(), gotTo.Location())
+			}
 		})
 	}
 }
@@ -5,18 +5,22 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	logger "github.com/sirupsen/logrus"
 
 	"github.com/pokt-foundation/portal-api-go/repository"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	// TODO: replace with pokt-foundation/relay-meter
 	"github.com/adshmh/meter/api"
 	"github.com/adshmh/meter/cmd"
 	"github.com/adshmh/meter/db"
+	"github.com/adshmh/meter/rpc"
 )
 
 const (
@@ -25,14 +29,29 @@ const (
 	TODAYS_METRICS_TTL_DEFAULT_SECONDS = 300
 	MAX_ARCHIVE_AGE_DEFAULT_DAYS       = 30
 	SERVER_PORT_DEFAULT                = 9898
+	GRPC_SERVER_PORT_DEFAULT           = 9897
+	TODAYS_METRICS_INTERVAL_MINUTES    = 60
 
 	ENV_LOAD_INTERVAL_SECONDS      = "LOAD_INTERVAL_SECONDS"
 	ENV_DAILY_METRICS_TTL_SECONDS  = "DAILY_METRICS_TTL_SECONDS"
 	ENV_TODAYS_METRICS_TTL_SECONDS = "TODAYS_METRICS_TTL_SECONDS"
 	ENV_MAX_ARCHIVE_AGE_DAYS       = "MAX_ARCHIVE_AGE"
+	ENV_TODAYS_METRICS_INTERVAL    = "TODAYS_METRICS_INTERVAL_MINUTES"
 	ENV_SERVER_PORT                = "API_SERVER_PORT"
+	ENV_GRPC_SERVER_PORT           = "GRPC_SERVER_PORT"
 	ENV_BACKEND_API_URL            = "BACKEND_API_URL"
 	ENV_BACKEND_API_TOKEN          = "BACKEND_API_TOKEN"
+	// Notifications on today's usage are enabled if the webhook URL is set. Rules are specified as a JSON array of api.NotificationRule
+	ENV_NOTIFICATION_WEBHOOK_URL = "NOTIFICATION_WEBHOOK_URL"
+	ENV_NOTIFICATION_RULES       = "NOTIFICATION_RULES"
+	// Authentication is enabled if either API keys, as a JSON array of api.APIKey, or the bearer token signing secret are set
+	ENV_API_KEYS          = "API_KEYS"
+	ENV_AUTH_TOKEN_SECRET = "AUTH_TOKEN_SECRET"
+	// Rate limiting is enabled if limits are set, as a JSON object of route class, e.g. api.RATE_LIMIT_CLASS_LIST, to api.RateLimit
+	ENV_RATE_LIMITS                    = "RATE_LIMITS"
+	ENV_RATE_LIMIT_TRUST_FORWARDED_FOR = "RATE_LIMIT_TRUST_FORWARDED_FOR"
+	// Export today's relays of each application on the metrics endpoint: adds one time series per application
+	ENV_EXPORT_APP_METRICS = "EXPORT_APP_METRICS"
 )
 
 type options struct {
@@ -40,9 +59,18 @@ type options struct {
 	dailyMetricsTTLSeconds  int
 	todaysMetricsTTLSeconds int
 	maxPastDays             int
+	todaysMetricsInterval   int
 	port                    int
+	grpcPort                int
 	backendApiUrl           string
 	backendApiToken         string
+	notificationWebhookUrl  string
+	notificationRules       []api.NotificationRule
+	apiKeys                 []api.APIKey
+	authTokenSecret         string
+	rateLimiterOptions      api.RateLimiterOptions
+	exportAppMetrics        bool
+	location                *time.Location
 }
 
 func gatherOptions() (options, error) {
@@ -58,6 +86,7 @@ func gatherOptions() (options, error) {
 		{value: &options.todaysMetricsTTLSeconds, defaultValue: TODAYS_METRICS_TTL_DEFAULT_SECONDS, envVar: ENV_TODAYS_METRICS_TTL_SECONDS},
 		{value: &options.maxPastDays, defaultValue: MAX_ARCHIVE_AGE_DEFAULT_DAYS, envVar: ENV_MAX_ARCHIVE_AGE_DAYS},
 		{value: &options.port, defaultValue: SERVER_PORT_DEFAULT, envVar: ENV_SERVER_PORT},
+		{value: &options.grpcPort, defaultValue: GRPC_SERVER_PORT_DEFAULT, envVar: ENV_GRPC_SERVER_PORT},
 	}
 
 	for _, o := range optsItems {
@@ -68,6 +97,12 @@ func gatherOptions() (options, error) {
 		*o.value = value
 	}
 
+	todaysMetricsInterval, err := cmd.GetMinutesFromEnv(ENV_TODAYS_METRICS_INTERVAL, TODAYS_METRICS_INTERVAL_MINUTES)
+	if err != nil {
+		return options, err
+	}
+	options.todaysMetricsInterval = todaysMetricsInterval
+
 	backendUrl := os.Getenv(ENV_BACKEND_API_URL)
 	if backendUrl == "" {
 		return options, fmt.Errorf("Missing required environment variable: %s", ENV_BACKEND_API_URL)
@@ -80,6 +115,46 @@ func gatherOptions() (options, error) {
 	}
 	options.backendApiToken = token
 
+	options.notificationWebhookUrl = os.Getenv(ENV_NOTIFICATION_WEBHOOK_URL)
+	if rules := os.Getenv(ENV_NOTIFICATION_RULES); rules != "" {
+		if err := json.Unmarshal([]byte(rules), &options.notificationRules); err != nil {
+			return options, fmt.Errorf("Invalid %s environment variable: %v", ENV_NOTIFICATION_RULES, err)
+		}
+	}
+
+	if keys := os.Getenv(ENV_API_KEYS); keys != "" {
+		if err := json.Unmarshal([]byte(keys), &options.apiKeys); err != nil {
+			return options, fmt.Errorf("Invalid %s environment variable: %v", ENV_API_KEYS, err)
+		}
+	}
+	options.authTokenSecret = os.Getenv(ENV_AUTH_TOKEN_SECRET)
+
+	if limits := os.Getenv(ENV_RATE_LIMITS); limits != "" {
+		if err := json.Unmarshal([]byte(limits), &options.rateLimiterOptions.Limits); err != nil {
+			return options, fmt.Errorf("Invalid %s environment variable: %v", ENV_RATE_LIMITS, err)
+		}
+	}
+	if trust := os.Getenv(ENV_RATE_LIMIT_TRUST_FORWARDED_FOR); trust != "" {
+		value, err := strconv.ParseBool(trust)
+		if err != nil {
+			return options, fmt.Errorf("Invalid %s environment variable: %v", ENV_RATE_LIMIT_TRUST_FORWARDED_FOR, err)
+		}
+		options.rateLimiterOptions.TrustForwardedFor = value
+	}
+	if export := os.Getenv(ENV_EXPORT_APP_METRICS); export != "" {
+		value, err := strconv.ParseBool(export)
+		if err != nil {
+			return options, fmt.Errorf("Invalid %s environment variable: %v", ENV_EXPORT_APP_METRICS, err)
+		}
+		options.exportAppMetrics = value
+	}
+
+	location, err := cmd.GetLocationFromEnv(cmd.REPORTING_TIMEZONE)
+	if err != nil {
+		return options, err
+	}
+	options.location = location
+
 	return options, nil
 }
 
@@ -89,9 +164,30 @@ type backendProvider struct {
 	backendApiToken string
 }
 
+// PingBackend verifies the backend apiserver is reachable: any response other than a server error is accepted.
+func (b *backendProvider) PingBackend(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.backendApiUrl, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Authorization", b.backendApiToken)
+
+	client := http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("Error from backend apiserver: %d", resp.StatusCode)
+	}
+	return nil
+}
+
 func (b *backendProvider) UserApps(user string) ([]string, error) {
 	// TODO: make the timeout configurable
-	ctx, _ := context.WithDeadline(context.Background(), time.Now().Add(10*time.Second))
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(10*time.Second))
+	defer cancel()
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/user/%s/application", b.backendApiUrl, user), nil)
 	if err != nil {
 		return nil, err
@@ -127,7 +223,8 @@ func (b *backendProvider) UserApps(user string) ([]string, error) {
 
 func (b *backendProvider) LoadBalancer(endpoint string) (*repository.LoadBalancer, error) {
 	// TODO: make the timeout configurable
-	ctx, _ := context.WithDeadline(context.Background(), time.Now().Add(10*time.Second))
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(10*time.Second))
+	defer cancel()
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/load_balancer/%s", b.backendApiUrl, endpoint), nil)
 	if err != nil {
 		return nil, err
@@ -143,6 +240,9 @@ func (b *backendProvider) LoadBalancer(endpoint string) (*repository.LoadBalance
 	if err != nil {
 		return nil, err
 	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", api.ErrLoadBalancerNotFound, endpoint)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("Error from backend apiserver: %d, %s", resp.StatusCode, string(body))
 	}
@@ -156,7 +256,8 @@ func (b *backendProvider) LoadBalancer(endpoint string) (*repository.LoadBalance
 
 func (b *backendProvider) LoadBalancers() ([]*repository.LoadBalancer, error) {
 	// TODO: make the timeout configurable
-	ctx, _ := context.WithDeadline(context.Background(), time.Now().Add(30*time.Second))
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(30*time.Second))
+	defer cancel()
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/load_balancer", b.backendApiUrl), nil)
 	if err != nil {
 		return nil, err
@@ -183,20 +284,44 @@ func (b *backendProvider) LoadBalancers() ([]*repository.LoadBalancer, error) {
 	return lbs, nil
 }
 
-// TODO: need a /health endpoint
+// getAuthenticator returns the authenticators enabled by the options: nil is returned if authentication is not enabled.
+func getAuthenticator(options options) (api.Authenticator, error) {
+	var authenticators api.Authenticators
+	if len(options.apiKeys) > 0 {
+		authenticator, err := api.NewAPIKeyAuthenticator(options.apiKeys)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, authenticator)
+	}
+	if options.authTokenSecret != "" {
+		authenticator, err := api.NewTokenAuthenticator([]byte(options.authTokenSecret))
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, authenticator)
+	}
+
+	if len(authenticators) == 0 {
+		return nil, nil
+	}
+	return authenticators, nil
+}
+
 func main() {
 	log := logger.New()
 
 	options, err := gatherOptions()
 	if err != nil {
-		log.WithFields(logger.Fields{"error": err, "options": options}).Warn("Invalid options specified")
+		// Options are not logged: they include credentials, e.g. API keys and the token secret
+		log.WithFields(logger.Fields{"error": err}).Warn("Invalid options specified")
 		os.Exit(1)
 	}
 
 	postgresOptions := cmd.GatherPostgresOptions()
 	pgClient, err := db.NewPostgresClient(postgresOptions)
 	if err != nil {
-		fmt.Errorf("Error setting up Postgres client: %v\n", err)
+		log.WithFields(logger.Fields{"error": err}).Warn("Error setting up Postgres client")
 		os.Exit(1)
 	}
 
@@ -206,6 +331,18 @@ func main() {
 		DailyMetricsTTL:  time.Duration(options.dailyMetricsTTLSeconds) * time.Second,
 		TodaysMetricsTTL: time.Duration(options.todaysMetricsTTLSeconds) * time.Second,
 		MaxPastDays:      time.Duration(options.maxPastDays) * 24 * time.Hour,
+
+		TodaysMetricsInterval: time.Duration(options.todaysMetricsInterval) * time.Minute,
+		ExportAppMetrics:      options.exportAppMetrics,
+		Location:              options.location,
+	}
+	if options.notificationWebhookUrl != "" {
+		notifier, err := api.NewWebhookNotifier(options.notificationWebhookUrl, options.notificationRules, options.location, log)
+		if err != nil {
+			log.WithFields(logger.Fields{"error": err}).Warn("Error setting up the webhook notifier")
+			os.Exit(1)
+		}
+		meterOptions.Notifier = notifier
 	}
 	log.WithFields(logger.Fields{"postgresOptions": postgresOptions, "meterOptions": meterOptions}).Info("Gathered options.")
 
@@ -215,7 +352,54 @@ func main() {
 		backendApiToken: options.backendApiToken,
 	}
 	meter := api.NewRelayMeter(&backend, log, meterOptions)
-	http.HandleFunc("/", api.GetHttpServer(meter, log))
+
+	handler := api.GetHttpServer(meter, log)
+	authenticator, err := getAuthenticator(options)
+	if err != nil {
+		log.WithFields(logger.Fields{"error": err}).Warn("Error setting up authentication")
+		os.Exit(1)
+	}
+	if authenticator != nil {
+		handler = api.RequireAuthorization(handler, authenticator, &backend, log)
+	} else {
+		log.Warn("No API keys or token secret specified: authentication is disabled")
+	}
+	if len(options.rateLimiterOptions.Limits) > 0 {
+		// Only verified credentials get their own limit: all other requests are rate limited per IP
+		options.rateLimiterOptions.Authenticator = authenticator
+		limiter, err := api.NewRateLimiter(options.rateLimiterOptions)
+		if err != nil {
+			log.WithFields(logger.Fields{"error": err}).Warn("Error setting up rate limiting")
+			os.Exit(1)
+		}
+		// Rate limiting is applied first, so requests with invalid credentials are rate limited too
+		handler = api.RequireRateLimit(handler, limiter, log)
+	}
+	// Instrumented last, so rejected requests are recorded too
+	http.HandleFunc("/", api.InstrumentHandler(handler))
+
+	// Health and metrics endpoints are not subject to authentication or rate limiting, so they can be used by probes and scrapers
+	checks := []api.HealthCheck{
+		api.MeterCheck(meter),
+		{Name: "postgres", Check: pgClient.PingContext},
+		{Name: "backend", Check: backend.PingBackend},
+	}
+	http.HandleFunc(api.HEALTH_PATH, api.GetHealthHandler(log))
+	http.HandleFunc(api.READINESS_PATH, api.GetReadinessHandler(checks, log))
+	http.Handle(api.METRICS_PATH, promhttp.Handler())
+
+	// The gRPC API shares the authentication settings of the HTTP API: when enabled, only admins are allowed access
+	grpcServer := rpc.NewServer(meter, authenticator, log)
+	go func() {
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", options.grpcPort))
+		if err != nil {
+			log.WithFields(logger.Fields{"error": err, "port": options.grpcPort}).Warn("Error listening on gRPC port")
+			return
+		}
+		log.WithFields(logger.Fields{"port": options.grpcPort}).Info("Starting the gRPC server...")
+		err = grpcServer.Serve(listener)
+		log.WithFields(logger.Fields{"error": err}).Warn("gRPC server exited.")
+	}()
 
 	log.Info("Starting the apiserver...")
 	http.ListenAndServe(fmt.Sprintf(":%d", options.port), nil)
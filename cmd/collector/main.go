@@ -3,11 +3,14 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	logger "github.com/sirupsen/logrus"
 
+	"github.com/adshmh/meter/api"
 	"github.com/adshmh/meter/cmd"
 	"github.com/adshmh/meter/collector"
 	"github.com/adshmh/meter/db"
@@ -17,16 +20,44 @@ const (
 	COLLECT_INTERVAL_DEFAULT_SECONDS = 300
 	REPORT_INTERVAL_DEFAULT_SECONDS = 30
 	MAX_ARCHIVE_AGE_DEFAULT_DAYS = 30
+	TODAYS_METRICS_INTERVAL_DEFAULT_MINUTES = 60
+	INGESTION_SERVER_PORT_DEFAULT = 9899
+	HEALTH_SERVER_PORT_DEFAULT = 9900
+	READINESS_MAX_MISSED_COLLECTIONS_DEFAULT = 3
+
+	SOURCE_INFLUXDB = "influxdb"
+	SOURCE_INGESTION = "ingestion"
+	SOURCE_FILE = "file"
 
 	ENV_COLLECT_INTERVAL_SECONDS = "COLLECTION_INTERVAL_SECONDS"
 	ENV_REPORT_INTERVAL_SECONDS = "REPORT_INTERVAL_SECONDS"
 	ENV_MAX_ARCHIVE_AGE_DAYS = "MAX_ARCHIVE_AGE"
+	ENV_TODAYS_METRICS_INTERVAL_MINUTES = "TODAYS_METRICS_INTERVAL_MINUTES"
+	// ENV_SOURCE selects where relay counts are collected from: InfluxDB (the default), or relay records pushed to the ingestion endpoint
+	ENV_SOURCE = "COLLECTOR_SOURCE"
+	ENV_INGESTION_SERVER_PORT = "INGESTION_SERVER_PORT"
+	// ENV_INGESTION_API_KEY is the shared secret clients of the ingestion endpoint need to send, as the X-API-Key header or a bearer token.
+	//	It is required when using the ingestion source.
+	ENV_INGESTION_API_KEY = "INGESTION_API_KEY"
+	// ENV_SOURCE_FILES_DIR is the directory holding relay records files, when using the file source
+	ENV_SOURCE_FILES_DIR = "SOURCE_FILES_DIR"
+	ENV_HEALTH_SERVER_PORT = "HEALTH_SERVER_PORT"
+	// The collector is reported as not ready if no collection has succeeded within this many collection intervals
+	ENV_READINESS_MAX_MISSED_COLLECTIONS = "READINESS_MAX_MISSED_COLLECTIONS"
 )
 
 type options struct {
 	collectionInterval int
 	reportingInterval int
 	maxArchiveAgeDays int
+	todaysMetricsIntervalMinutes int
+	source string
+	ingestionPort int
+	ingestionAPIKey string
+	sourceFilesDir string
+	healthPort int
+	readinessMaxMissedCollections int
+	location *time.Location
 }
 
 func gatherOptions() (options, error) {
@@ -45,32 +76,133 @@ func gatherOptions() (options, error) {
 		return options{}, err
 	}
 
+	todaysMetricsInterval, err := cmd.GetMinutesFromEnv(ENV_TODAYS_METRICS_INTERVAL_MINUTES, TODAYS_METRICS_INTERVAL_DEFAULT_MINUTES)
+	if err != nil {
+		return options{}, err
+	}
+
+	source := os.Getenv(ENV_SOURCE)
+	switch source {
+	case "":
+		source = SOURCE_INFLUXDB
+	case SOURCE_INFLUXDB, SOURCE_INGESTION, SOURCE_FILE:
+	default:
+		return options{}, fmt.Errorf("Invalid value for %s: %s", ENV_SOURCE, source)
+	}
+
+	ingestionPort, err := cmd.GetIntFromEnv(ENV_INGESTION_SERVER_PORT, INGESTION_SERVER_PORT_DEFAULT)
+	if err != nil {
+		return options{}, err
+	}
+
+	ingestionAPIKey := os.Getenv(ENV_INGESTION_API_KEY)
+	if source == SOURCE_INGESTION && ingestionAPIKey == "" {
+		return options{}, fmt.Errorf("Missing required environment variable: %s", ENV_INGESTION_API_KEY)
+	}
+
+	sourceFilesDir := os.Getenv(ENV_SOURCE_FILES_DIR)
+	if source == SOURCE_FILE && sourceFilesDir == "" {
+		return options{}, fmt.Errorf("Missing required environment variable: %s", ENV_SOURCE_FILES_DIR)
+	}
+
+	healthPort, err := cmd.GetIntFromEnv(ENV_HEALTH_SERVER_PORT, HEALTH_SERVER_PORT_DEFAULT)
+	if err != nil {
+		return options{}, err
+	}
+
+	readinessMaxMissedCollections, err := cmd.GetIntFromEnv(ENV_READINESS_MAX_MISSED_COLLECTIONS, READINESS_MAX_MISSED_COLLECTIONS_DEFAULT)
+	if err != nil {
+		return options{}, err
+	}
+
+	location, err := cmd.GetLocationFromEnv(cmd.REPORTING_TIMEZONE)
+	if err != nil {
+		return options{}, err
+	}
+
 	return options {
 		collectionInterval: collectionInterval,
 		reportingInterval: reportingInterval,
 		maxArchiveAgeDays: maxArchiveAge,
+		todaysMetricsIntervalMinutes: todaysMetricsInterval,
+		source: source,
+		ingestionPort: ingestionPort,
+		ingestionAPIKey: ingestionAPIKey,
+		sourceFilesDir: sourceFilesDir,
+		healthPort: healthPort,
+		readinessMaxMissedCollections: readinessMaxMissedCollections,
+		location: location,
 	}, nil
 }
 
-// TODO: need a /health endpoint
 func main() {
-	influxOptions := cmd.GatherInfluxOptions()
+	log := logger.New()
 	postgresOptions := cmd.GatherPostgresOptions()
 
-	influxClient := db.NewInfluxDBSource(influxOptions)
 	pgClient, err := db.NewPostgresClient(postgresOptions)
 	if err != nil {
-		fmt.Errorf("Error setting up Postgres client: %v\n", err)
+		fmt.Printf("Error setting up Postgres client: %v\n", err)
 		os.Exit(1)
 	}
 
 	options, err := gatherOptions()
 	if err != nil {
-		fmt.Errorf("Error gathering options: %v", err)
+		fmt.Printf("Error gathering options: %v\n", err)
 		os.Exit(1)
 	}
+	maxArchiveAge := time.Duration(options.maxArchiveAgeDays) * 24 * time.Hour
+	todaysMetricsInterval := time.Duration(options.todaysMetricsIntervalMinutes) * time.Minute
+
+	checks := []api.HealthCheck{
+		{Name: "postgres", Check: pgClient.PingContext},
+	}
+
+	var source collector.Source
+	switch options.source {
+	case SOURCE_INGESTION:
+		ingestionSource := collector.NewIngestionSource(maxArchiveAge, todaysMetricsInterval, options.location)
+		authenticator, err := api.NewAPIKeyAuthenticator([]api.APIKey{{Key: options.ingestionAPIKey, Admin: true}})
+		if err != nil {
+			fmt.Printf("Error setting up ingestion authentication: %v\n", err)
+			os.Exit(1)
+		}
+		http.HandleFunc("/v0/relays", collector.GetIngestionHandler(ingestionSource, authenticator, log))
+		go func() {
+			log.WithFields(logger.Fields{"port": options.ingestionPort}).Info("Starting the ingestion server...")
+			err := http.ListenAndServe(fmt.Sprintf(":%d", options.ingestionPort), nil)
+			log.WithFields(logger.Fields{"error": err}).Warn("Ingestion server exited.")
+		}()
+		source = ingestionSource
+	case SOURCE_FILE:
+		source = collector.NewFileSource(options.sourceFilesDir, todaysMetricsInterval, options.location)
+	default:
+		influxSource := db.NewInfluxDBSource(cmd.GatherInfluxOptions(options.location))
+		checks = append(checks, api.HealthCheck{Name: "influxdb", Check: influxSource.Ping})
+		source = influxSource
+	}
 
 	fmt.Printf("Starting the collector...")
-	collector := collector.NewCollector(influxClient, pgClient, time.Duration(options.maxArchiveAgeDays) * 24 * time.Hour, logger.New())
-	collector.Start(context.Background(), options.collectionInterval, options.reportingInterval)
+	c := collector.NewCollector(
+		source,
+		pgClient,
+		maxArchiveAge,
+		todaysMetricsInterval,
+		options.location,
+		log,
+	)
+
+	// The health and metrics endpoints are served separately from the ingestion endpoint, as they are needed with any source
+	maxCollectionAge := time.Duration(options.readinessMaxMissedCollections*options.collectionInterval) * time.Second
+	checks = append(checks, collector.CollectionCheck(c, maxCollectionAge))
+	healthMux := http.NewServeMux()
+	healthMux.HandleFunc(api.HEALTH_PATH, api.GetHealthHandler(log))
+	healthMux.HandleFunc(api.READINESS_PATH, api.GetReadinessHandler(checks, log))
+	healthMux.Handle(api.METRICS_PATH, promhttp.Handler())
+	go func() {
+		log.WithFields(logger.Fields{"port": options.healthPort}).Info("Starting the health server...")
+		err := http.ListenAndServe(fmt.Sprintf(":%d", options.healthPort), healthMux)
+		log.WithFields(logger.Fields{"error": err}).Warn("Health server exited.")
+	}()
+
+	c.Start(context.Background(), options.collectionInterval, options.reportingInterval)
 }
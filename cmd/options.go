@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"time"
+	// Embedded timezone database, so the reporting timezone can be loaded on hosts without one, e.g. scratch containers
+	_ "time/tzdata"
 
 	"github.com/adshmh/meter/db"
 )
@@ -18,6 +22,10 @@ const (
 	POSTGRES_PASSWORD = "POSTGRES_PASSWORD"
 	POSTGRES_DB = "POSTGRES_DB"
 	POSTGRES_HOST = "POSTGRES_HOST"
+
+	// REPORTING_TIMEZONE is the IANA name of the timezone whose midnight starts each day, e.g. America/New_York: defaults to UTC.
+	//	The collector and the apiserver need to use the same value. Days already stored, e.g. at midnight UTC, are kept as the same dates: see api.StoredDay
+	REPORTING_TIMEZONE = "REPORTING_TIMEZONE"
 )
 
 type options struct {
@@ -25,13 +33,14 @@ type options struct {
 	db.PostgresOptions
 }
 
-func GatherInfluxOptions() db.InfluxDBOptions {
+func GatherInfluxOptions(location *time.Location) db.InfluxDBOptions {
 	return db.InfluxDBOptions {
 		URL: os.Getenv(INFLUXDB_URL),
 		Token: os.Getenv(INFLUXDB_TOKEN),
 		Org: os.Getenv(INFLUXDB_ORG),
 		DailyBucket: os.Getenv(INFLUXDB_BUCKET_DAILY),
 		CurrentBucket: os.Getenv(INFLUXDB_BUCKET_CURRENT),
+		Location: location,
 	}
 }
 
@@ -62,3 +71,31 @@ func GetIntFromEnv(envVarName string, defaultValue int) (int, error) {
 	}
 	return value, nil
 }
+
+// GetMinutesFromEnv returns the number of minutes set by the environment variable, or the default if it is not set.
+//	Values under one minute are rejected, e.g. a zero length for today's metrics intervals.
+func GetMinutesFromEnv(envVarName string, defaultValue int) (int, error) {
+	value, err := GetIntFromEnv(envVarName, defaultValue)
+	if err != nil {
+		return 0, err
+	}
+
+	if value < 1 {
+		return 0, fmt.Errorf("Invalid %s environment variable: %d, needs to be at least 1 minute", envVarName, value)
+	}
+	return value, nil
+}
+
+// GetLocationFromEnv returns the timezone named by the environment variable, or UTC if it is not set.
+func GetLocationFromEnv(envVarName string) (*time.Location, error) {
+	name := os.Getenv(envVarName)
+	if name == "" {
+		return time.UTC, nil
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid %s environment variable: %v", envVarName, err)
+	}
+	return location, nil
+}
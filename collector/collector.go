@@ -3,6 +3,7 @@ package collector
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	logger "github.com/sirupsen/logrus"
@@ -13,11 +14,17 @@ import (
 const (
 	COLLECT_INTERVAL_SECONDS = 120
 	REPORT_INTERVAL_SECONDS  = 10
+
+	TODAYS_METRICS_INTERVAL_DEFAULT_MINUTES = 60
 )
 
 type Source interface {
 	DailyCounts(from, to time.Time) (map[time.Time]map[string]api.RelayCounts, error)
 	TodaysCounts() (map[string]api.RelayCounts, error)
+	// TodaysIntervalCounts returns today's metrics so far, split into intervals of the specified length and keyed by the start of each interval
+	TodaysIntervalCounts(interval time.Duration) (map[time.Time]map[string]api.RelayCounts, error)
+	// DailyLatency returns the latency statistics of each application, keyed by day: sources with no latency data return an empty map
+	DailyLatency(from, to time.Time) (map[time.Time]map[string]api.RelayLatency, error)
 }
 
 type Writer interface {
@@ -28,6 +35,10 @@ type Writer interface {
 	// TODO: allow overwriting today's metrics
 	WriteDailyUsage(counts map[time.Time]map[string]api.RelayCounts) error
 	WriteTodaysUsage(counts map[string]api.RelayCounts) error
+	// WriteTodaysIntervalUsage replaces the stored intervals of today's metrics
+	WriteTodaysIntervalUsage(counts map[time.Time]map[string]api.RelayCounts) error
+	// WriteDailyLatency writes the latency statistics, overwriting any existing statistics for the same application and day
+	WriteDailyLatency(latency map[time.Time]map[string]api.RelayLatency) error
 }
 
 type Collector interface {
@@ -38,37 +49,60 @@ type Collector interface {
 	// Collect and write metrics data: this will overwrite any existing metrics
 	//	This function exists to allow manually overriding the collector's behavior.
 	Collect(from, to time.Time) error
+	// LastCollected returns the time of the last successful periodic collection, or zero if there has been none yet
+	LastCollected() time.Time
 }
 
 // NewCollector returns a collector which will periodically (or on Collect being called)
 //	gathers metrics from the source and writes to the writer.
 //	maxArchiveAge is the oldest time for which metrics are saved
-func NewCollector(source Source, writer Writer, maxArchiveAge time.Duration, log *logger.Logger) Collector {
+//	todaysInterval is the length of the intervals today's metrics are split into, e.g. 1 hour
+//	loc is the reporting timezone, i.e. days start at midnight in this timezone: it needs to match the source's and the apiserver's setting
+func NewCollector(source Source, writer Writer, maxArchiveAge, todaysInterval time.Duration, loc *time.Location, log *logger.Logger) Collector {
 	return &collector{
-		Source:        source,
-		Writer:        writer,
-		MaxArchiveAge: maxArchiveAge,
-		Logger:        log,
+		Source:         source,
+		Writer:         writer,
+		MaxArchiveAge:  maxArchiveAge,
+		TodaysInterval: todaysInterval,
+		Location:       loc,
+		Logger:         log,
 	}
 }
 
 type collector struct {
 	Source
 	Writer
-	MaxArchiveAge time.Duration
+	MaxArchiveAge  time.Duration
+	TodaysInterval time.Duration
+	// Reporting timezone: defaults to UTC
+	Location *time.Location
 	*logger.Logger
+
+	// lastCollected is set by the collection goroutine, and read by health checks
+	mutex         sync.RWMutex
+	lastCollected time.Time
 }
 
 // Collects relay usage data from the source and uses the writer to store.
-//	-
+//	- Latency statistics are written before the daily counts: the existing daily counts determine the days to collect,
+//	so a failure to write the latency repeats the collection of the same days.
 func (c *collector) Collect(from, to time.Time) error {
 	c.Logger.WithFields(logger.Fields{"from": from, "to": to}).Info("Starting daily metrics collection...")
-	from, to, err := api.AdjustTimePeriod(from, to)
+	from, to, err := api.AdjustTimePeriodIn(from, to, c.location())
 	if err != nil {
 		return err
 	}
 	c.Logger.WithFields(logger.Fields{"from": from, "to": to}).Info("Daily metrics collection period adjusted.")
 
+	latency, err := c.Source.DailyLatency(from, to)
+	if err != nil {
+		return err
+	}
+	c.Logger.WithFields(logger.Fields{"daily_latency_count": len(latency), "from": from, "to": to}).Info("Collected daily latency metrics")
+	if err := c.Writer.WriteDailyLatency(latency); err != nil {
+		return err
+	}
+
 	counts, err := c.Source.DailyCounts(from, to)
 	if err != nil {
 		return err
@@ -84,7 +118,21 @@ func (c *collector) CollectTodaysMetrics() error {
 	}
 	c.Logger.WithFields(logger.Fields{"todays_metrics_count": len(todaysCounts)}).Info("Collected todays metrics")
 
-	return c.Writer.WriteTodaysUsage(todaysCounts)
+	if err := c.Writer.WriteTodaysUsage(todaysCounts); err != nil {
+		return err
+	}
+
+	interval := c.TodaysInterval
+	if interval == 0 {
+		interval = time.Duration(TODAYS_METRICS_INTERVAL_DEFAULT_MINUTES) * time.Minute
+	}
+	intervalCounts, err := c.Source.TodaysIntervalCounts(interval)
+	if err != nil {
+		return err
+	}
+	c.Logger.WithFields(logger.Fields{"todays_intervals_count": len(intervalCounts), "interval": interval}).Info("Collected todays metrics intervals")
+
+	return c.Writer.WriteTodaysIntervalUsage(intervalCounts)
 }
 
 func (c *collector) collect() error {
@@ -101,35 +149,87 @@ func (c *collector) collect() error {
 
 	// We assume there are no gaps between stored metrics from start to end, so
 	// 	start collecting metrics after the last saved date
-	dayLayout := "2006-01-02"
-	today, err := time.Parse(dayLayout, time.Now().Format(dayLayout))
+	loc := c.location()
+	now := time.Now().In(loc)
+	today, _, err := api.AdjustTimePeriodIn(now, now, loc)
 	if err != nil {
 		return err
 	}
-	if last.Equal(today.AddDate(0, 0, -1)) || last.After(today.AddDate(0, 0, -1)) {
+	// The stored time is the start of the day in the reporting timezone, or midnight UTC for days collected before the timezone
+	//	was configurable: the same day is used as the apiserver's, see api.StoredDay, so those days are not collected again.
+	//	The first day collected after changing the timezone still partially overlaps the last day stored at midnight UTC.
+	lastDay := api.StoredDay(last, loc)
+	if lastDay.Equal(today.AddDate(0, 0, -1)) || lastDay.After(today.AddDate(0, 0, -1)) {
 		c.Logger.WithFields(logger.Fields{"today": today, "last_daily_collected": last}).Info("Last collected daily metric was yesterday, skipping daily metrics collection...")
 		return nil
 	}
 	var from time.Time
 	if first.Equal(time.Time{}) {
-		from = time.Now().Add(-1 * c.MaxArchiveAge)
+		from = now.Add(-1 * c.MaxArchiveAge)
 	} else {
-		from = last.AddDate(0, 0, 1)
+		from = lastDay.AddDate(0, 0, 1)
 		if from.After(today) {
 			from = today
 		}
 	}
 
 	// TODO: cover with unit tests
-	return c.Collect(from, time.Now().AddDate(0, 0, -1))
+	return c.Collect(from, now.AddDate(0, 0, -1))
+}
+
+// location returns the reporting timezone
+func (c *collector) location() *time.Location {
+	if c.Location == nil {
+		return time.UTC
+	}
+	return c.Location
+}
+
+// run runs a periodic collection, recording the time if successful
+func (c *collector) run() {
+	start := time.Now()
+	err := c.collect()
+	collectionDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		collectionRuns.WithLabelValues(OUTCOME_FAILURE).Inc()
+		c.Logger.WithFields(logger.Fields{"error": err}).Warn("Failed to collect data")
+		return
+	}
+	collectionRuns.WithLabelValues(OUTCOME_SUCCESS).Inc()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.lastCollected = time.Now()
+	lastCollectedTimestamp.Set(float64(c.lastCollected.Unix()))
+}
+
+func (c *collector) LastCollected() time.Time {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.lastCollected
+}
+
+// CollectionCheck fails if the last successful collection is older than maxAge, e.g. a few collection intervals.
+func CollectionCheck(c Collector, maxAge time.Duration) api.HealthCheck {
+	return api.HealthCheck{
+		Name: "collection",
+		Check: func(_ context.Context) error {
+			last := c.LastCollected()
+			if last.IsZero() {
+				return fmt.Errorf("No successful collection yet")
+			}
+			if age := time.Since(last); age > maxAge {
+				return fmt.Errorf("Last successful collection was %v ago, at %v", age.Round(time.Second), last)
+			}
+			return nil
+		},
+	}
 }
 
 func (c *collector) Start(ctx context.Context, collectIntervalSeconds, reportIntervalSeconds int) {
 	// Do an initial data collection, and then repeat on set intervals
 	c.Logger.Info("Starting initial data collection...")
-	if err := c.collect(); err != nil {
-		c.Logger.WithFields(logger.Fields{"error": err}).Warn("Failed to collect data")
-	}
+	c.run()
 	c.Logger.Info("Initial data collection completed.")
 
 	reportTicker := time.NewTicker(time.Duration(reportIntervalSeconds) * time.Second)
@@ -146,9 +246,7 @@ func (c *collector) Start(ctx context.Context, collectIntervalSeconds, reportInt
 			c.Logger.Info(fmt.Sprintf("Will collect data in %d seconds...", remaining))
 		case <-collectTicker.C:
 			c.Logger.Info("Starting data collection...")
-			if err := c.collect(); err != nil {
-				c.Logger.WithFields(logger.Fields{"error": err}).Warn("Failed to collect data")
-			}
+			c.run()
 			c.Logger.Info("Data collection completed.")
 			remaining = collectIntervalSeconds
 		}
@@ -2,9 +2,11 @@ package collector
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	logger "github.com/sirupsen/logrus"
 
 	"github.com/adshmh/meter/api"
@@ -12,14 +14,30 @@ import (
 
 func TestCollect(t *testing.T) {
 	dayLayout := "2006-01-02"
-	today, err := time.Parse(dayLayout, time.Now().Format(dayLayout))
+	today, err := time.Parse(dayLayout, time.Now().UTC().Format(dayLayout))
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
+	// A timezone whose current date is, for most of the day, not the same as in UTC
+	farEast := time.FixedZone("UTC+14", 14*60*60)
+	farEastToday, err := time.ParseInLocation(dayLayout, time.Now().In(farEast).Format(dayLayout), farEast)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// A timezone west of UTC, with days stored at midnight UTC before the reporting timezone was configurable
+	west := time.FixedZone("UTC-5", -5*60*60)
+	westToday, err := time.ParseInLocation(dayLayout, time.Now().In(west).Format(dayLayout), west)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	legacyDay := func(day time.Time) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	}
 
 	testCases := []struct {
 		name               string
 		maxArchiveAge      time.Duration
+		location           *time.Location
 		firstSaved         time.Time
 		lastSaved          time.Time
 		expectedFrom       time.Time
@@ -54,6 +72,41 @@ func TestCollect(t *testing.T) {
 			firstSaved:    today.AddDate(0, 0, -40),
 			lastSaved:     today,
 		},
+		{
+			name:          "Days start at midnight in the reporting timezone",
+			maxArchiveAge: 30 * 24 * time.Hour,
+			location:      farEast,
+			// Stored times are read back in UTC
+			firstSaved:         farEastToday.AddDate(0, 0, -40).UTC(),
+			lastSaved:          farEastToday.AddDate(0, 0, -10).UTC(),
+			shouldCollectDaily: true,
+			expectedFrom:       farEastToday.AddDate(0, 0, -9),
+			expectedTo:         farEastToday,
+		},
+		{
+			name:          "Daily metrics are skipped if yesterday in the reporting timezone was collected",
+			maxArchiveAge: 30 * 24 * time.Hour,
+			location:      farEast,
+			firstSaved:    farEastToday.AddDate(0, 0, -40).UTC(),
+			lastSaved:     farEastToday.AddDate(0, 0, -1).UTC(),
+		},
+		{
+			name:               "Days stored at midnight UTC are not collected again",
+			maxArchiveAge:      30 * 24 * time.Hour,
+			location:           west,
+			firstSaved:         legacyDay(westToday.AddDate(0, 0, -40)),
+			lastSaved:          legacyDay(westToday.AddDate(0, 0, -10)),
+			shouldCollectDaily: true,
+			expectedFrom:       westToday.AddDate(0, 0, -9),
+			expectedTo:         westToday,
+		},
+		{
+			name:          "Daily metrics are skipped if yesterday was stored at midnight UTC",
+			maxArchiveAge: 30 * 24 * time.Hour,
+			location:      west,
+			firstSaved:    legacyDay(westToday.AddDate(0, 0, -40)),
+			lastSaved:     legacyDay(westToday.AddDate(0, 0, -1)),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -67,6 +120,7 @@ func TestCollect(t *testing.T) {
 				Source:        source,
 				Writer:        writer,
 				MaxArchiveAge: tc.maxArchiveAge,
+				Location:      tc.location,
 				Logger:        logger.New(),
 			}
 			if err := c.collect(); err != nil {
@@ -81,6 +135,14 @@ func TestCollect(t *testing.T) {
 				t.Fatalf("Expected 1 write of todays metrics, got: %d", writer.todaysWrites)
 			}
 
+			if writer.todaysIntervalWrites != 1 {
+				t.Fatalf("Expected 1 write of todays metrics intervals, got: %d", writer.todaysIntervalWrites)
+			}
+
+			if source.requestedInterval != time.Duration(TODAYS_METRICS_INTERVAL_DEFAULT_MINUTES)*time.Minute {
+				t.Errorf("Expected todays metrics interval: %v, got: %v", time.Duration(TODAYS_METRICS_INTERVAL_DEFAULT_MINUTES)*time.Minute, source.requestedInterval)
+			}
+
 			if source.dailyMetricsCollected != tc.shouldCollectDaily {
 				t.Fatalf("Expected daily metrics collection to be: %t, got: %t", tc.shouldCollectDaily, source.dailyMetricsCollected)
 			}
@@ -100,6 +162,53 @@ func TestCollect(t *testing.T) {
 	}
 }
 
+func TestCollectLatency(t *testing.T) {
+	day := time.Date(2022, time.July, 20, 0, 0, 0, 0, time.UTC)
+	latency := map[time.Time]map[string]api.RelayLatency{
+		day: {"app1": {Relays: 100, Average: 0.2, P50: 0.15, P95: 0.5, P99: 0.9}},
+	}
+
+	testCases := []struct {
+		name                string
+		latencyErr          error
+		expectedLatency     map[time.Time]map[string]api.RelayLatency
+		expectedDailyWrites int
+	}{
+		{
+			name:                "Latency is written alongside the daily counts",
+			expectedLatency:     latency,
+			expectedDailyWrites: 1,
+		},
+		{
+			name:       "Daily counts are not written if writing the latency fails",
+			latencyErr: errors.New("write failed"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := &fakeSource{latency: latency}
+			writer := &fakeWriter{latencyErr: tc.latencyErr}
+			c := &collector{
+				Source: source,
+				Writer: writer,
+				Logger: logger.New(),
+			}
+
+			err := c.Collect(day, day)
+			if !errors.Is(err, tc.latencyErr) {
+				t.Fatalf("Expected error: %v, got: %v", tc.latencyErr, err)
+			}
+			if diff := cmp.Diff(tc.expectedLatency, writer.latency); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+			if writer.dailyWrites != tc.expectedDailyWrites {
+				t.Errorf("Expected %d daily writes, got: %d", tc.expectedDailyWrites, writer.dailyWrites)
+			}
+		})
+	}
+}
+
 func TestStart(t *testing.T) {
 	testCases := []struct {
 		name             string
@@ -140,6 +249,56 @@ func TestStart(t *testing.T) {
 	}
 }
 
+func TestCollectionCheck(t *testing.T) {
+	yesterday := time.Now().AddDate(0, 0, -1)
+
+	testCases := []struct {
+		name          string
+		lastCollected time.Time
+		writer        *fakeWriter
+		expectedErr   bool
+	}{
+		{
+			name:        "No collection yet",
+			expectedErr: true,
+		},
+		{
+			name:   "Successful collection",
+			writer: &fakeWriter{first: yesterday, last: yesterday},
+		},
+		{
+			name:          "Failed collection",
+			lastCollected: time.Now().Add(-time.Hour),
+			writer:        &fakeWriter{latencyErr: errors.New("write error")},
+			expectedErr:   true,
+		},
+		{
+			name:          "Recent collection",
+			lastCollected: time.Now().Add(-5 * time.Minute),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &collector{
+				Source:        &fakeSource{},
+				Writer:        tc.writer,
+				MaxArchiveAge: 30 * 24 * time.Hour,
+				Logger:        logger.New(),
+				lastCollected: tc.lastCollected,
+			}
+			if tc.writer != nil {
+				c.run()
+			}
+
+			err := CollectionCheck(c, 10*time.Minute).Check(context.Background())
+			if (err != nil) != tc.expectedErr {
+				t.Errorf("Expected error: %t, got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
 type fakeSource struct {
 	requestedFrom time.Time
 	requestedTo   time.Time
@@ -150,6 +309,11 @@ type fakeSource struct {
 	todaysCounts           map[string]api.RelayCounts
 	todaysMetricsCollected bool
 	dailyMetricsCollected  bool
+
+	todaysIntervalCounts map[time.Time]map[string]api.RelayCounts
+	requestedInterval    time.Duration
+
+	latency map[time.Time]map[string]api.RelayLatency
 }
 
 func (f *fakeSource) DailyCounts(from, to time.Time) (map[time.Time]map[string]api.RelayCounts, error) {
@@ -164,11 +328,26 @@ func (f *fakeSource) TodaysCounts() (map[string]api.RelayCounts, error) {
 	return f.todaysCounts, nil
 }
 
+func (f *fakeSource) TodaysIntervalCounts(interval time.Duration) (map[time.Time]map[string]api.RelayCounts, error) {
+	f.requestedInterval = interval
+	return f.todaysIntervalCounts, nil
+}
+
+func (f *fakeSource) DailyLatency(from, to time.Time) (map[time.Time]map[string]api.RelayLatency, error) {
+	return f.latency, nil
+}
+
 type fakeWriter struct {
 	first        time.Time
 	last         time.Time
 	callsCount   int
 	todaysWrites int
+
+	todaysIntervalWrites int
+
+	latency     map[time.Time]map[string]api.RelayLatency
+	dailyWrites int
+	latencyErr  error
 }
 
 func (f *fakeWriter) ExistingMetricsTimespan() (time.Time, time.Time, error) {
@@ -177,6 +356,7 @@ func (f *fakeWriter) ExistingMetricsTimespan() (time.Time, time.Time, error) {
 }
 
 func (f *fakeWriter) WriteDailyUsage(counts map[time.Time]map[string]api.RelayCounts) error {
+	f.dailyWrites++
 	return nil
 }
 
@@ -184,3 +364,16 @@ func (f *fakeWriter) WriteTodaysUsage(counts map[string]api.RelayCounts) error {
 	f.todaysWrites++
 	return nil
 }
+
+func (f *fakeWriter) WriteTodaysIntervalUsage(counts map[time.Time]map[string]api.RelayCounts) error {
+	f.todaysIntervalWrites++
+	return nil
+}
+
+func (f *fakeWriter) WriteDailyLatency(latency map[time.Time]map[string]api.RelayLatency) error {
+	if f.latencyErr != nil {
+		return f.latencyErr
+	}
+	f.latency = latency
+	return nil
+}
@@ -0,0 +1,196 @@
+package collector
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adshmh/meter/api"
+)
+
+const (
+	FILE_EXTENSION_NDJSON = ".ndjson"
+	FILE_EXTENSION_JSONL  = ".jsonl"
+	FILE_EXTENSION_CSV    = ".csv"
+
+	// Columns expected in the header row of CSV files: columns can be in any order
+	CSV_COLUMN_TIME        = "time"
+	CSV_COLUMN_APPLICATION = "application"
+	CSV_COLUMN_SUCCESS     = "success"
+	CSV_COLUMN_FAILURE     = "failure"
+	// Optional column
+	CSV_COLUMN_CHAIN = "chain"
+)
+
+// NewFileSource returns a source which reads relay records from the files in the specified directory, e.g. exports of relay counts.
+//	Supported formats, selected by file extension:
+//	- Newline-delimited JSON (.ndjson or .jsonl): one RelayRecord per line
+//	- CSV (.csv): a header row naming the time, application, success and failure columns, and optionally the chain column, followed by one record per row. Time uses the RFC3339 layout.
+//	Files with other extensions are ignored. The files are read on every call, so new files dropped in the directory are picked up on the next collection.
+//	Records are aggregated into days starting at midnight in the specified location, i.e. the reporting timezone.
+func NewFileSource(dir string, interval time.Duration, loc *time.Location) Source {
+	return &fileSource{
+		Dir:      dir,
+		Interval: interval,
+		Location: loc,
+	}
+}
+
+type fileSource struct {
+	Dir      string
+	Interval time.Duration
+	Location *time.Location
+}
+
+func (f *fileSource) DailyCounts(from, to time.Time) (map[time.Time]map[string]api.RelayCounts, error) {
+	aggregator, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	return aggregator.daily(from, to), nil
+}
+
+func (f *fileSource) TodaysCounts() (map[string]api.RelayCounts, error) {
+	aggregator, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	return aggregator.todays(), nil
+}
+
+func (f *fileSource) TodaysIntervalCounts(interval time.Duration) (map[time.Time]map[string]api.RelayCounts, error) {
+	aggregator, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	return aggregator.todaysIntervals(interval), nil
+}
+
+// DailyLatency returns no latency statistics: relay records do not include the relays' latency
+func (f *fileSource) DailyLatency(from, to time.Time) (map[time.Time]map[string]api.RelayLatency, error) {
+	return map[time.Time]map[string]api.RelayLatency{}, nil
+}
+
+// load reads all the records in the source's directory.
+func (f *fileSource) load() (*relayAggregator, error) {
+	entries, err := os.ReadDir(f.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregator := newRelayAggregator(f.Interval, f.Location)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		var parse func(io.Reader) ([]RelayRecord, error)
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case FILE_EXTENSION_NDJSON, FILE_EXTENSION_JSONL:
+			parse = parseNDJSONRecords
+		case FILE_EXTENSION_CSV:
+			parse = parseCSVRecords
+		default:
+			continue
+		}
+
+		records, err := readRecordsFile(filepath.Join(f.Dir, entry.Name()), parse)
+		if err != nil {
+			return nil, err
+		}
+		aggregator.add(records)
+	}
+	return aggregator, nil
+}
+
+func readRecordsFile(path string, parse func(io.Reader) ([]RelayRecord, error)) ([]RelayRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	records, err := parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading relay records from %s: %w", path, err)
+	}
+	return records, nil
+}
+
+func parseNDJSONRecords(r io.Reader) ([]RelayRecord, error) {
+	var records []RelayRecord
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var record RelayRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		if err := validateRecord(record); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+func parseCSVRecords(r io.Reader) ([]RelayRecord, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int)
+	for i, name := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{CSV_COLUMN_TIME, CSV_COLUMN_APPLICATION, CSV_COLUMN_SUCCESS, CSV_COLUMN_FAILURE} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing column in header row: %s", name)
+		}
+	}
+
+	var records []RelayRecord
+	for i, row := range rows[1:] {
+		line := i + 2
+		ts, err := time.Parse(time.RFC3339, row[columns[CSV_COLUMN_TIME]])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid time: %v", line, err)
+		}
+		success, err := strconv.ParseInt(row[columns[CSV_COLUMN_SUCCESS]], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid success count: %v", line, err)
+		}
+		failure, err := strconv.ParseInt(row[columns[CSV_COLUMN_FAILURE]], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid failure count: %v", line, err)
+		}
+
+		record := RelayRecord{
+			Application: row[columns[CSV_COLUMN_APPLICATION]],
+			Time:        ts,
+			Success:     success,
+			Failure:     failure,
+		}
+		if column, ok := columns[CSV_COLUMN_CHAIN]; ok {
+			record.Chain = row[column]
+		}
+		if err := validateRecord(record); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
@@ -0,0 +1,155 @@
+package collector
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/adshmh/meter/api"
+)
+
+func TestFileSourceDailyCounts(t *testing.T) {
+	day1 := time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	source := NewFileSource(filepath.Join("testdata", "files"), time.Hour, time.UTC)
+	got, err := source.DailyCounts(day1, day2.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[time.Time]map[string]api.RelayCounts{
+		day1: {
+			"app1": {Success: 10 + 5, Failure: 2 + 3},
+			"app2": {Success: 4},
+		},
+		day2: {
+			"app1": {Success: 1, Failure: 1},
+			"app3": {Success: 8, Failure: 1},
+		},
+		// Days with no records have an empty entry: 'to' is exclusive
+		day2.AddDate(0, 0, 1): {},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected value (-want +got):\n%s", diff)
+	}
+}
+
+func TestFileSourceTodaysCounts(t *testing.T) {
+	now := time.Now().UTC()
+	dir := t.TempDir()
+	ndjson := `{"Application": "app1", "Time": "` + now.Format(time.RFC3339) + `", "Success": 3, "Failure": 1}` + "\n"
+	csv := "time,application,success,failure\n" +
+		now.Format(time.RFC3339) + ",app1,2,2\n" +
+		now.AddDate(0, 0, -1).Format(time.RFC3339) + ",app1,100,100\n"
+	if err := os.WriteFile(filepath.Join(dir, "today.ndjson"), []byte(ndjson), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "today.csv"), []byte(csv), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	source := NewFileSource(dir, time.Hour, time.UTC)
+	todays, err := source.TodaysCounts()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedTodays := map[string]api.RelayCounts{"app1": {Success: 5, Failure: 3}}
+	if diff := cmp.Diff(expectedTodays, todays); diff != "" {
+		t.Errorf("unexpected todays counts (-want +got):\n%s", diff)
+	}
+
+	intervals, err := source.TodaysIntervalCounts(time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedIntervals := map[time.Time]map[string]api.RelayCounts{now.Truncate(time.Hour): expectedTodays}
+	if diff := cmp.Diff(expectedIntervals, intervals); diff != "" {
+		t.Errorf("unexpected todays intervals (-want +got):\n%s", diff)
+	}
+}
+
+func TestFileSourceInvalidFiles(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fileName string
+		content  string
+	}{
+		{
+			name:     "Invalid JSON line",
+			fileName: "relays.ndjson",
+			content:  "{\"Application\": \"app1\"\n",
+		},
+		{
+			name:     "Invalid JSON record",
+			fileName: "relays.jsonl",
+			content:  `{"Time": "2022-07-01T01:00:00Z", "Success": 1}` + "\n",
+		},
+		{
+			name:     "Missing CSV column",
+			fileName: "relays.csv",
+			content:  "time,application,success\n2022-07-01T01:00:00Z,app1,1\n",
+		},
+		{
+			name:     "Invalid CSV count",
+			fileName: "relays.csv",
+			content:  "time,application,success,failure\n2022-07-01T01:00:00Z,app1,1,many\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, tc.fileName), []byte(tc.content), 0644); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			source := NewFileSource(dir, time.Hour, time.UTC)
+			if _, err := source.TodaysCounts(); err == nil {
+				t.Errorf("Expected error reading %s, got nil", tc.fileName)
+			}
+		})
+	}
+
+	source := NewFileSource(filepath.Join(t.TempDir(), "missing"), time.Hour, time.UTC)
+	if _, err := source.TodaysCounts(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected error: %v, got: %v", os.ErrNotExist, err)
+	}
+}
+
+func TestFileSourceChains(t *testing.T) {
+	now := time.Now().UTC()
+	dir := t.TempDir()
+	csv := "time,application,chain,success,failure\n" +
+		now.Format(time.RFC3339) + ",app1,0021,5,1\n" +
+		now.Format(time.RFC3339) + ",app1,0009,2,0\n" +
+		now.Format(time.RFC3339) + ",app1,,1,1\n"
+	if err := os.WriteFile(filepath.Join(dir, "chains.csv"), []byte(csv), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	source := NewFileSource(dir, time.Hour, time.UTC)
+	todays, err := source.TodaysCounts()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]api.RelayCounts{
+		"app1": {
+			Success: 8,
+			Failure: 2,
+			Chains: map[string]api.RelayCounts{
+				"0021": {Success: 5, Failure: 1},
+				"0009": {Success: 2},
+				// Records with no chain are under the empty chain ID
+				"": {Success: 1, Failure: 1},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, todays); diff != "" {
+		t.Errorf("unexpected todays counts (-want +got):\n%s", diff)
+	}
+}
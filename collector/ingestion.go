@@ -0,0 +1,161 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+
+	"github.com/adshmh/meter/api"
+)
+
+const (
+	// Maximum size of a single batch of relay records
+	INGESTION_MAX_BODY_BYTES = 10 * 1024 * 1024
+	// Records later than this, relative to the current time, are rejected
+	INGESTION_MAX_CLOCK_SKEW = 5 * time.Minute
+)
+
+// IngestionResponse is returned by the ingestion endpoint for a successfully ingested batch.
+type IngestionResponse struct {
+	Records int
+}
+
+// IngestionSource is a Source fed by relay records pushed to the collector, e.g. through the handler returned by GetIngestionHandler.
+//	It allows running the collector without InfluxDB. Records are only kept in memory:
+//	today's records are lost if the collector restarts, and records for a previous day are lost if they arrive after that day has been collected.
+type IngestionSource interface {
+	Source
+	// Ingest aggregates the records into daily and today's counts
+	Ingest(records []RelayRecord) error
+}
+
+// NewIngestionSource returns an ingestion source which keeps the daily counts for up to maxArchiveAge,
+//	and aggregates today's counts into intervals of the specified length. Days start at midnight in the specified location, i.e. the reporting timezone.
+func NewIngestionSource(maxArchiveAge, interval time.Duration, loc *time.Location) IngestionSource {
+	return &ingestionSource{
+		MaxArchiveAge: maxArchiveAge,
+		aggregator:    newRelayAggregator(interval, loc),
+	}
+}
+
+type ingestionSource struct {
+	MaxArchiveAge time.Duration
+
+	aggregator *relayAggregator
+	mutex      sync.Mutex
+}
+
+func (i *ingestionSource) Ingest(records []RelayRecord) error {
+	now := time.Now()
+	oldest := i.aggregator.dayOf(now.Add(-1 * i.MaxArchiveAge))
+
+	// Validate the whole batch first, so a batch is either fully ingested or rejected
+	for _, r := range records {
+		if err := validateRecord(r); err != nil {
+			return err
+		}
+		if r.Time.Before(oldest) || r.Time.After(now.Add(INGESTION_MAX_CLOCK_SKEW)) {
+			return fmt.Errorf("%w: record time %v for application %s is outside the accepted period", api.InvalidRequest, r.Time, r.Application)
+		}
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.aggregator.add(records)
+	// Drop the entries which are no longer needed
+	i.aggregator.prune(oldest)
+	return nil
+}
+
+// DailyCounts returns the ingested relays per application, with an entry per day in the specified time period.
+func (i *ingestionSource) DailyCounts(from, to time.Time) (map[time.Time]map[string]api.RelayCounts, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	return i.aggregator.daily(from, to), nil
+}
+
+func (i *ingestionSource) TodaysCounts() (map[string]api.RelayCounts, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	return i.aggregator.todays(), nil
+}
+
+func (i *ingestionSource) TodaysIntervalCounts(interval time.Duration) (map[time.Time]map[string]api.RelayCounts, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	return i.aggregator.todaysIntervals(interval), nil
+}
+
+// DailyLatency returns no latency statistics: relay records do not include the relays' latency
+func (i *ingestionSource) DailyLatency(from, to time.Time) (map[time.Time]map[string]api.RelayLatency, error) {
+	return map[time.Time]map[string]api.RelayLatency{}, nil
+}
+
+// GetIngestionHandler returns an HTTP handler which accepts batches of relay records, as a JSON array, on POST requests.
+//	Requests need valid credentials for the authenticator, e.g. the shared ingestion API key: all requests are rejected if it is nil.
+//	Errors are returned as an api.ErrorResponse, as done by the apiserver.
+func GetIngestionHandler(source IngestionSource, authenticator api.Authenticator, l *logger.Logger) func(w http.ResponseWriter, req *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log := l.WithFields(logger.Fields{"method": req.Method, "path": req.URL.Path})
+
+		if authenticator == nil {
+			log.Warn("Unauthenticated ingestion request: no authenticator is set")
+			w.Header().Set(api.HEADER_WWW_AUTHENTICATE, "Bearer")
+			api.WriteError(l, w, http.StatusUnauthorized, api.ERROR_CODE_UNAUTHENTICATED, api.ErrUnauthenticated.Error())
+			return
+		}
+		if _, err := authenticator.Authenticate(req); err != nil {
+			log.WithFields(logger.Fields{"error": err}).Warn("Unauthenticated ingestion request")
+			w.Header().Set(api.HEADER_WWW_AUTHENTICATE, "Bearer")
+			api.WriteError(l, w, http.StatusUnauthorized, api.ERROR_CODE_UNAUTHENTICATED, api.ErrUnauthenticated.Error())
+			return
+		}
+
+		if req.Method != http.MethodPost {
+			log.Warn("Incorrect request method, expected: " + http.MethodPost)
+			w.Header().Set("Allow", http.MethodPost)
+			api.WriteError(l, w, http.StatusMethodNotAllowed, api.ERROR_CODE_METHOD_NOT_ALLOWED, fmt.Sprintf("Incorrect request method, expected: %s, got: %s", http.MethodPost, req.Method))
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, INGESTION_MAX_BODY_BYTES))
+		if err != nil {
+			log.WithFields(logger.Fields{"error": err}).Warn("Error reading request body")
+			api.WriteError(l, w, http.StatusBadRequest, api.ERROR_CODE_INVALID_REQUEST, fmt.Sprintf("Error reading request body: %v", err))
+			return
+		}
+
+		var records []RelayRecord
+		if err := json.Unmarshal(body, &records); err != nil {
+			log.WithFields(logger.Fields{"error": err}).Warn("Invalid relay records")
+			api.WriteError(l, w, http.StatusBadRequest, api.ERROR_CODE_INVALID_REQUEST, fmt.Sprintf("Invalid relay records: %v", err))
+			return
+		}
+
+		if err := source.Ingest(records); err != nil {
+			log.WithFields(logger.Fields{"error": err}).Warn("Error ingesting relay records")
+			api.WriteError(l, w, http.StatusBadRequest, api.ERROR_CODE_INVALID_REQUEST, err.Error())
+			return
+		}
+		log.WithFields(logger.Fields{"records_count": len(records)}).Info("Ingested relay records")
+
+		bytes, err := json.Marshal(IngestionResponse{Records: len(records)})
+		if err != nil {
+			log.WithFields(logger.Fields{"error": err}).Warn("Internal error marshalling response")
+			api.WriteError(l, w, http.StatusInternalServerError, api.ERROR_CODE_INTERNAL_ERROR, "Internal server error")
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(bytes)
+	}
+}
@@ -0,0 +1,218 @@
+package collector
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	logger "github.com/sirupsen/logrus"
+
+	"github.com/adshmh/meter/api"
+)
+
+func TestIngestionSource(t *testing.T) {
+	now := time.Now().UTC()
+	today := now.Truncate(24 * time.Hour)
+	yesterday := today.AddDate(0, 0, -1)
+	currentInterval := now.Truncate(10 * time.Minute)
+
+	source := NewIngestionSource(30*24*time.Hour, 10*time.Minute, time.UTC)
+	records := []RelayRecord{
+		{Application: "app1", Time: yesterday.Add(time.Hour), Success: 5, Failure: 1},
+		{Application: "app1", Time: yesterday.Add(2 * time.Hour), Success: 3, Failure: 2},
+		{Application: "app2", Time: yesterday.Add(3 * time.Hour), Success: 7},
+		{Application: "app1", Time: now, Success: 10, Failure: 4},
+		{Application: "app2", Time: now, Success: 1, Failure: 1},
+	}
+	if err := source.Ingest(records); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	daily, err := source.DailyCounts(yesterday, today)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedDaily := map[time.Time]map[string]api.RelayCounts{
+		yesterday: {
+			"app1": {Success: 8, Failure: 3},
+			"app2": {Success: 7},
+		},
+	}
+	if diff := cmp.Diff(expectedDaily, daily); diff != "" {
+		t.Errorf("unexpected daily counts (-want +got):\n%s", diff)
+	}
+
+	todays, err := source.TodaysCounts()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedTodays := map[string]api.RelayCounts{
+		"app1": {Success: 10, Failure: 4},
+		"app2": {Success: 1, Failure: 1},
+	}
+	if diff := cmp.Diff(expectedTodays, todays); diff != "" {
+		t.Errorf("unexpected todays counts (-want +got):\n%s", diff)
+	}
+
+	intervals, err := source.TodaysIntervalCounts(10 * time.Minute)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedIntervals := map[time.Time]map[string]api.RelayCounts{
+		currentInterval: expectedTodays,
+	}
+	if diff := cmp.Diff(expectedIntervals, intervals); diff != "" {
+		t.Errorf("unexpected todays intervals (-want +got):\n%s", diff)
+	}
+}
+
+func TestIngestionSourceRejectsInvalidRecords(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name   string
+		record RelayRecord
+	}{
+		{
+			name:   "Empty application",
+			record: RelayRecord{Time: now, Success: 1},
+		},
+		{
+			name:   "Negative counts",
+			record: RelayRecord{Application: "app1", Time: now, Failure: -1},
+		},
+		{
+			name:   "Records older than the archive age",
+			record: RelayRecord{Application: "app1", Time: now.AddDate(0, 0, -40), Success: 1},
+		},
+		{
+			name:   "Records in the future",
+			record: RelayRecord{Application: "app1", Time: now.Add(time.Hour), Success: 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := NewIngestionSource(30*24*time.Hour, time.Hour, time.UTC)
+			valid := RelayRecord{Application: "app2", Time: now, Success: 1}
+
+			err := source.Ingest([]RelayRecord{valid, tc.record})
+			if !errors.Is(err, api.InvalidRequest) {
+				t.Fatalf("Expected error: %v, got: %v", api.InvalidRequest, err)
+			}
+
+			// A rejected batch is not partially ingested
+			todays, _ := source.TodaysCounts()
+			if len(todays) != 0 {
+				t.Errorf("Expected no ingested records, got: %v", todays)
+			}
+		})
+	}
+}
+
+func TestIngestionHandler(t *testing.T) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	authenticator, err := api.NewAPIKeyAuthenticator([]api.APIKey{{Key: "ingestion-key", Admin: true}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		name               string
+		method             string
+		apiKey             string
+		body               string
+		expectedStatusCode int
+		expectedErrorCode  string
+		expectedTodays     map[string]api.RelayCounts
+	}{
+		{
+			name:               "Relay records are ingested",
+			method:             http.MethodPost,
+			apiKey:             "ingestion-key",
+			body:               `[{"Application": "app1", "Time": "` + now + `", "Success": 3, "Failure": 1}]`,
+			expectedStatusCode: http.StatusOK,
+			expectedTodays:     map[string]api.RelayCounts{"app1": {Success: 3, Failure: 1}},
+		},
+		{
+			name:               "Missing API key is rejected",
+			method:             http.MethodPost,
+			body:               `[{"Application": "app1", "Time": "` + now + `", "Success": 3, "Failure": 1}]`,
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedErrorCode:  api.ERROR_CODE_UNAUTHENTICATED,
+			expectedTodays:     map[string]api.RelayCounts{},
+		},
+		{
+			name:               "Invalid API key is rejected",
+			method:             http.MethodPost,
+			apiKey:             "other-key",
+			body:               `[{"Application": "app1", "Time": "` + now + `", "Success": 3, "Failure": 1}]`,
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedErrorCode:  api.ERROR_CODE_UNAUTHENTICATED,
+			expectedTodays:     map[string]api.RelayCounts{},
+		},
+		{
+			name:               "Incorrect request method is rejected",
+			method:             http.MethodGet,
+			apiKey:             "ingestion-key",
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedErrorCode:  api.ERROR_CODE_METHOD_NOT_ALLOWED,
+			expectedTodays:     map[string]api.RelayCounts{},
+		},
+		{
+			name:               "Invalid body is rejected",
+			method:             http.MethodPost,
+			apiKey:             "ingestion-key",
+			body:               `{"Application": "app1"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedErrorCode:  api.ERROR_CODE_INVALID_REQUEST,
+			expectedTodays:     map[string]api.RelayCounts{},
+		},
+		{
+			name:               "Invalid records are rejected",
+			method:             http.MethodPost,
+			apiKey:             "ingestion-key",
+			body:               `[{"Time": "` + now + `", "Success": 3}]`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedErrorCode:  api.ERROR_CODE_INVALID_REQUEST,
+			expectedTodays:     map[string]api.RelayCounts{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := NewIngestionSource(30*24*time.Hour, time.Hour, time.UTC)
+			handler := GetIngestionHandler(source, authenticator, logger.New())
+
+			req := httptest.NewRequest(tc.method, "http://relay-meter.pokt.network/v0/relays", strings.NewReader(tc.body))
+			if tc.apiKey != "" {
+				req.Header.Set(api.HEADER_API_KEY, tc.apiKey)
+			}
+			w := httptest.NewRecorder()
+			handler(w, req)
+
+			if w.Result().StatusCode != tc.expectedStatusCode {
+				t.Errorf("Expected status code: %d, got: %d", tc.expectedStatusCode, w.Result().StatusCode)
+			}
+			if tc.expectedErrorCode != "" {
+				var errResp api.ErrorResponse
+				if err := json.NewDecoder(w.Result().Body).Decode(&errResp); err != nil {
+					t.Fatalf("Unexpected error unmarshalling the error response: %v", err)
+				}
+				if errResp.Code != tc.expectedErrorCode {
+					t.Errorf("Expected error code: %s, got: %s", tc.expectedErrorCode, errResp.Code)
+				}
+			}
+
+			todays, _ := source.TodaysCounts()
+			if diff := cmp.Diff(tc.expectedTodays, todays); diff != "" {
+				t.Errorf("unexpected todays counts (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/adshmh/meter/api"
+)
+
+const (
+	// Values of the outcome label of collectionRuns
+	OUTCOME_SUCCESS = "success"
+	OUTCOME_FAILURE = "failure"
+)
+
+var (
+	collectionRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: api.METRICS_NAMESPACE,
+		Name:      "collector_runs_total",
+		Help:      "Number of periodic collections, by outcome",
+	}, []string{"outcome"})
+	collectionDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: api.METRICS_NAMESPACE,
+		Name:      "collector_run_duration_seconds",
+		Help:      "Duration of the periodic collections",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12),
+	})
+	lastCollectedTimestamp = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: api.METRICS_NAMESPACE,
+		Name:      "collector_last_success_timestamp_seconds",
+		Help:      "Unix time of the last successful periodic collection",
+	})
+)
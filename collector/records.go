@@ -0,0 +1,158 @@
+package collector
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/adshmh/meter/api"
+)
+
+// RelayRecord holds the results of relays performed for an application, e.g. as reported by a relayer.
+//	Time is the time of the relays: relays are aggregated into the day and the interval that contain it.
+//	Failed relays are not broken down into categories, i.e. only the Failure count of api.RelayCounts is set.
+type RelayRecord struct {
+	Application string
+	Time        time.Time
+	Success     int64
+	Failure     int64
+	// Chain is the blockchain ID of the relays: optional
+	Chain string
+}
+
+// counts returns the relay counts of the record, with a breakdown by chain if the record specifies its chain.
+func (r RelayRecord) counts() api.RelayCounts {
+	counts := api.RelayCounts{Success: r.Success, Failure: r.Failure}
+	if r.Chain == "" {
+		return counts
+	}
+	return api.ChainRelayCounts(r.Chain, counts)
+}
+
+// validateRecord returns an error wrapping api.InvalidRequest if the record is not valid.
+func validateRecord(r RelayRecord) error {
+	if r.Application == "" {
+		return fmt.Errorf("%w: empty application public key", api.InvalidRequest)
+	}
+	if r.Time.IsZero() {
+		return fmt.Errorf("%w: missing time for application %s", api.InvalidRequest, r.Application)
+	}
+	if r.Success < 0 || r.Failure < 0 {
+		return fmt.Errorf("%w: negative relay counts for application %s", api.InvalidRequest, r.Application)
+	}
+	return nil
+}
+
+// relayAggregator aggregates relay records into daily counts, and into today's counts split into intervals.
+//	Days start at midnight in the aggregator's location, and intervals are aligned to the start of the day.
+//	It is not safe for concurrent use.
+type relayAggregator struct {
+	// Length of the intervals used for aggregating today's records
+	interval time.Duration
+	// Reporting timezone
+	location *time.Location
+
+	dailyCounts    map[time.Time]map[string]api.RelayCounts
+	intervalCounts map[time.Time]map[string]api.RelayCounts
+}
+
+func newRelayAggregator(interval time.Duration, loc *time.Location) *relayAggregator {
+	if interval == 0 {
+		interval = time.Duration(TODAYS_METRICS_INTERVAL_DEFAULT_MINUTES) * time.Minute
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	return &relayAggregator{
+		interval:       interval,
+		location:       loc,
+		dailyCounts:    make(map[time.Time]map[string]api.RelayCounts),
+		intervalCounts: make(map[time.Time]map[string]api.RelayCounts),
+	}
+}
+
+func (a *relayAggregator) add(records []RelayRecord) {
+	add := func(counts map[time.Time]map[string]api.RelayCounts, key time.Time, r RelayRecord) {
+		if counts[key] == nil {
+			counts[key] = make(map[string]api.RelayCounts)
+		}
+		counts[key][r.Application] = counts[key][r.Application].Add(r.counts())
+	}
+
+	today := a.dayOf(time.Now())
+	for _, r := range records {
+		add(a.dailyCounts, a.dayOf(r.Time), r)
+		if !r.Time.Before(today) {
+			add(a.intervalCounts, intervalOf(r.Time, today, a.interval), r)
+		}
+	}
+}
+
+// prune drops the daily counts before the oldest day, and the intervals before today.
+func (a *relayAggregator) prune(oldest time.Time) {
+	for day := range a.dailyCounts {
+		if day.Before(oldest) {
+			delete(a.dailyCounts, day)
+		}
+	}
+	today := a.dayOf(time.Now())
+	for start := range a.intervalCounts {
+		if start.Before(today) {
+			delete(a.intervalCounts, start)
+		}
+	}
+}
+
+// daily returns the relays per application, with an entry per day in the specified time period.
+func (a *relayAggregator) daily(from, to time.Time) map[time.Time]map[string]api.RelayCounts {
+	dailyCounts := make(map[time.Time]map[string]api.RelayCounts)
+	for current := from; current.Before(to); current = current.AddDate(0, 0, 1) {
+		counts := make(map[string]api.RelayCounts)
+		for app, c := range a.dailyCounts[a.dayOf(current)] {
+			counts[app] = c
+		}
+		dailyCounts[current] = counts
+	}
+	return dailyCounts
+}
+
+func (a *relayAggregator) todays() map[string]api.RelayCounts {
+	counts := make(map[string]api.RelayCounts)
+	for app, c := range a.dailyCounts[a.dayOf(time.Now())] {
+		counts[app] = c
+	}
+	return counts
+}
+
+// todaysIntervals returns today's relays, aggregated into intervals of the specified length.
+//	The interval should be a multiple of the aggregator's interval, otherwise relays are assigned to the interval containing the start of their aggregator's interval.
+func (a *relayAggregator) todaysIntervals(interval time.Duration) map[time.Time]map[string]api.RelayCounts {
+	today := a.dayOf(time.Now())
+	intervalCounts := make(map[time.Time]map[string]api.RelayCounts)
+	for start, appCounts := range a.intervalCounts {
+		if start.Before(today) {
+			continue
+		}
+		key := intervalOf(start, today, interval)
+		if intervalCounts[key] == nil {
+			intervalCounts[key] = make(map[string]api.RelayCounts)
+		}
+		for app, c := range appCounts {
+			total := intervalCounts[key][app]
+			total = total.Add(c)
+			intervalCounts[key][app] = total
+		}
+	}
+	return intervalCounts
+}
+
+// dayOf returns the start of the day, in the aggregator's location, that contains the input.
+func (a *relayAggregator) dayOf(t time.Time) time.Time {
+	y, m, d := t.In(a.location).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, a.location)
+}
+
+// intervalOf returns the start of the interval that contains the input, with intervals counted from the start of the day.
+//	The start of the day is not necessarily a multiple of the interval since the Unix epoch, e.g. in timezones with a 30 minutes offset.
+func intervalOf(t, dayStart time.Time, interval time.Duration) time.Time {
+	return dayStart.Add(t.Sub(dayStart).Truncate(interval))
+}
@@ -4,20 +4,35 @@ package db
 import (
 	"context"
 	"fmt"
-	"net/http"
 	"strings"
 	"time"
 
 	"github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api/query"
 
 	"github.com/adshmh/meter/api"
 )
 
+const (
+	// Tag holding the blockchain ID of relays
+	INFLUX_CHAIN_TAG = "blockchain"
+	// Field holding the latency, in seconds, of relays
+	INFLUX_ELAPSED_TIME_FIELD = "elapsedTime"
+	// Column added to latency query results, to identify the statistic of each record
+	INFLUX_LATENCY_STAT_COLUMN = "stat"
+)
+
 type Source interface {
 	AppRelays(from, to time.Time) (map[string]api.RelayCounts, error)
 	DailyCounts(from, to time.Time) (map[time.Time]map[string]api.RelayCounts, error)
 	// Returns application metrics for today so far
 	TodaysCounts() (map[string]api.RelayCounts, error)
+	// Returns application metrics for today so far, split into intervals of the specified length
+	TodaysIntervalCounts(interval time.Duration) (map[time.Time]map[string]api.RelayCounts, error)
+	// Returns latency statistics per application, one entry per day
+	DailyLatency(from, to time.Time) (map[time.Time]map[string]api.RelayLatency, error)
+	// Verifies the InfluxDB server is reachable
+	Ping(ctx context.Context) error
 }
 
 type InfluxDBOptions struct {
@@ -28,16 +43,41 @@ type InfluxDBOptions struct {
 	DailyBucket string
 	// Bucket to query for today's counts
 	CurrentBucket string
+	// Location is the reporting timezone: today starts at midnight in this timezone. Defaults to UTC.
+	Location *time.Location
 }
 
 func NewInfluxDBSource(options InfluxDBOptions) Source {
-	return &influxDB{Options: options}
+	return &instrumentedInfluxDB{Source: &influxDB{Options: options}}
 }
 
 type influxDB struct {
 	Options InfluxDBOptions
 }
 
+// todayStart returns the start of today in the reporting timezone
+func (i *influxDB) todayStart() time.Time {
+	loc := i.Options.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	return startOfDay(time.Now().In(loc))
+}
+
+func (i *influxDB) Ping(ctx context.Context) error {
+	client := influxdb2.NewClient(i.Options.URL, i.Options.Token)
+	defer client.Close()
+
+	ok, err := client.Ping(ctx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("InfluxDB server at %s is not ready", i.Options.URL)
+	}
+	return nil
+}
+
 // DailyCounts Returns total of number of daily relays per application, up to and including the specified day
 //	Each app will have an entry per day
 func (i *influxDB) DailyCounts(from, to time.Time) (map[time.Time]map[string]api.RelayCounts, error) {
@@ -59,8 +99,8 @@ func (i *influxDB) DailyCounts(from, to time.Time) (map[time.Time]map[string]api
 			fmt.Sprintf(" |> range(start: %s, stop: %s)", current.Format(time.RFC3339), current.AddDate(0, 0, 1).Format(time.RFC3339)) +
 			fmt.Sprintf(" |> filter(fn: (r) => r[%q] == %q)", "_measurement", "relay") +
 			fmt.Sprintf(" |> filter(fn: (r) => r[%q] == %q)", "_field", "count") +
-			fmt.Sprintf(" |> group(columns: [%q, %q])", "applicationPublicKey", "result") +
-			fmt.Sprintf(" |> keep(columns: [%q, %q, %q])", "applicationPublicKey", "result", "_value")
+			fmt.Sprintf(" |> group(columns: [%q, %q, %q])", "applicationPublicKey", "result", INFLUX_CHAIN_TAG) +
+			fmt.Sprintf(" |> keep(columns: [%q, %q, %q, %q])", "applicationPublicKey", "result", INFLUX_CHAIN_TAG, "_value")
 
 		result, err := queryAPI.Query(context.Background(), query)
 		if err != nil {
@@ -94,11 +134,7 @@ func (i *influxDB) DailyCounts(from, to time.Time) (map[time.Time]map[string]api
 			if !ok {
 				return nil, fmt.Errorf("Error parsing relay result: %v", result.Record().ValueByKey("result"))
 			}
-			updatedCount, err := updateRelayCount(counts[app], relayResult, count)
-			if err != nil {
-				return nil, err
-			}
-			counts[app] = updatedCount
+			counts[app] = updateRelayCount(counts[app], relayResult, chain(result.Record()), count)
 		}
 		// check for an error
 		if result.Err() != nil {
@@ -119,11 +155,11 @@ func (i *influxDB) TodaysCounts() (map[string]api.RelayCounts, error) {
 	counts := make(map[string]api.RelayCounts)
 	// TODO: send queries in parallel
 	query := fmt.Sprintf("from(bucket: %q)", i.Options.CurrentBucket) +
-		fmt.Sprintf(" |> range(start: %s)", startOfDay(time.Now()).Format(time.RFC3339)) +
+		fmt.Sprintf(" |> range(start: %s)", i.todayStart().Format(time.RFC3339)) +
 		fmt.Sprintf(" |> filter(fn: (r) => r[%q] == %q)", "_measurement", "relay") +
 		fmt.Sprintf(" |> filter(fn: (r) => r[%q] == %q)", "_field", "count") +
-		fmt.Sprintf(" |> keep(columns: [%q, %q, %q])", "applicationPublicKey", "result", "_value") +
-		fmt.Sprintf(" |> group(columns: [%q, %q])", "applicationPublicKey", "result") +
+		fmt.Sprintf(" |> keep(columns: [%q, %q, %q, %q])", "applicationPublicKey", "result", INFLUX_CHAIN_TAG, "_value") +
+		fmt.Sprintf(" |> group(columns: [%q, %q, %q])", "applicationPublicKey", "result", INFLUX_CHAIN_TAG) +
 		fmt.Sprintf(" |> sum()")
 
 	result, err := queryAPI.Query(context.Background(), query)
@@ -157,11 +193,7 @@ func (i *influxDB) TodaysCounts() (map[string]api.RelayCounts, error) {
 		if !ok {
 			return nil, fmt.Errorf("Error parsing relay result: %v", result.Record().ValueByKey("result"))
 		}
-		updatedCount, err := updateRelayCount(counts[app], relayResult, count)
-		if err != nil {
-			return nil, err
-		}
-		counts[app] = updatedCount
+		counts[app] = updateRelayCount(counts[app], relayResult, chain(result.Record()), count)
 	}
 	// check for an error
 	if result.Err() != nil {
@@ -172,16 +204,166 @@ func (i *influxDB) TodaysCounts() (map[string]api.RelayCounts, error) {
 	return counts, nil
 }
 
-func updateRelayCount(current api.RelayCounts, relayResult string, count int64) (api.RelayCounts, error) {
-	switch {
-	case relayResult == fmt.Sprintf("%d", http.StatusOK):
-		current.Success += int64(count)
-	case relayResult == fmt.Sprintf("%d", http.StatusInternalServerError):
-		current.Failure += int64(count)
-	default:
-		return api.RelayCounts{}, fmt.Errorf("Invalid value in result field: %s", relayResult)
+// TodaysIntervalCounts returns the relays per application for today so far, grouped into intervals of the specified length.
+//	Each interval is keyed by its start time. Intervals with no relays are not included.
+func (i *influxDB) TodaysIntervalCounts(interval time.Duration) (map[time.Time]map[string]api.RelayCounts, error) {
+	if interval < time.Second {
+		return nil, fmt.Errorf("Invalid interval: %v, needs to be at least 1 second", interval)
+	}
+
+	client := influxdb2.NewClient(i.Options.URL, i.Options.Token)
+	queryAPI := client.QueryAPI(i.Options.Org)
+
+	intervalCounts := make(map[time.Time]map[string]api.RelayCounts)
+	// Windows are aligned to the start of today, rather than to the Unix epoch, as the start of the day may not be a multiple of the interval
+	todayStart := i.todayStart()
+	offset := todayStart.Unix() % int64(interval.Seconds())
+	query := fmt.Sprintf("from(bucket: %q)", i.Options.CurrentBucket) +
+		fmt.Sprintf(" |> range(start: %s)", todayStart.Format(time.RFC3339)) +
+		fmt.Sprintf(" |> filter(fn: (r) => r[%q] == %q)", "_measurement", "relay") +
+		fmt.Sprintf(" |> filter(fn: (r) => r[%q] == %q)", "_field", "count") +
+		fmt.Sprintf(" |> keep(columns: [%q, %q, %q, %q, %q])", "applicationPublicKey", "result", INFLUX_CHAIN_TAG, "_value", "_time") +
+		fmt.Sprintf(" |> group(columns: [%q, %q, %q])", "applicationPublicKey", "result", INFLUX_CHAIN_TAG) +
+		fmt.Sprintf(" |> aggregateWindow(every: %ds, offset: %ds, fn: sum, timeSrc: %q, createEmpty: false)", int64(interval.Seconds()), offset, "_start")
+
+	result, err := queryAPI.Query(context.Background(), query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Iterate over query response
+	for result.Next() {
+		app, ok := result.Record().ValueByKey("applicationPublicKey").(string)
+		if !ok {
+			return nil, fmt.Errorf("Error parsing application public key: %v", result.Record().ValueByKey("applicationPublicKey"))
+		}
+		// TODO: log a warning on empty app key
+		if app == "" {
+			fmt.Println("Warning: empty application public key")
+			continue
+		}
+
+		// Remove leading and trailing '"' from app
+		app = strings.TrimPrefix(app, "\"")
+		app = strings.TrimSuffix(app, "\"")
+
+		count, ok := result.Record().Value().(int64)
+		if !ok {
+			return nil, fmt.Errorf("Error parsing application %s relay counts %v", app, result.Record().Value())
+		}
+
+		relayResult, ok := result.Record().ValueByKey("result").(string)
+		if !ok {
+			return nil, fmt.Errorf("Error parsing relay result: %v", result.Record().ValueByKey("result"))
+		}
+
+		intervalStart := result.Record().Time()
+		if intervalCounts[intervalStart] == nil {
+			intervalCounts[intervalStart] = make(map[string]api.RelayCounts)
+		}
+		intervalCounts[intervalStart][app] = updateRelayCount(intervalCounts[intervalStart][app], relayResult, chain(result.Record()), count)
+	}
+	// check for an error
+	if result.Err() != nil {
+		return nil, fmt.Errorf("query parsing error: %s", result.Err().Error())
+	}
+
+	client.Close()
+	return intervalCounts, nil
+}
+
+// DailyLatency returns the latency statistics of each application's relays, i.e. the average and percentiles of their elapsed time, up to and including the specified day
+//	Each app will have an entry per day. Percentiles are estimated by influx.
+//	The elapsed times are read from the daily bucket, which holds them downsampled: the statistics are calculated on the aggregated values,
+//	not on individual relays, so the percentiles approximate those of the relays. No bucket holds the raw relays for past days.
+func (i *influxDB) DailyLatency(from, to time.Time) (map[time.Time]map[string]api.RelayLatency, error) {
+	client := influxdb2.NewClient(i.Options.URL, i.Options.Token)
+	queryAPI := client.QueryAPI(i.Options.Org)
+
+	dailyLatency := make(map[time.Time]map[string]api.RelayLatency)
+	// TODO: send queries in parallel
+	for current := from; current.Before(to); current = current.AddDate(0, 0, 1) {
+		// All the statistics are calculated in a single query: each record is marked with the statistic it holds
+		query := fmt.Sprintf("data = from(bucket: %q)", i.Options.DailyBucket) +
+			fmt.Sprintf(" |> range(start: %s, stop: %s)", current.Format(time.RFC3339), current.AddDate(0, 0, 1).Format(time.RFC3339)) +
+			fmt.Sprintf(" |> filter(fn: (r) => r[%q] == %q)", "_measurement", "relay") +
+			fmt.Sprintf(" |> filter(fn: (r) => r[%q] == %q)", "_field", INFLUX_ELAPSED_TIME_FIELD) +
+			fmt.Sprintf(" |> group(columns: [%q])", "applicationPublicKey") +
+			fmt.Sprintf(" |> keep(columns: [%q, %q])", "applicationPublicKey", "_value") +
+			" union(tables: [" +
+			fmt.Sprintf("data |> count() |> toFloat() |> set(key: %q, value: %q),", INFLUX_LATENCY_STAT_COLUMN, "count") +
+			fmt.Sprintf("data |> mean() |> set(key: %q, value: %q),", INFLUX_LATENCY_STAT_COLUMN, "mean") +
+			fmt.Sprintf("data |> quantile(q: 0.5) |> set(key: %q, value: %q),", INFLUX_LATENCY_STAT_COLUMN, "p50") +
+			fmt.Sprintf("data |> quantile(q: 0.95) |> set(key: %q, value: %q),", INFLUX_LATENCY_STAT_COLUMN, "p95") +
+			fmt.Sprintf("data |> quantile(q: 0.99) |> set(key: %q, value: %q)", INFLUX_LATENCY_STAT_COLUMN, "p99") +
+			"])"
+
+		result, err := queryAPI.Query(context.Background(), query)
+		if err != nil {
+			return nil, err
+		}
+
+		latency := make(map[string]api.RelayLatency)
+		// Iterate over query response
+		for result.Next() {
+			app, ok := result.Record().ValueByKey("applicationPublicKey").(string)
+			if !ok {
+				return nil, fmt.Errorf("Error parsing application public key: %v", result.Record().ValueByKey("applicationPublicKey"))
+			}
+			// TODO: log a warning on empty app key
+			if app == "" {
+				fmt.Println("Warning: empty application public key")
+				continue
+			}
+
+			// Remove leading and trailing '"' from app
+			app = strings.TrimPrefix(app, "\"")
+			app = strings.TrimSuffix(app, "\"")
+
+			value, ok := result.Record().Value().(float64)
+			if !ok {
+				return nil, fmt.Errorf("Error parsing application %s latency %v", app, result.Record().Value())
+			}
+
+			stat, _ := result.Record().ValueByKey(INFLUX_LATENCY_STAT_COLUMN).(string)
+			l := latency[app]
+			switch stat {
+			case "count":
+				l.Relays = int64(value)
+			case "mean":
+				l.Average = value
+			case "p50":
+				l.P50 = value
+			case "p95":
+				l.P95 = value
+			case "p99":
+				l.P99 = value
+			default:
+				return nil, fmt.Errorf("Unexpected latency statistic: %v for application %s", result.Record().ValueByKey(INFLUX_LATENCY_STAT_COLUMN), app)
+			}
+			latency[app] = l
+		}
+		// check for an error
+		if result.Err() != nil {
+			return nil, fmt.Errorf("query parsing error: %s", result.Err().Error())
+		}
+		dailyLatency[current] = latency
 	}
-	return current, nil
+
+	client.Close()
+	return dailyLatency, nil
+}
+
+// updateRelayCount adds the relays with the specified result and chain to the current counts.
+//	Unknown results are counted too, as api.RelayCounts.Other, so an unexpected status code does not break the collection.
+func updateRelayCount(current api.RelayCounts, relayResult string, chain string, count int64) api.RelayCounts {
+	return current.Add(api.ChainRelayCounts(chain, api.ResultRelayCounts(relayResult, count)))
+}
+
+// chain returns the blockchain ID of the record: the empty chain ID is returned if the record has no chain information, e.g. older relays.
+func chain(record *query.FluxRecord) string {
+	chain, _ := record.ValueByKey(INFLUX_CHAIN_TAG).(string)
+	return chain
 }
 
 // TODO: Remove this and all references.
@@ -0,0 +1,165 @@
+package db
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/adshmh/meter/api"
+)
+
+const (
+	DATABASE_POSTGRES = "postgres"
+	DATABASE_INFLUXDB = "influxdb"
+)
+
+var (
+	queryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: api.METRICS_NAMESPACE,
+		Name:      "db_query_duration_seconds",
+		Help:      "Duration of the database queries, by database and query",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 14),
+	}, []string{"database", "query"})
+	queryErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: api.METRICS_NAMESPACE,
+		Name:      "db_query_errors_total",
+		Help:      "Number of failed database queries, by database and query",
+	}, []string{"database", "query"})
+	rowsWritten = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: api.METRICS_NAMESPACE,
+		Name:      "db_rows_written_total",
+		Help:      "Number of rows written to Postgres, by table",
+	}, []string{"table"})
+)
+
+// observeQuery records the duration and outcome of a query: meant to be deferred at the start of the query.
+func observeQuery(database, query string, start time.Time, err *error) {
+	queryDuration.WithLabelValues(database, query).Observe(time.Since(start).Seconds())
+	if *err != nil {
+		queryErrors.WithLabelValues(database, query).Inc()
+	}
+}
+
+// instrumentedPostgresClient records metrics on the queries of the underlying client
+type instrumentedPostgresClient struct {
+	PostgresClient
+}
+
+func (p *instrumentedPostgresClient) DailyUsage(from, to time.Time) (counts map[time.Time]map[string]api.RelayCounts, err error) {
+	defer observeQuery(DATABASE_POSTGRES, "DailyUsage", time.Now(), &err)
+	return p.PostgresClient.DailyUsage(from, to)
+}
+
+func (p *instrumentedPostgresClient) TodaysUsage() (counts map[string]api.RelayCounts, err error) {
+	defer observeQuery(DATABASE_POSTGRES, "TodaysUsage", time.Now(), &err)
+	return p.PostgresClient.TodaysUsage()
+}
+
+func (p *instrumentedPostgresClient) TodaysIntervalUsage() (counts map[time.Time]map[string]api.RelayCounts, err error) {
+	defer observeQuery(DATABASE_POSTGRES, "TodaysIntervalUsage", time.Now(), &err)
+	return p.PostgresClient.TodaysIntervalUsage()
+}
+
+func (p *instrumentedPostgresClient) DailyLatency(from, to time.Time) (latency map[time.Time]map[string]api.RelayLatency, err error) {
+	defer observeQuery(DATABASE_POSTGRES, "DailyLatency", time.Now(), &err)
+	return p.PostgresClient.DailyLatency(from, to)
+}
+
+func (p *instrumentedPostgresClient) AppQuota(app string) (quota *api.Quota, err error) {
+	defer observeQuery(DATABASE_POSTGRES, "AppQuota", time.Now(), &err)
+	return p.PostgresClient.AppQuota(app)
+}
+
+func (p *instrumentedPostgresClient) UserQuota(user string) (quota *api.Quota, err error) {
+	defer observeQuery(DATABASE_POSTGRES, "UserQuota", time.Now(), &err)
+	return p.PostgresClient.UserQuota(user)
+}
+
+func (p *instrumentedPostgresClient) ExistingMetricsTimespan() (first time.Time, last time.Time, err error) {
+	defer observeQuery(DATABASE_POSTGRES, "ExistingMetricsTimespan", time.Now(), &err)
+	return p.PostgresClient.ExistingMetricsTimespan()
+}
+
+func (p *instrumentedPostgresClient) WriteDailyUsage(counts map[time.Time]map[string]api.RelayCounts) (err error) {
+	defer observeQuery(DATABASE_POSTGRES, "WriteDailyUsage", time.Now(), &err)
+	if err = p.PostgresClient.WriteDailyUsage(counts); err == nil {
+		var rows int
+		for _, appCounts := range counts {
+			rows += countRows(appCounts)
+		}
+		rowsWritten.WithLabelValues(TABLE_DAILY_SUMS).Add(float64(rows))
+	}
+	return err
+}
+
+func (p *instrumentedPostgresClient) WriteTodaysUsage(counts map[string]api.RelayCounts) (err error) {
+	defer observeQuery(DATABASE_POSTGRES, "WriteTodaysUsage", time.Now(), &err)
+	if err = p.PostgresClient.WriteTodaysUsage(counts); err == nil {
+		rowsWritten.WithLabelValues(TABLE_TODAYS_SUMS).Add(float64(countRows(counts)))
+	}
+	return err
+}
+
+func (p *instrumentedPostgresClient) WriteTodaysIntervalUsage(counts map[time.Time]map[string]api.RelayCounts) (err error) {
+	defer observeQuery(DATABASE_POSTGRES, "WriteTodaysIntervalUsage", time.Now(), &err)
+	if err = p.PostgresClient.WriteTodaysIntervalUsage(counts); err == nil {
+		var rows int
+		for _, appCounts := range counts {
+			rows += countRows(appCounts)
+		}
+		rowsWritten.WithLabelValues(TABLE_TODAYS_INTERVALS).Add(float64(rows))
+	}
+	return err
+}
+
+func (p *instrumentedPostgresClient) WriteDailyLatency(latency map[time.Time]map[string]api.RelayLatency) (err error) {
+	defer observeQuery(DATABASE_POSTGRES, "WriteDailyLatency", time.Now(), &err)
+	if err = p.PostgresClient.WriteDailyLatency(latency); err == nil {
+		var rows int
+		for _, appLatency := range latency {
+			rows += len(appLatency)
+		}
+		rowsWritten.WithLabelValues(TABLE_DAILY_LATENCY).Add(float64(rows))
+	}
+	return err
+}
+
+// countRows returns the number of rows the counts are stored in: see chainRows
+func countRows(counts map[string]api.RelayCounts) int {
+	var rows int
+	for _, c := range counts {
+		rows += len(chainRows(c))
+	}
+	return rows
+}
+
+// instrumentedInfluxDB records metrics on the queries of the underlying source
+type instrumentedInfluxDB struct {
+	Source
+}
+
+func (i *instrumentedInfluxDB) AppRelays(from, to time.Time) (counts map[string]api.RelayCounts, err error) {
+	defer observeQuery(DATABASE_INFLUXDB, "AppRelays", time.Now(), &err)
+	return i.Source.AppRelays(from, to)
+}
+
+func (i *instrumentedInfluxDB) DailyCounts(from, to time.Time) (counts map[time.Time]map[string]api.RelayCounts, err error) {
+	defer observeQuery(DATABASE_INFLUXDB, "DailyCounts", time.Now(), &err)
+	return i.Source.DailyCounts(from, to)
+}
+
+func (i *instrumentedInfluxDB) TodaysCounts() (counts map[string]api.RelayCounts, err error) {
+	defer observeQuery(DATABASE_INFLUXDB, "TodaysCounts", time.Now(), &err)
+	return i.Source.TodaysCounts()
+}
+
+func (i *instrumentedInfluxDB) TodaysIntervalCounts(interval time.Duration) (counts map[time.Time]map[string]api.RelayCounts, err error) {
+	defer observeQuery(DATABASE_INFLUXDB, "TodaysIntervalCounts", time.Now(), &err)
+	return i.Source.TodaysIntervalCounts(interval)
+}
+
+func (i *instrumentedInfluxDB) DailyLatency(from, to time.Time) (latency map[time.Time]map[string]api.RelayLatency, err error) {
+	defer observeQuery(DATABASE_INFLUXDB, "DailyLatency", time.Now(), &err)
+	return i.Source.DailyLatency(from, to)
+}
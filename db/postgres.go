@@ -4,6 +4,7 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -17,8 +18,16 @@ import (
 
 // TODO: db package needs some form of unit testing
 const (
-	DAY_LAYOUT       = "2006-01-02"
-	TABLE_DAILY_SUMS = "daily_app_sums"
+	TABLE_DAILY_SUMS       = "daily_app_sums"
+	TABLE_TODAYS_SUMS      = "todays_app_sums"
+	TABLE_TODAYS_INTERVALS = "todays_app_intervals"
+	TABLE_DAILY_LATENCY    = "daily_app_latency"
+	TABLE_APP_QUOTAS       = "app_quotas"
+	TABLE_USER_QUOTAS      = "user_quotas"
+
+	// COUNT_COLUMNS lists the relay count columns, in the order expected by parseRelayCounts and returned by relayCountValues
+	COUNT_COLUMNS      = "count_success, count_failure, count_client_error, count_server_error, count_timeout, count_other"
+	COUNT_COLUMNS_SIZE = 6
 )
 
 // Will be implemented by Postgres DB interface
@@ -27,6 +36,14 @@ type Reporter interface {
 	DailyUsage(from, to time.Time) (map[time.Time]map[string]api.RelayCounts, error)
 	// TodaysUsage returns the metrics for today so far
 	TodaysUsage() (map[string]api.RelayCounts, error)
+	// TodaysIntervalUsage returns the metrics for today so far, with each interval, e.g. hour, being an entry in the results map
+	TodaysIntervalUsage() (map[time.Time]map[string]api.RelayCounts, error)
+	// DailyLatency returns saved daily latency statistics for the specified time period, with each day being an entry in the results map
+	DailyLatency(from, to time.Time) (map[time.Time]map[string]api.RelayLatency, error)
+	// AppQuota returns the daily and monthly relay limits of the application, or nil if none are set
+	AppQuota(app string) (*api.Quota, error)
+	// UserQuota returns the daily and monthly relay limits of the user, or nil if none are set
+	UserQuota(user string) (*api.Quota, error)
 }
 
 // Will be implemented by Postgres DB interface
@@ -35,6 +52,10 @@ type Writer interface {
 	WriteDailyUsage(counts map[time.Time]map[string]api.RelayCounts) error
 	// WriteTodaysUsage writes todays relay counts to the underlying storage.
 	WriteTodaysUsage(counts map[string]api.RelayCounts) error
+	// WriteTodaysIntervalUsage writes todays relay counts, split into intervals, to the underlying storage.
+	WriteTodaysIntervalUsage(counts map[time.Time]map[string]api.RelayCounts) error
+	// WriteDailyLatency writes daily latency statistics, replacing any existing statistics for the same application and day.
+	WriteDailyLatency(latency map[time.Time]map[string]api.RelayLatency) error
 	// Returns oldest and most recent timestamps for stored metrics
 	ExistingMetricsTimespan() (time.Time, time.Time, error)
 }
@@ -49,16 +70,20 @@ type PostgresOptions struct {
 type PostgresClient interface {
 	Reporter
 	Writer
+	// PingContext verifies the database is reachable: implemented by the underlying sql.DB
+	PingContext(ctx context.Context) error
 }
 
 func NewPostgresClient(options PostgresOptions) (PostgresClient, error) {
 	// TODO: add '?sslmode=verify-full' to connection string?
-	connStr := fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=disable", options.User, options.Password, options.Host, options.DB)
+	// The session timezone is set to UTC: query results are parsed as UTC timestamps, e.g. by DailyUsage,
+	//	regardless of the server's default timezone or the reporting timezone.
+	connStr := fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=disable&timezone=UTC", options.User, options.Password, options.Host, options.DB)
 	db, err := sql.Open("postgres", connStr)
 	if err != nil {
 		return nil, err
 	}
-	return &pgClient{DB: db}, nil
+	return &instrumentedPostgresClient{PostgresClient: &pgClient{DB: db}}, nil
 }
 
 // type pgReporter
@@ -69,9 +94,11 @@ type pgClient struct {
 func (p *pgClient) DailyUsage(from, to time.Time) (map[time.Time]map[string]api.RelayCounts, error) {
 	ctx := context.Background()
 	// TODO: delegate dealing with the timestamps to the sql query: looks like there is a bug in QueryContext in dealing with parameters
-	q := fmt.Sprintf("SELECT (time, application, count_success, count_failure) FROM daily_app_sums as d WHERE d.time >= '%s' and d.time <= '%s'",
-		from.Format(DAY_LAYOUT),
-		to.Format(DAY_LAYOUT),
+	// Days start at midnight in the reporting timezone: the timestamps include their offset so they are not read in the session's timezone
+	q := fmt.Sprintf("SELECT (time, application, chain, %s) FROM daily_app_sums as d WHERE d.time >= '%s' and d.time <= '%s'",
+		COUNT_COLUMNS,
+		from.Format(time.RFC3339),
+		to.Format(time.RFC3339),
 	)
 	rows, err := p.DB.QueryContext(ctx, q)
 	if err != nil {
@@ -88,12 +115,12 @@ func (p *pgClient) DailyUsage(from, to time.Time) (map[time.Time]map[string]api.
 		}
 
 		// Example of query output (app public key has been modified)
-		// ("2022-06-25 00:00:00+00",33d4474f0a60b362103b1867c7edac323e39f416e7458f436623b9d96eb31k19,18931)
+		// ("2022-06-25 00:00:00+00",33d4474f0a60b362103b1867c7edac323e39f416e7458f436623b9d96eb31k19,0021,18931,12,2,8,1,1)
 		r = strings.ReplaceAll(r, "\"", "")
 		r = strings.TrimPrefix(r, "(")
 		r = strings.TrimSuffix(r, ")")
 		items := strings.Split(r, ",")
-		if len(items) != 4 {
+		if len(items) != 3+COUNT_COLUMNS_SIZE {
 			return nil, fmt.Errorf("Invalid format in query output: %s", r)
 		}
 
@@ -101,13 +128,9 @@ func (p *pgClient) DailyUsage(from, to time.Time) (map[time.Time]map[string]api.
 		if err != nil {
 			return nil, fmt.Errorf("Invalid time format: %s in query result line: %s, error: %v", items[0], r, err)
 		}
-		count_success, err := strconv.ParseInt(items[2], 10, 64) // bitsize 64 for int64 return
+		counts, err := parseRelayCounts(items[3:], r)
 		if err != nil {
-			return nil, fmt.Errorf("Invalid total relays format: %s in query result line: %s, error: %v", items[2], r, err)
-		}
-		count_failure, err := strconv.ParseInt(items[3], 10, 64) // bitsize 64 for int64 return
-		if err != nil {
-			return nil, fmt.Errorf("Invalid total relays format: %s in query result line: %s, error: %v", items[3], r, err)
+			return nil, err
 		}
 		app := items[1]
 		if app == "" {
@@ -117,7 +140,8 @@ func (p *pgClient) DailyUsage(from, to time.Time) (map[time.Time]map[string]api.
 		if dailyUsage[ts] == nil {
 			dailyUsage[ts] = make(map[string]api.RelayCounts)
 		}
-		dailyUsage[ts][app] = api.RelayCounts{Success: count_success, Failure: count_failure}
+		// Each chain is stored in its own row
+		dailyUsage[ts][app] = dailyUsage[ts][app].Add(api.ChainRelayCounts(items[2], counts))
 	}
 	// TODO: verify this is needed
 	if rerr := rows.Close(); rerr != nil {
@@ -142,15 +166,16 @@ func (p *pgClient) WriteDailyUsage(counts map[time.Time]map[string]api.RelayCoun
 	// TODO: bulk insert
 	for day, appCounts := range counts {
 		for app, counts := range appCounts {
-			_, execErr := tx.ExecContext(ctx,
-				"INSERT INTO daily_app_sums(application, count_success, count_failure, time) VALUES($1, $2, $3, $4);",
-				app, counts.Success, counts.Failure, day)
-			if execErr != nil {
-				if rollbackErr := tx.Rollback(); rollbackErr != nil {
-					fmt.Printf("update failed: %v, unable to rollback: %v\n", execErr, rollbackErr)
+			for chain, chainCounts := range chainRows(counts) {
+				_, execErr := tx.ExecContext(ctx,
+					fmt.Sprintf("INSERT INTO daily_app_sums(application, time, chain, %s) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9);", COUNT_COLUMNS),
+					append([]any{app, day, chain}, relayCountValues(chainCounts)...)...)
+				if execErr != nil {
+					if rollbackErr := tx.Rollback(); rollbackErr != nil {
+						fmt.Printf("update failed: %v, unable to rollback: %v\n", execErr, rollbackErr)
+					}
 					return execErr
 				}
-				fmt.Printf("update failed: %v", execErr)
 			}
 		}
 	}
@@ -161,6 +186,70 @@ func (p *pgClient) WriteDailyUsage(counts map[time.Time]map[string]api.RelayCoun
 	return nil
 }
 
+func (p *pgClient) DailyLatency(from, to time.Time) (map[time.Time]map[string]api.RelayLatency, error) {
+	ctx := context.Background()
+	q := fmt.Sprintf("SELECT time, application, relays, average, p50, p95, p99 FROM %s as d WHERE d.time >= '%s' and d.time <= '%s'",
+		TABLE_DAILY_LATENCY,
+		from.Format(time.RFC3339),
+		to.Format(time.RFC3339),
+	)
+	rows, err := p.DB.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dailyLatency := make(map[time.Time]map[string]api.RelayLatency)
+	for rows.Next() {
+		var day time.Time
+		var app string
+		var latency api.RelayLatency
+		if err := rows.Scan(&day, &app, &latency.Relays, &latency.Average, &latency.P50, &latency.P95, &latency.P99); err != nil {
+			return nil, err
+		}
+		if app == "" {
+			return nil, fmt.Errorf("Empty application public key in daily latency, day: %v", day)
+		}
+
+		// Keys need to match those of the daily usage, which are in UTC
+		day = day.UTC()
+		if dailyLatency[day] == nil {
+			dailyLatency[day] = make(map[string]api.RelayLatency)
+		}
+		dailyLatency[day][app] = latency
+	}
+	// Rows.Err will report the last error encountered by Rows.Scan.
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return dailyLatency, nil
+}
+
+func (p *pgClient) WriteDailyLatency(latency map[time.Time]map[string]api.RelayLatency) error {
+	ctx := context.Background()
+	tx, err := p.DB.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return err
+	}
+
+	// Existing statistics are overwritten, e.g. when the collection of a day is repeated
+	q := fmt.Sprintf(`INSERT INTO %s(application, time, relays, average, p50, p95, p99) VALUES($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (application, time) DO UPDATE SET relays = $3, average = $4, p50 = $5, p95 = $6, p99 = $7;`, TABLE_DAILY_LATENCY)
+	for day, appLatency := range latency {
+		for app, l := range appLatency {
+			if _, err := tx.ExecContext(ctx, q, app, day, l.Relays, l.Average, l.P50, l.P95, l.P99); err != nil {
+				if rollbackErr := tx.Rollback(); rollbackErr != nil {
+					fmt.Printf("update failed: %v, unable to rollback: %v\n", err, rollbackErr)
+				}
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
 func (p *pgClient) ExistingMetricsTimespan() (time.Time, time.Time, error) {
 	ctx := context.Background()
 	row := p.DB.QueryRowContext(ctx, fmt.Sprintf("SELECT count(*), COALESCE(min(time), '2003-01-02 03:04' ), COALESCE(max(time), '2003-01-02 03:04') FROM %s", TABLE_DAILY_SUMS))
@@ -192,25 +281,26 @@ func (p *pgClient) WriteTodaysUsage(counts map[string]api.RelayCounts) error {
 	}
 
 	// todays_sums table gets rebuilt every time
-	_, deleteErr := tx.ExecContext(ctx, "DELETE FROM todays_app_sums")
+	_, deleteErr := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", TABLE_TODAYS_SUMS))
 	if deleteErr != nil {
 		if rollbackErr := tx.Rollback(); rollbackErr != nil {
 			fmt.Printf("delete failed: %v, unable to rollback: %v\n", deleteErr, rollbackErr)
-			return deleteErr
 		}
+		return deleteErr
 	}
 
 	// TODO: bulk insert
 	for app, count := range counts {
-		_, execErr := tx.ExecContext(ctx,
-			"INSERT INTO todays_app_sums(application, count_success, count_failure) VALUES($1, $2, $3);",
-			app, count.Success, count.Failure)
-		if execErr != nil {
-			if rollbackErr := tx.Rollback(); rollbackErr != nil {
-				fmt.Printf("update failed: %v, unable to rollback: %v\n", execErr, rollbackErr)
+		for chain, chainCount := range chainRows(count) {
+			_, execErr := tx.ExecContext(ctx,
+				fmt.Sprintf("INSERT INTO %s(application, chain, %s) VALUES($1, $2, $3, $4, $5, $6, $7, $8);", TABLE_TODAYS_SUMS, COUNT_COLUMNS),
+				append([]any{app, chain}, relayCountValues(chainCount)...)...)
+			if execErr != nil {
+				if rollbackErr := tx.Rollback(); rollbackErr != nil {
+					fmt.Printf("update failed: %v, unable to rollback: %v\n", execErr, rollbackErr)
+				}
 				return execErr
 			}
-			fmt.Printf("update failed: %v", execErr)
 		}
 	}
 
@@ -224,7 +314,7 @@ func (p *pgClient) WriteTodaysUsage(counts map[string]api.RelayCounts) error {
 func (pg *pgClient) TodaysUsage() (map[string]api.RelayCounts, error) {
 	// TODO: factor-out the SQL statements
 	ctx := context.Background()
-	rows, err := pg.DB.QueryContext(ctx, "SELECT (application, count_success, count_failure) FROM todays_app_sums")
+	rows, err := pg.DB.QueryContext(ctx, fmt.Sprintf("SELECT (application, chain, %s) FROM %s", COUNT_COLUMNS, TABLE_TODAYS_SUMS))
 	if err != nil {
 		return nil, err
 	}
@@ -238,29 +328,25 @@ func (pg *pgClient) TodaysUsage() (map[string]api.RelayCounts, error) {
 		}
 
 		// Example of query output:
-		// (46d4474f0a60f062103b1867c7edac323e58f416e7458f436623b9d96eb44b37,18931)
+		// (46d4474f0a60f062103b1867c7edac323e58f416e7458f436623b9d96eb44b37,0021,18931,12,2,8,1,1)
 		r = strings.ReplaceAll(r, "\"", "")
 		r = strings.TrimPrefix(r, "(")
 		r = strings.TrimSuffix(r, ")")
 		items := strings.Split(r, ",")
-		if len(items) != 3 {
+		if len(items) != 2+COUNT_COLUMNS_SIZE {
 			return nil, fmt.Errorf("Invalid format in query output: %s", r)
 		}
 
-		count_success, err := strconv.ParseInt(items[1], 10, 64) // bitsize 64 for int64 return
+		counts, err := parseRelayCounts(items[2:], r)
 		if err != nil {
-			return nil, fmt.Errorf("Invalid total relays format: %s in query result line: %s, error: %v", items[1], r, err)
-		}
-		count_failure, err := strconv.ParseInt(items[2], 10, 64) // bitsize 64 for int64 return
-		if err != nil {
-			return nil, fmt.Errorf("Invalid total relays format: %s in query result line: %s, error: %v", items[2], r, err)
+			return nil, err
 		}
 		app := items[0]
 		if app == "" {
 			return nil, fmt.Errorf("Empty application public key, in query result line: %s", r)
 		}
 
-		todaysUsage[app] = api.RelayCounts{Success: count_success, Failure: count_failure}
+		todaysUsage[app] = todaysUsage[app].Add(api.ChainRelayCounts(items[1], counts))
 	}
 	// TODO: verify this is needed
 	if rerr := rows.Close(); rerr != nil {
@@ -274,8 +360,161 @@ func (pg *pgClient) TodaysUsage() (map[string]api.RelayCounts, error) {
 	return todaysUsage, nil
 }
 
+// WriteTodaysIntervalUsage writes the app metrics for today so far, split into intervals, to the underlying PG table.
+//	All the entries in the table holding todays intervals are deleted first.
+func (p *pgClient) WriteTodaysIntervalUsage(counts map[time.Time]map[string]api.RelayCounts) error {
+	ctx := context.Background()
+	// TODO: determine required isolation level
+	tx, err := p.DB.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return err
+	}
+
+	// todays intervals table gets rebuilt every time: the source returns all of today's intervals on each collection
+	_, deleteErr := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", TABLE_TODAYS_INTERVALS))
+	if deleteErr != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			fmt.Printf("delete failed: %v, unable to rollback: %v\n", deleteErr, rollbackErr)
+		}
+		return deleteErr
+	}
+
+	// TODO: bulk insert
+	for interval, appCounts := range counts {
+		for app, count := range appCounts {
+			for chain, chainCount := range chainRows(count) {
+				_, execErr := tx.ExecContext(ctx,
+					fmt.Sprintf("INSERT INTO %s(application, time, chain, %s) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9);", TABLE_TODAYS_INTERVALS, COUNT_COLUMNS),
+					append([]any{app, interval, chain}, relayCountValues(chainCount)...)...)
+				if execErr != nil {
+					if rollbackErr := tx.Rollback(); rollbackErr != nil {
+						fmt.Printf("update failed: %v, unable to rollback: %v\n", execErr, rollbackErr)
+					}
+					return execErr
+				}
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// TodaysIntervalUsage returns the current day's metrics so far, with an entry per interval.
+func (p *pgClient) TodaysIntervalUsage() (map[time.Time]map[string]api.RelayCounts, error) {
+	ctx := context.Background()
+	rows, err := p.DB.QueryContext(ctx, fmt.Sprintf("SELECT (time, application, chain, %s) FROM %s", COUNT_COLUMNS, TABLE_TODAYS_INTERVALS))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	const timeFormat = "2006-01-02 15:04:00+00"
+	intervalUsage := make(map[time.Time]map[string]api.RelayCounts)
+	for rows.Next() {
+		var r string
+		if err := rows.Scan(&r); err != nil {
+			return nil, err
+		}
+
+		// Example of query output (app public key has been modified)
+		// ("2022-06-25 13:00:00+00",33d4474f0a60b362103b1867c7edac323e39f416e7458f436623b9d96eb31k19,0021,18931,12,2,8,1,1)
+		r = strings.ReplaceAll(r, "\"", "")
+		r = strings.TrimPrefix(r, "(")
+		r = strings.TrimSuffix(r, ")")
+		items := strings.Split(r, ",")
+		if len(items) != 3+COUNT_COLUMNS_SIZE {
+			return nil, fmt.Errorf("Invalid format in query output: %s", r)
+		}
+
+		ts, err := time.Parse(timeFormat, items[0])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid time format: %s in query result line: %s, error: %v", items[0], r, err)
+		}
+		counts, err := parseRelayCounts(items[3:], r)
+		if err != nil {
+			return nil, err
+		}
+		app := items[1]
+		if app == "" {
+			return nil, fmt.Errorf("Empty application public key, in query result line: %s", r)
+		}
+
+		if intervalUsage[ts] == nil {
+			intervalUsage[ts] = make(map[string]api.RelayCounts)
+		}
+		intervalUsage[ts][app] = intervalUsage[ts][app].Add(api.ChainRelayCounts(items[2], counts))
+	}
+	// Rows.Err will report the last error encountered by Rows.Scan.
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return intervalUsage, nil
+}
+
+// parseRelayCounts parses the relay count columns, i.e. COUNT_COLUMNS, of a query result line
+func parseRelayCounts(items []string, line string) (api.RelayCounts, error) {
+	if len(items) != COUNT_COLUMNS_SIZE {
+		return api.RelayCounts{}, fmt.Errorf("Invalid format in query output: %s", line)
+	}
+
+	var values [COUNT_COLUMNS_SIZE]int64
+	for i, item := range items {
+		value, err := strconv.ParseInt(item, 10, 64) // bitsize 64 for int64 return
+		if err != nil {
+			return api.RelayCounts{}, fmt.Errorf("Invalid total relays format: %s in query result line: %s, error: %v", item, line, err)
+		}
+		values[i] = value
+	}
+
+	return api.RelayCounts{
+		Success:     values[0],
+		Failure:     values[1],
+		ClientError: values[2],
+		ServerError: values[3],
+		Timeout:     values[4],
+		Other:       values[5],
+	}, nil
+}
+
+// chainRows returns the counts to store, one row per chain: counts with no breakdown by chain are stored under the empty chain ID
+func chainRows(counts api.RelayCounts) map[string]api.RelayCounts {
+	if len(counts.Chains) == 0 {
+		return map[string]api.RelayCounts{"": counts}
+	}
+	return counts.Chains
+}
+
+// relayCountValues returns the values for the relay count columns, i.e. COUNT_COLUMNS
+func relayCountValues(counts api.RelayCounts) []any {
+	return []any{counts.Success, counts.Failure, counts.ClientError, counts.ServerError, counts.Timeout, counts.Other}
+}
+
 func parseDate(source string) (time.Time, error) {
 	// Postgres queries date output format: 2022-05-31T00:00:00Z
 	const layout = "2006-01-02T15:04:00Z"
 	return time.Parse(layout, source)
 }
+
+// AppQuota returns the relay limits of the application: a nil quota is returned if the application has no entry in the quotas table.
+func (p *pgClient) AppQuota(app string) (*api.Quota, error) {
+	return p.quota(fmt.Sprintf("SELECT daily_limit, monthly_limit FROM %s WHERE application = $1", TABLE_APP_QUOTAS), app)
+}
+
+// UserQuota returns the relay limits of the user: a nil quota is returned if the user has no entry in the quotas table.
+func (p *pgClient) UserQuota(user string) (*api.Quota, error) {
+	return p.quota(fmt.Sprintf("SELECT daily_limit, monthly_limit FROM %s WHERE user_id = $1", TABLE_USER_QUOTAS), user)
+}
+
+func (p *pgClient) quota(query string, key string) (*api.Quota, error) {
+	ctx := context.Background()
+	var quota api.Quota
+	err := p.DB.QueryRowContext(ctx, query, key).Scan(&quota.DailyLimit, &quota.MonthlyLimit)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &quota, nil
+}
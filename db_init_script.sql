@@ -1,6 +1,10 @@
 DROP TABLE IF EXISTS relay_counts;
 DROP TABLE IF EXISTS daily_app_sums;
 DROP TABLE IF EXISTS todays_app_sums;
+DROP TABLE IF EXISTS todays_app_intervals;
+DROP TABLE IF EXISTS daily_app_latency;
+DROP TABLE IF EXISTS app_quotas;
+DROP TABLE IF EXISTS user_quotas;
 CREATE TABLE relay_counts (
   id INT GENERATED ALWAYS AS IDENTITY,
   application VARCHAR NOT NULL,
@@ -10,11 +14,55 @@ CREATE TABLE relay_counts (
 CREATE TABLE daily_app_sums (
   id INT GENERATED ALWAYS AS IDENTITY,
   application VARCHAR NOT NULL,
-  count bigint NOT NULL,
+  chain VARCHAR NOT NULL DEFAULT '',
+  count_success bigint NOT NULL,
+  count_failure bigint NOT NULL,
+  count_client_error bigint NOT NULL DEFAULT 0,
+  count_server_error bigint NOT NULL DEFAULT 0,
+  count_timeout bigint NOT NULL DEFAULT 0,
+  count_other bigint NOT NULL DEFAULT 0,
   time TIMESTAMPTZ
 );
 CREATE TABLE todays_app_sums (
   id INT GENERATED ALWAYS AS IDENTITY,
   application VARCHAR NOT NULL,
-  count bigint NOT NULL
-);
\ No newline at end of file
+  chain VARCHAR NOT NULL DEFAULT '',
+  count_success bigint NOT NULL,
+  count_failure bigint NOT NULL,
+  count_client_error bigint NOT NULL DEFAULT 0,
+  count_server_error bigint NOT NULL DEFAULT 0,
+  count_timeout bigint NOT NULL DEFAULT 0,
+  count_other bigint NOT NULL DEFAULT 0
+);
+CREATE TABLE todays_app_intervals (
+  id INT GENERATED ALWAYS AS IDENTITY,
+  application VARCHAR NOT NULL,
+  chain VARCHAR NOT NULL DEFAULT '',
+  count_success bigint NOT NULL,
+  count_failure bigint NOT NULL,
+  count_client_error bigint NOT NULL DEFAULT 0,
+  count_server_error bigint NOT NULL DEFAULT 0,
+  count_timeout bigint NOT NULL DEFAULT 0,
+  count_other bigint NOT NULL DEFAULT 0,
+  time TIMESTAMPTZ NOT NULL
+);
+CREATE TABLE daily_app_latency (
+  application VARCHAR NOT NULL,
+  time TIMESTAMPTZ NOT NULL,
+  relays bigint NOT NULL,
+  average double precision NOT NULL,
+  p50 double precision NOT NULL,
+  p95 double precision NOT NULL,
+  p99 double precision NOT NULL,
+  PRIMARY KEY (application, time)
+);
+CREATE TABLE app_quotas (
+  application VARCHAR PRIMARY KEY,
+  daily_limit bigint NOT NULL DEFAULT 0,
+  monthly_limit bigint NOT NULL DEFAULT 0
+);
+CREATE TABLE user_quotas (
+  user_id VARCHAR PRIMARY KEY,
+  daily_limit bigint NOT NULL DEFAULT 0,
+  monthly_limit bigint NOT NULL DEFAULT 0
+);
@@ -0,0 +1,1335 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        (unknown)
+// source: meter.proto
+
+// The relay meter's gRPC API: its methods map one-to-one onto the api.RelayMeter interface.
+//	The time period of the requests follows the same rules as the HTTP API: unset timestamps select the meter's defaults,
+//	and the period is adjusted to whole days.
+
+package rpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RelayCounts struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Success int64 `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	// Includes all the failure categories below
+	Failure     int64 `protobuf:"varint,2,opt,name=failure,proto3" json:"failure,omitempty"`
+	ClientError int64 `protobuf:"varint,3,opt,name=client_error,json=clientError,proto3" json:"client_error,omitempty"`
+	ServerError int64 `protobuf:"varint,4,opt,name=server_error,json=serverError,proto3" json:"server_error,omitempty"`
+	Timeout     int64 `protobuf:"varint,5,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Other       int64 `protobuf:"varint,6,opt,name=other,proto3" json:"other,omitempty"`
+	// Breakdown by chain, if provided by the source of the metrics
+	Chains map[string]*RelayCounts `protobuf:"bytes,7,rep,name=chains,proto3" json:"chains,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *RelayCounts) Reset() {
+	*x = RelayCounts{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_meter_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RelayCounts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RelayCounts) ProtoMessage() {}
+
+func (x *RelayCounts) ProtoReflect() protoreflect.Message {
+	mi := &file_meter_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RelayCounts.ProtoReflect.Descriptor instead.
+func (*RelayCounts) Descriptor() ([]byte, []int) {
+	return file_meter_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RelayCounts) GetSuccess() int64 {
+	if x != nil {
+		return x.Success
+	}
+	return 0
+}
+
+func (x *RelayCounts) GetFailure() int64 {
+	if x != nil {
+		return x.Failure
+	}
+	return 0
+}
+
+func (x *RelayCounts) GetClientError() int64 {
+	if x != nil {
+		return x.ClientError
+	}
+	return 0
+}
+
+func (x *RelayCounts) GetServerError() int64 {
+	if x != nil {
+		return x.ServerError
+	}
+	return 0
+}
+
+func (x *RelayCounts) GetTimeout() int64 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+func (x *RelayCounts) GetOther() int64 {
+	if x != nil {
+		return x.Other
+	}
+	return 0
+}
+
+func (x *RelayCounts) GetChains() map[string]*RelayCounts {
+	if x != nil {
+		return x.Chains
+	}
+	return nil
+}
+
+// Latency, i.e. elapsed time in seconds, of the relays of past days.
+// The statistics are calculated from downsampled elapsed times, not from individual relays: the percentiles are approximations
+type RelayLatency struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Relays  int64   `protobuf:"varint,1,opt,name=relays,proto3" json:"relays,omitempty"`
+	Average float64 `protobuf:"fixed64,2,opt,name=average,proto3" json:"average,omitempty"`
+	P50     float64 `protobuf:"fixed64,3,opt,name=p50,proto3" json:"p50,omitempty"`
+	P95     float64 `protobuf:"fixed64,4,opt,name=p95,proto3" json:"p95,omitempty"`
+	P99     float64 `protobuf:"fixed64,5,opt,name=p99,proto3" json:"p99,omitempty"`
+}
+
+func (x *RelayLatency) Reset() {
+	*x = RelayLatency{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_meter_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RelayLatency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RelayLatency) ProtoMessage() {}
+
+func (x *RelayLatency) ProtoReflect() protoreflect.Message {
+	mi := &file_meter_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RelayLatency.ProtoReflect.Descriptor instead.
+func (*RelayLatency) Descriptor() ([]byte, []int) {
+	return file_meter_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RelayLatency) GetRelays() int64 {
+	if x != nil {
+		return x.Relays
+	}
+	return 0
+}
+
+func (x *RelayLatency) GetAverage() float64 {
+	if x != nil {
+		return x.Average
+	}
+	return 0
+}
+
+func (x *RelayLatency) GetP50() float64 {
+	if x != nil {
+		return x.P50
+	}
+	return 0
+}
+
+func (x *RelayLatency) GetP95() float64 {
+	if x != nil {
+		return x.P95
+	}
+	return 0
+}
+
+func (x *RelayLatency) GetP99() float64 {
+	if x != nil {
+		return x.P99
+	}
+	return 0
+}
+
+type AppRelaysRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Application string                 `protobuf:"bytes,1,opt,name=application,proto3" json:"application,omitempty"`
+	From        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+}
+
+func (x *AppRelaysRequest) Reset() {
+	*x = AppRelaysRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_meter_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AppRelaysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppRelaysRequest) ProtoMessage() {}
+
+func (x *AppRelaysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meter_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppRelaysRequest.ProtoReflect.Descriptor instead.
+func (*AppRelaysRequest) Descriptor() ([]byte, []int) {
+	return file_meter_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AppRelaysRequest) GetApplication() string {
+	if x != nil {
+		return x.Application
+	}
+	return ""
+}
+
+func (x *AppRelaysRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *AppRelaysRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type AllAppsRelaysRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+}
+
+func (x *AllAppsRelaysRequest) Reset() {
+	*x = AllAppsRelaysRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_meter_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AllAppsRelaysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllAppsRelaysRequest) ProtoMessage() {}
+
+func (x *AllAppsRelaysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meter_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllAppsRelaysRequest.ProtoReflect.Descriptor instead.
+func (*AllAppsRelaysRequest) Descriptor() ([]byte, []int) {
+	return file_meter_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AllAppsRelaysRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *AllAppsRelaysRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type AppRelaysResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count *RelayCounts `protobuf:"bytes,1,opt,name=count,proto3" json:"count,omitempty"`
+	// Only set if latency statistics are available
+	Latency     *RelayLatency          `protobuf:"bytes,2,opt,name=latency,proto3" json:"latency,omitempty"`
+	From        *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To          *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	Application string                 `protobuf:"bytes,5,opt,name=application,proto3" json:"application,omitempty"`
+	// Notes explain the changes made to the requested time period, e.g. a 'from' older than the oldest day with metrics
+	Notes []string `protobuf:"bytes,6,rep,name=notes,proto3" json:"notes,omitempty"`
+}
+
+func (x *AppRelaysResponse) Reset() {
+	*x = AppRelaysResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_meter_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AppRelaysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppRelaysResponse) ProtoMessage() {}
+
+func (x *AppRelaysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_meter_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppRelaysResponse.ProtoReflect.Descriptor instead.
+func (*AppRelaysResponse) Descriptor() ([]byte, []int) {
+	return file_meter_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AppRelaysResponse) GetCount() *RelayCounts {
+	if x != nil {
+		return x.Count
+	}
+	return nil
+}
+
+func (x *AppRelaysResponse) GetLatency() *RelayLatency {
+	if x != nil {
+		return x.Latency
+	}
+	return nil
+}
+
+func (x *AppRelaysResponse) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *AppRelaysResponse) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *AppRelaysResponse) GetApplication() string {
+	if x != nil {
+		return x.Application
+	}
+	return ""
+}
+
+func (x *AppRelaysResponse) GetNotes() []string {
+	if x != nil {
+		return x.Notes
+	}
+	return nil
+}
+
+type UserRelaysRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User string                 `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	From *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+}
+
+func (x *UserRelaysRequest) Reset() {
+	*x = UserRelaysRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_meter_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UserRelaysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserRelaysRequest) ProtoMessage() {}
+
+func (x *UserRelaysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meter_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserRelaysRequest.ProtoReflect.Descriptor instead.
+func (*UserRelaysRequest) Descriptor() ([]byte, []int) {
+	return file_meter_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UserRelaysRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *UserRelaysRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *UserRelaysRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type UserRelaysResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count        *RelayCounts           `protobuf:"bytes,1,opt,name=count,proto3" json:"count,omitempty"`
+	From         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To           *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	User         string                 `protobuf:"bytes,4,opt,name=user,proto3" json:"user,omitempty"`
+	Applications []string               `protobuf:"bytes,5,rep,name=applications,proto3" json:"applications,omitempty"`
+	// Notes explain the changes made to the requested time period, e.g. a 'from' older than the oldest day with metrics
+	Notes []string `protobuf:"bytes,6,rep,name=notes,proto3" json:"notes,omitempty"`
+}
+
+func (x *UserRelaysResponse) Reset() {
+	*x = UserRelaysResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_meter_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UserRelaysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserRelaysResponse) ProtoMessage() {}
+
+func (x *UserRelaysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_meter_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserRelaysResponse.ProtoReflect.Descriptor instead.
+func (*UserRelaysResponse) Descriptor() ([]byte, []int) {
+	return file_meter_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UserRelaysResponse) GetCount() *RelayCounts {
+	if x != nil {
+		return x.Count
+	}
+	return nil
+}
+
+func (x *UserRelaysResponse) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *UserRelaysResponse) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *UserRelaysResponse) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *UserRelaysResponse) GetApplications() []string {
+	if x != nil {
+		return x.Applications
+	}
+	return nil
+}
+
+func (x *UserRelaysResponse) GetNotes() []string {
+	if x != nil {
+		return x.Notes
+	}
+	return nil
+}
+
+type TotalRelaysRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+}
+
+func (x *TotalRelaysRequest) Reset() {
+	*x = TotalRelaysRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_meter_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TotalRelaysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TotalRelaysRequest) ProtoMessage() {}
+
+func (x *TotalRelaysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meter_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TotalRelaysRequest.ProtoReflect.Descriptor instead.
+func (*TotalRelaysRequest) Descriptor() ([]byte, []int) {
+	return file_meter_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TotalRelaysRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *TotalRelaysRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type TotalRelaysResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count *RelayCounts           `protobuf:"bytes,1,opt,name=count,proto3" json:"count,omitempty"`
+	From  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	// Notes explain the changes made to the requested time period, e.g. a 'from' older than the oldest day with metrics
+	Notes []string `protobuf:"bytes,4,rep,name=notes,proto3" json:"notes,omitempty"`
+}
+
+func (x *TotalRelaysResponse) Reset() {
+	*x = TotalRelaysResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_meter_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TotalRelaysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TotalRelaysResponse) ProtoMessage() {}
+
+func (x *TotalRelaysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_meter_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TotalRelaysResponse.ProtoReflect.Descriptor instead.
+func (*TotalRelaysResponse) Descriptor() ([]byte, []int) {
+	return file_meter_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TotalRelaysResponse) GetCount() *RelayCounts {
+	if x != nil {
+		return x.Count
+	}
+	return nil
+}
+
+func (x *TotalRelaysResponse) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *TotalRelaysResponse) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *TotalRelaysResponse) GetNotes() []string {
+	if x != nil {
+		return x.Notes
+	}
+	return nil
+}
+
+type LoadBalancerRelaysRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Endpoint string                 `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	From     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+}
+
+func (x *LoadBalancerRelaysRequest) Reset() {
+	*x = LoadBalancerRelaysRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_meter_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoadBalancerRelaysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadBalancerRelaysRequest) ProtoMessage() {}
+
+func (x *LoadBalancerRelaysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meter_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadBalancerRelaysRequest.ProtoReflect.Descriptor instead.
+func (*LoadBalancerRelaysRequest) Descriptor() ([]byte, []int) {
+	return file_meter_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *LoadBalancerRelaysRequest) GetEndpoint() string {
+	if x != nil {
+		return x.Endpoint
+	}
+	return ""
+}
+
+func (x *LoadBalancerRelaysRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *LoadBalancerRelaysRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type AllLoadBalancersRelaysRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+}
+
+func (x *AllLoadBalancersRelaysRequest) Reset() {
+	*x = AllLoadBalancersRelaysRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_meter_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AllLoadBalancersRelaysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllLoadBalancersRelaysRequest) ProtoMessage() {}
+
+func (x *AllLoadBalancersRelaysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meter_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllLoadBalancersRelaysRequest.ProtoReflect.Descriptor instead.
+func (*AllLoadBalancersRelaysRequest) Descriptor() ([]byte, []int) {
+	return file_meter_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *AllLoadBalancersRelaysRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *AllLoadBalancersRelaysRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type LoadBalancerRelaysResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count *RelayCounts `protobuf:"bytes,1,opt,name=count,proto3" json:"count,omitempty"`
+	// Only set if latency statistics are available
+	Latency      *RelayLatency          `protobuf:"bytes,2,opt,name=latency,proto3" json:"latency,omitempty"`
+	From         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To           *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	Endpoint     string                 `protobuf:"bytes,5,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	Applications []string               `protobuf:"bytes,6,rep,name=applications,proto3" json:"applications,omitempty"`
+	// Notes explain the changes made to the requested time period, e.g. a 'from' older than the oldest day with metrics
+	Notes []string `protobuf:"bytes,7,rep,name=notes,proto3" json:"notes,omitempty"`
+}
+
+func (x *LoadBalancerRelaysResponse) Reset() {
+	*x = LoadBalancerRelaysResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_meter_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoadBalancerRelaysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadBalancerRelaysResponse) ProtoMessage() {}
+
+func (x *LoadBalancerRelaysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_meter_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadBalancerRelaysResponse.ProtoReflect.Descriptor instead.
+func (*LoadBalancerRelaysResponse) Descriptor() ([]byte, []int) {
+	return file_meter_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *LoadBalancerRelaysResponse) GetCount() *RelayCounts {
+	if x != nil {
+		return x.Count
+	}
+	return nil
+}
+
+func (x *LoadBalancerRelaysResponse) GetLatency() *RelayLatency {
+	if x != nil {
+		return x.Latency
+	}
+	return nil
+}
+
+func (x *LoadBalancerRelaysResponse) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *LoadBalancerRelaysResponse) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *LoadBalancerRelaysResponse) GetEndpoint() string {
+	if x != nil {
+		return x.Endpoint
+	}
+	return ""
+}
+
+func (x *LoadBalancerRelaysResponse) GetApplications() []string {
+	if x != nil {
+		return x.Applications
+	}
+	return nil
+}
+
+func (x *LoadBalancerRelaysResponse) GetNotes() []string {
+	if x != nil {
+		return x.Notes
+	}
+	return nil
+}
+
+var File_meter_proto protoreflect.FileDescriptor
+
+var file_meter_proto_rawDesc = []byte{
+	0x0a, 0x0b, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x6d,
+	0x65, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x30, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xc4, 0x02, 0x0a, 0x0b, 0x52, 0x65, 0x6c,
+	0x61, 0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63,
+	0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65,
+	0x73, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x07, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x12, 0x21, 0x0a, 0x0c,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0b, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12,
+	0x21, 0x0a, 0x0c, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x45, 0x72, 0x72,
+	0x6f, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x14, 0x0a, 0x05,
+	0x6f, 0x74, 0x68, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6f, 0x74, 0x68,
+	0x65, 0x72, 0x12, 0x39, 0x0a, 0x06, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x73, 0x18, 0x07, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x21, 0x2e, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x30, 0x2e, 0x52, 0x65,
+	0x6c, 0x61, 0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x2e, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x73, 0x1a, 0x50, 0x0a,
+	0x0b, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2b,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x6d, 0x65, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x30, 0x2e, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x73, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
+	0x76, 0x0a, 0x0c, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x12,
+	0x16, 0x0a, 0x06, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x06, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x76, 0x65, 0x72, 0x61,
+	0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67,
+	0x65, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x35, 0x30, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03,
+	0x70, 0x35, 0x30, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x39, 0x35, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x03, 0x70, 0x39, 0x35, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x39, 0x39, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x03, 0x70, 0x39, 0x39, 0x22, 0x90, 0x01, 0x0a, 0x10, 0x41, 0x70, 0x70, 0x52,
+	0x65, 0x6c, 0x61, 0x79, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b,
+	0x61, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2e,
+	0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x2a,
+	0x0a, 0x02, 0x74, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x74, 0x6f, 0x22, 0x72, 0x0a, 0x14, 0x41, 0x6c,
+	0x6c, 0x41, 0x70, 0x70, 0x73, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x66, 0x72,
+	0x6f, 0x6d, 0x12, 0x2a, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x74, 0x6f, 0x22, 0x86,
+	0x02, 0x0a, 0x11, 0x41, 0x70, 0x70, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x30, 0x2e, 0x52,
+	0x65, 0x6c, 0x61, 0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x30, 0x0a, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x30, 0x2e, 0x52, 0x65,
+	0x6c, 0x61, 0x79, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x52, 0x07, 0x6c, 0x61, 0x74, 0x65,
+	0x6e, 0x63, 0x79, 0x12, 0x2e, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x66,
+	0x72, 0x6f, 0x6d, 0x12, 0x2a, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x74, 0x6f, 0x12,
+	0x20, 0x0a, 0x0b, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x22, 0x83, 0x01, 0x0a, 0x11, 0x55, 0x73, 0x65, 0x72,
+	0x52, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65,
+	0x72, 0x12, 0x2e, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x66, 0x72, 0x6f,
+	0x6d, 0x12, 0x2a, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x74, 0x6f, 0x22, 0xeb, 0x01,
+	0x0a, 0x12, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x30, 0x2e, 0x52,
+	0x65, 0x6c, 0x61, 0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x2e, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x66, 0x72, 0x6f,
+	0x6d, 0x12, 0x2a, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x12, 0x0a,
+	0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65,
+	0x72, 0x12, 0x22, 0x0a, 0x0c, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x06,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x22, 0x70, 0x0a, 0x12, 0x54,
+	0x6f, 0x74, 0x61, 0x6c, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x2e, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x66, 0x72, 0x6f,
+	0x6d, 0x12, 0x2a, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x74, 0x6f, 0x22, 0xb4, 0x01,
+	0x0a, 0x13, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x30, 0x2e,
+	0x52, 0x65, 0x6c, 0x61, 0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x52, 0x05, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x66, 0x72,
+	0x6f, 0x6d, 0x12, 0x2a, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x14,
+	0x0a, 0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x6e,
+	0x6f, 0x74, 0x65, 0x73, 0x22, 0x93, 0x01, 0x0a, 0x19, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c,
+	0x61, 0x6e, 0x63, 0x65, 0x72, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x2e,
+	0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x2a,
+	0x0a, 0x02, 0x74, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x74, 0x6f, 0x22, 0x7b, 0x0a, 0x1d, 0x41, 0x6c,
+	0x6c, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x73, 0x52, 0x65,
+	0x6c, 0x61, 0x79, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x66,
+	0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x2a, 0x0a, 0x02, 0x74,
+	0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x02, 0x74, 0x6f, 0x22, 0xad, 0x02, 0x0a, 0x1a, 0x4c, 0x6f, 0x61, 0x64,
+	0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x30,
+	0x2e, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x52, 0x05, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x12, 0x30, 0x0a, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x30, 0x2e,
+	0x52, 0x65, 0x6c, 0x61, 0x79, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x52, 0x07, 0x6c, 0x61,
+	0x74, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x2e, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x2a, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x74,
+	0x6f, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x22, 0x0a,
+	0x0c, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x06, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x0c, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x32, 0x83, 0x04, 0x0a, 0x0a, 0x52, 0x65, 0x6c, 0x61,
+	0x79, 0x4d, 0x65, 0x74, 0x65, 0x72, 0x12, 0x44, 0x0a, 0x09, 0x41, 0x70, 0x70, 0x52, 0x65, 0x6c,
+	0x61, 0x79, 0x73, 0x12, 0x1a, 0x2e, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x30, 0x2e, 0x41,
+	0x70, 0x70, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1b, 0x2e, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x30, 0x2e, 0x41, 0x70, 0x70, 0x52, 0x65,
+	0x6c, 0x61, 0x79, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4e, 0x0a, 0x0d,
+	0x41, 0x6c, 0x6c, 0x41, 0x70, 0x70, 0x73, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x12, 0x1e, 0x2e,
+	0x6d, 0x65, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x30, 0x2e, 0x41, 0x6c, 0x6c, 0x41, 0x70, 0x70, 0x73,
+	0x52, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e,
+	0x6d, 0x65, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x30, 0x2e, 0x41, 0x70, 0x70, 0x52, 0x65, 0x6c, 0x61,
+	0x79, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x47, 0x0a, 0x0a,
+	0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x12, 0x1b, 0x2e, 0x6d, 0x65, 0x74,
+	0x65, 0x72, 0x2e, 0x76, 0x30, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x2e,
+	0x76, 0x30, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4a, 0x0a, 0x0b, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x52, 0x65,
+	0x6c, 0x61, 0x79, 0x73, 0x12, 0x1c, 0x2e, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x30, 0x2e,
+	0x54, 0x6f, 0x74, 0x61, 0x6c, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x30, 0x2e, 0x54, 0x6f,
+	0x74, 0x61, 0x6c, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x5f, 0x0a, 0x12, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65,
+	0x72, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x12, 0x23, 0x2e, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x2e,
+	0x76, 0x30, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x52,
+	0x65, 0x6c, 0x61, 0x79, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x6d,
+	0x65, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x30, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61,
+	0x6e, 0x63, 0x65, 0x72, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x69, 0x0a, 0x16, 0x41, 0x6c, 0x6c, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c,
+	0x61, 0x6e, 0x63, 0x65, 0x72, 0x73, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x12, 0x27, 0x2e, 0x6d,
+	0x65, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x30, 0x2e, 0x41, 0x6c, 0x6c, 0x4c, 0x6f, 0x61, 0x64, 0x42,
+	0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x73, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x30,
+	0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x52, 0x65, 0x6c,
+	0x61, 0x79, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x42, 0x1d, 0x5a,
+	0x1b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x64, 0x73, 0x68,
+	0x6d, 0x68, 0x2f, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x2f, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_meter_proto_rawDescOnce sync.Once
+	file_meter_proto_rawDescData = file_meter_proto_rawDesc
+)
+
+func file_meter_proto_rawDescGZIP() []byte {
+	file_meter_proto_rawDescOnce.Do(func() {
+		file_meter_proto_rawDescData = protoimpl.X.CompressGZIP(file_meter_proto_rawDescData)
+	})
+	return file_meter_proto_rawDescData
+}
+
+var file_meter_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_meter_proto_goTypes = []interface{}{
+	(*RelayCounts)(nil),                   // 0: meter.v0.RelayCounts
+	(*RelayLatency)(nil),                  // 1: meter.v0.RelayLatency
+	(*AppRelaysRequest)(nil),              // 2: meter.v0.AppRelaysRequest
+	(*AllAppsRelaysRequest)(nil),          // 3: meter.v0.AllAppsRelaysRequest
+	(*AppRelaysResponse)(nil),             // 4: meter.v0.AppRelaysResponse
+	(*UserRelaysRequest)(nil),             // 5: meter.v0.UserRelaysRequest
+	(*UserRelaysResponse)(nil),            // 6: meter.v0.UserRelaysResponse
+	(*TotalRelaysRequest)(nil),            // 7: meter.v0.TotalRelaysRequest
+	(*TotalRelaysResponse)(nil),           // 8: meter.v0.TotalRelaysResponse
+	(*LoadBalancerRelaysRequest)(nil),     // 9: meter.v0.LoadBalancerRelaysRequest
+	(*AllLoadBalancersRelaysRequest)(nil), // 10: meter.v0.AllLoadBalancersRelaysRequest
+	(*LoadBalancerRelaysResponse)(nil),    // 11: meter.v0.LoadBalancerRelaysResponse
+	nil,                                   // 12: meter.v0.RelayCounts.ChainsEntry
+	(*timestamppb.Timestamp)(nil),         // 13: google.protobuf.Timestamp
+}
+var file_meter_proto_depIdxs = []int32{
+	12, // 0: meter.v0.RelayCounts.chains:type_name -> meter.v0.RelayCounts.ChainsEntry
+	13, // 1: meter.v0.AppRelaysRequest.from:type_name -> google.protobuf.Timestamp
+	13, // 2: meter.v0.AppRelaysRequest.to:type_name -> google.protobuf.Timestamp
+	13, // 3: meter.v0.AllAppsRelaysRequest.from:type_name -> google.protobuf.Timestamp
+	13, // 4: meter.v0.AllAppsRelaysRequest.to:type_name -> google.protobuf.Timestamp
+	0,  // 5: meter.v0.AppRelaysResponse.count:type_name -> meter.v0.RelayCounts
+	1,  // 6: meter.v0.AppRelaysResponse.latency:type_name -> meter.v0.RelayLatency
+	13, // 7: meter.v0.AppRelaysResponse.from:type_name -> google.protobuf.Timestamp
+	13, // 8: meter.v0.AppRelaysResponse.to:type_name -> google.protobuf.Timestamp
+	13, // 9: meter.v0.UserRelaysRequest.from:type_name -> google.protobuf.Timestamp
+	13, // 10: meter.v0.UserRelaysRequest.to:type_name -> google.protobuf.Timestamp
+	0,  // 11: meter.v0.UserRelaysResponse.count:type_name -> meter.v0.RelayCounts
+	13, // 12: meter.v0.UserRelaysResponse.from:type_name -> google.protobuf.Timestamp
+	13, // 13: meter.v0.UserRelaysResponse.to:type_name -> google.protobuf.Timestamp
+	13, // 14: meter.v0.TotalRelaysRequest.from:type_name -> google.protobuf.Timestamp
+	13, // 15: meter.v0.TotalRelaysRequest.to:type_name -> google.protobuf.Timestamp
+	0,  // 16: meter.v0.TotalRelaysResponse.count:type_name -> meter.v0.RelayCounts
+	13, // 17: meter.v0.TotalRelaysResponse.from:type_name -> google.protobuf.Timestamp
+	13, // 18: meter.v0.TotalRelaysResponse.to:type_name -> google.protobuf.Timestamp
+	13, // 19: meter.v0.LoadBalancerRelaysRequest.from:type_name -> google.protobuf.Timestamp
+	13, // 20: meter.v0.LoadBalancerRelaysRequest.to:type_name -> google.protobuf.Timestamp
+	13, // 21: meter.v0.AllLoadBalancersRelaysRequest.from:type_name -> google.protobuf.Timestamp
+	13, // 22: meter.v0.AllLoadBalancersRelaysRequest.to:type_name -> google.protobuf.Timestamp
+	0,  // 23: meter.v0.LoadBalancerRelaysResponse.count:type_name -> meter.v0.RelayCounts
+	1,  // 24: meter.v0.LoadBalancerRelaysResponse.latency:type_name -> meter.v0.RelayLatency
+	13, // 25: meter.v0.LoadBalancerRelaysResponse.from:type_name -> google.protobuf.Timestamp
+	13, // 26: meter.v0.LoadBalancerRelaysResponse.to:type_name -> google.protobuf.Timestamp
+	0,  // 27: meter.v0.RelayCounts.ChainsEntry.value:type_name -> meter.v0.RelayCounts
+	2,  // 28: meter.v0.RelayMeter.AppRelays:input_type -> meter.v0.AppRelaysRequest
+	3,  // 29: meter.v0.RelayMeter.AllAppsRelays:input_type -> meter.v0.AllAppsRelaysRequest
+	5,  // 30: meter.v0.RelayMeter.UserRelays:input_type -> meter.v0.UserRelaysRequest
+	7,  // 31: meter.v0.RelayMeter.TotalRelays:input_type -> meter.v0.TotalRelaysRequest
+	9,  // 32: meter.v0.RelayMeter.LoadBalancerRelays:input_type -> meter.v0.LoadBalancerRelaysRequest
+	10, // 33: meter.v0.RelayMeter.AllLoadBalancersRelays:input_type -> meter.v0.AllLoadBalancersRelaysRequest
+	4,  // 34: meter.v0.RelayMeter.AppRelays:output_type -> meter.v0.AppRelaysResponse
+	4,  // 35: meter.v0.RelayMeter.AllAppsRelays:output_type -> meter.v0.AppRelaysResponse
+	6,  // 36: meter.v0.RelayMeter.UserRelays:output_type -> meter.v0.UserRelaysResponse
+	8,  // 37: meter.v0.RelayMeter.TotalRelays:output_type -> meter.v0.TotalRelaysResponse
+	11, // 38: meter.v0.RelayMeter.LoadBalancerRelays:output_type -> meter.v0.LoadBalancerRelaysResponse
+	11, // 39: meter.v0.RelayMeter.AllLoadBalancersRelays:output_type -> meter.v0.LoadBalancerRelaysResponse
+	34, // [34:40] is the sub-list for method output_type
+	28, // [28:34] is the sub-list for method input_type
+	28, // [28:28] is the sub-list for extension type_name
+	28, // [28:28] is the sub-list for extension extendee
+	0,  // [0:28] is the sub-list for field type_name
+}
+
+func init() { file_meter_proto_init() }
+func file_meter_proto_init() {
+	if File_meter_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_meter_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RelayCounts); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_meter_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RelayLatency); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_meter_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AppRelaysRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_meter_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AllAppsRelaysRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_meter_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AppRelaysResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_meter_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserRelaysRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_meter_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserRelaysResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_meter_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TotalRelaysRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_meter_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TotalRelaysResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_meter_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancerRelaysRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_meter_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AllLoadBalancersRelaysRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_meter_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancerRelaysResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_meter_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_meter_proto_goTypes,
+		DependencyIndexes: file_meter_proto_depIdxs,
+		MessageInfos:      file_meter_proto_msgTypes,
+	}.Build()
+	File_meter_proto = out.File
+	file_meter_proto_rawDesc = nil
+	file_meter_proto_goTypes = nil
+	file_meter_proto_depIdxs = nil
+}
@@ -0,0 +1,348 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: meter.proto
+
+package rpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// RelayMeterClient is the client API for RelayMeter service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RelayMeterClient interface {
+	AppRelays(ctx context.Context, in *AppRelaysRequest, opts ...grpc.CallOption) (*AppRelaysResponse, error)
+	// Streams one response per application.
+	//	The responses are computed before the first one is sent: the stream only frames them, one message per application
+	AllAppsRelays(ctx context.Context, in *AllAppsRelaysRequest, opts ...grpc.CallOption) (RelayMeter_AllAppsRelaysClient, error)
+	UserRelays(ctx context.Context, in *UserRelaysRequest, opts ...grpc.CallOption) (*UserRelaysResponse, error)
+	TotalRelays(ctx context.Context, in *TotalRelaysRequest, opts ...grpc.CallOption) (*TotalRelaysResponse, error)
+	LoadBalancerRelays(ctx context.Context, in *LoadBalancerRelaysRequest, opts ...grpc.CallOption) (*LoadBalancerRelaysResponse, error)
+	// Streams one response per load balancer.
+	//	The responses are computed before the first one is sent: the stream only frames them, one message per load balancer
+	AllLoadBalancersRelays(ctx context.Context, in *AllLoadBalancersRelaysRequest, opts ...grpc.CallOption) (RelayMeter_AllLoadBalancersRelaysClient, error)
+}
+
+type relayMeterClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRelayMeterClient(cc grpc.ClientConnInterface) RelayMeterClient {
+	return &relayMeterClient{cc}
+}
+
+func (c *relayMeterClient) AppRelays(ctx context.Context, in *AppRelaysRequest, opts ...grpc.CallOption) (*AppRelaysResponse, error) {
+	out := new(AppRelaysResponse)
+	err := c.cc.Invoke(ctx, "/meter.v0.RelayMeter/AppRelays", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *relayMeterClient) AllAppsRelays(ctx context.Context, in *AllAppsRelaysRequest, opts ...grpc.CallOption) (RelayMeter_AllAppsRelaysClient, error) {
+	stream, err := c.cc.NewStream(ctx, &RelayMeter_ServiceDesc.Streams[0], "/meter.v0.RelayMeter/AllAppsRelays", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &relayMeterAllAppsRelaysClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type RelayMeter_AllAppsRelaysClient interface {
+	Recv() (*AppRelaysResponse, error)
+	grpc.ClientStream
+}
+
+type relayMeterAllAppsRelaysClient struct {
+	grpc.ClientStream
+}
+
+func (x *relayMeterAllAppsRelaysClient) Recv() (*AppRelaysResponse, error) {
+	m := new(AppRelaysResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *relayMeterClient) UserRelays(ctx context.Context, in *UserRelaysRequest, opts ...grpc.CallOption) (*UserRelaysResponse, error) {
+	out := new(UserRelaysResponse)
+	err := c.cc.Invoke(ctx, "/meter.v0.RelayMeter/UserRelays", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *relayMeterClient) TotalRelays(ctx context.Context, in *TotalRelaysRequest, opts ...grpc.CallOption) (*TotalRelaysResponse, error) {
+	out := new(TotalRelaysResponse)
+	err := c.cc.Invoke(ctx, "/meter.v0.RelayMeter/TotalRelays", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *relayMeterClient) LoadBalancerRelays(ctx context.Context, in *LoadBalancerRelaysRequest, opts ...grpc.CallOption) (*LoadBalancerRelaysResponse, error) {
+	out := new(LoadBalancerRelaysResponse)
+	err := c.cc.Invoke(ctx, "/meter.v0.RelayMeter/LoadBalancerRelays", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *relayMeterClient) AllLoadBalancersRelays(ctx context.Context, in *AllLoadBalancersRelaysRequest, opts ...grpc.CallOption) (RelayMeter_AllLoadBalancersRelaysClient, error) {
+	stream, err := c.cc.NewStream(ctx, &RelayMeter_ServiceDesc.Streams[1], "/meter.v0.RelayMeter/AllLoadBalancersRelays", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &relayMeterAllLoadBalancersRelaysClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type RelayMeter_AllLoadBalancersRelaysClient interface {
+	Recv() (*LoadBalancerRelaysResponse, error)
+	grpc.ClientStream
+}
+
+type relayMeterAllLoadBalancersRelaysClient struct {
+	grpc.ClientStream
+}
+
+func (x *relayMeterAllLoadBalancersRelaysClient) Recv() (*LoadBalancerRelaysResponse, error) {
+	m := new(LoadBalancerRelaysResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// RelayMeterServer is the server API for RelayMeter service.
+// All implementations must embed UnimplementedRelayMeterServer
+// for forward compatibility
+type RelayMeterServer interface {
+	AppRelays(context.Context, *AppRelaysRequest) (*AppRelaysResponse, error)
+	// Streams one response per application.
+	//	The responses are computed before the first one is sent: the stream only frames them, one message per application
+	AllAppsRelays(*AllAppsRelaysRequest, RelayMeter_AllAppsRelaysServer) error
+	UserRelays(context.Context, *UserRelaysRequest) (*UserRelaysResponse, error)
+	TotalRelays(context.Context, *TotalRelaysRequest) (*TotalRelaysResponse, error)
+	LoadBalancerRelays(context.Context, *LoadBalancerRelaysRequest) (*LoadBalancerRelaysResponse, error)
+	// Streams one response per load balancer.
+	//	The responses are computed before the first one is sent: the stream only frames them, one message per load balancer
+	AllLoadBalancersRelays(*AllLoadBalancersRelaysRequest, RelayMeter_AllLoadBalancersRelaysServer) error
+	mustEmbedUnimplementedRelayMeterServer()
+}
+
+// UnimplementedRelayMeterServer must be embedded to have forward compatible implementations.
+type UnimplementedRelayMeterServer struct {
+}
+
+func (UnimplementedRelayMeterServer) AppRelays(context.Context, *AppRelaysRequest) (*AppRelaysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AppRelays not implemented")
+}
+func (UnimplementedRelayMeterServer) AllAppsRelays(*AllAppsRelaysRequest, RelayMeter_AllAppsRelaysServer) error {
+	return status.Errorf(codes.Unimplemented, "method AllAppsRelays not implemented")
+}
+func (UnimplementedRelayMeterServer) UserRelays(context.Context, *UserRelaysRequest) (*UserRelaysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UserRelays not implemented")
+}
+func (UnimplementedRelayMeterServer) TotalRelays(context.Context, *TotalRelaysRequest) (*TotalRelaysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TotalRelays not implemented")
+}
+func (UnimplementedRelayMeterServer) LoadBalancerRelays(context.Context, *LoadBalancerRelaysRequest) (*LoadBalancerRelaysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoadBalancerRelays not implemented")
+}
+func (UnimplementedRelayMeterServer) AllLoadBalancersRelays(*AllLoadBalancersRelaysRequest, RelayMeter_AllLoadBalancersRelaysServer) error {
+	return status.Errorf(codes.Unimplemented, "method AllLoadBalancersRelays not implemented")
+}
+func (UnimplementedRelayMeterServer) mustEmbedUnimplementedRelayMeterServer() {}
+
+// UnsafeRelayMeterServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RelayMeterServer will
+// result in compilation errors.
+type UnsafeRelayMeterServer interface {
+	mustEmbedUnimplementedRelayMeterServer()
+}
+
+func RegisterRelayMeterServer(s grpc.ServiceRegistrar, srv RelayMeterServer) {
+	s.RegisterService(&RelayMeter_ServiceDesc, srv)
+}
+
+func _RelayMeter_AppRelays_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AppRelaysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RelayMeterServer).AppRelays(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meter.v0.RelayMeter/AppRelays",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RelayMeterServer).AppRelays(ctx, req.(*AppRelaysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RelayMeter_AllAppsRelays_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AllAppsRelaysRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RelayMeterServer).AllAppsRelays(m, &relayMeterAllAppsRelaysServer{stream})
+}
+
+type RelayMeter_AllAppsRelaysServer interface {
+	Send(*AppRelaysResponse) error
+	grpc.ServerStream
+}
+
+type relayMeterAllAppsRelaysServer struct {
+	grpc.ServerStream
+}
+
+func (x *relayMeterAllAppsRelaysServer) Send(m *AppRelaysResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _RelayMeter_UserRelays_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRelaysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RelayMeterServer).UserRelays(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meter.v0.RelayMeter/UserRelays",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RelayMeterServer).UserRelays(ctx, req.(*UserRelaysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RelayMeter_TotalRelays_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TotalRelaysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RelayMeterServer).TotalRelays(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meter.v0.RelayMeter/TotalRelays",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RelayMeterServer).TotalRelays(ctx, req.(*TotalRelaysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RelayMeter_LoadBalancerRelays_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoadBalancerRelaysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RelayMeterServer).LoadBalancerRelays(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meter.v0.RelayMeter/LoadBalancerRelays",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RelayMeterServer).LoadBalancerRelays(ctx, req.(*LoadBalancerRelaysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RelayMeter_AllLoadBalancersRelays_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AllLoadBalancersRelaysRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RelayMeterServer).AllLoadBalancersRelays(m, &relayMeterAllLoadBalancersRelaysServer{stream})
+}
+
+type RelayMeter_AllLoadBalancersRelaysServer interface {
+	Send(*LoadBalancerRelaysResponse) error
+	grpc.ServerStream
+}
+
+type relayMeterAllLoadBalancersRelaysServer struct {
+	grpc.ServerStream
+}
+
+func (x *relayMeterAllLoadBalancersRelaysServer) Send(m *LoadBalancerRelaysResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// RelayMeter_ServiceDesc is the grpc.ServiceDesc for RelayMeter service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RelayMeter_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "meter.v0.RelayMeter",
+	HandlerType: (*RelayMeterServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AppRelays",
+			Handler:    _RelayMeter_AppRelays_Handler,
+		},
+		{
+			MethodName: "UserRelays",
+			Handler:    _RelayMeter_UserRelays_Handler,
+		},
+		{
+			MethodName: "TotalRelays",
+			Handler:    _RelayMeter_TotalRelays_Handler,
+		},
+		{
+			MethodName: "LoadBalancerRelays",
+			Handler:    _RelayMeter_LoadBalancerRelays_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "AllAppsRelays",
+			Handler:       _RelayMeter_AllAppsRelays_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "AllLoadBalancersRelays",
+			Handler:       _RelayMeter_AllLoadBalancersRelays_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "meter.proto",
+}
@@ -0,0 +1,230 @@
+package rpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative meter.proto
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/adshmh/meter/api"
+)
+
+// NewServer returns a gRPC server serving the meter.
+//	If the authenticator is set, only admins are allowed access: credentials are passed as metadata,
+//	using the same keys as the HTTP headers, e.g. authorization: Bearer <token>
+func NewServer(meter api.RelayMeter, authenticator api.Authenticator, l *logger.Logger) *grpc.Server {
+	var opts []grpc.ServerOption
+	if authenticator != nil {
+		opts = append(opts,
+			grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+				if err := authorize(ctx, authenticator, l); err != nil {
+					return nil, err
+				}
+				return handler(ctx, req)
+			}),
+			grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+				if err := authorize(ss.Context(), authenticator, l); err != nil {
+					return err
+				}
+				return handler(srv, ss)
+			}),
+		)
+	}
+
+	s := grpc.NewServer(opts...)
+	RegisterRelayMeterServer(s, &server{meter: meter, l: l})
+	return s
+}
+
+// authorize authenticates the credentials of the call's metadata: only admins are allowed access.
+func authorize(ctx context.Context, authenticator api.Authenticator, l *logger.Logger) error {
+	// The authenticator expects the credentials as HTTP headers
+	req := &http.Request{Header: http.Header{}}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, key := range []string{api.HEADER_AUTHORIZATION, api.HEADER_API_KEY} {
+		if values := md.Get(strings.ToLower(key)); len(values) > 0 {
+			req.Header.Set(key, values[0])
+		}
+	}
+
+	principal, err := authenticator.Authenticate(req)
+	if err != nil {
+		l.WithFields(logger.Fields{"error": err}).Warn("Unauthenticated gRPC call")
+		return status.Error(codes.Unauthenticated, api.ErrUnauthenticated.Error())
+	}
+	if !principal.Admin {
+		l.WithFields(logger.Fields{"user": principal.User}).Warn("Unauthorized gRPC call")
+		return status.Error(codes.PermissionDenied, api.ErrForbidden.Error())
+	}
+	return nil
+}
+
+type server struct {
+	UnimplementedRelayMeterServer
+	meter api.RelayMeter
+	l     *logger.Logger
+}
+
+func (s *server) AppRelays(_ context.Context, req *AppRelaysRequest) (*AppRelaysResponse, error) {
+	resp, err := s.meter.AppRelays(req.GetApplication(), fromTimestamp(req.GetFrom()), fromTimestamp(req.GetTo()))
+	if err != nil {
+		return nil, s.statusError(err)
+	}
+	return appRelaysResponse(resp), nil
+}
+
+// AllAppsRelays sends one message per application: the meter returns all the applications at once,
+//	so the stream only frames the responses, it does not reduce the server's memory use.
+func (s *server) AllAppsRelays(req *AllAppsRelaysRequest, stream RelayMeter_AllAppsRelaysServer) error {
+	resp, err := s.meter.AllAppsRelays(fromTimestamp(req.GetFrom()), fromTimestamp(req.GetTo()))
+	if err != nil {
+		return s.statusError(err)
+	}
+	for _, r := range resp {
+		if err := stream.Send(appRelaysResponse(r)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *server) UserRelays(_ context.Context, req *UserRelaysRequest) (*UserRelaysResponse, error) {
+	resp, err := s.meter.UserRelays(req.GetUser(), fromTimestamp(req.GetFrom()), fromTimestamp(req.GetTo()))
+	if err != nil {
+		return nil, s.statusError(err)
+	}
+	return &UserRelaysResponse{
+		Count:        relayCounts(resp.Count),
+		From:         timestamppb.New(resp.From),
+		To:           timestamppb.New(resp.To),
+		User:         resp.User,
+		Applications: resp.Applications,
+		Notes:        resp.Notes,
+	}, nil
+}
+
+func (s *server) TotalRelays(_ context.Context, req *TotalRelaysRequest) (*TotalRelaysResponse, error) {
+	resp, err := s.meter.TotalRelays(fromTimestamp(req.GetFrom()), fromTimestamp(req.GetTo()))
+	if err != nil {
+		return nil, s.statusError(err)
+	}
+	return &TotalRelaysResponse{
+		Count: relayCounts(resp.Count),
+		From:  timestamppb.New(resp.From),
+		To:    timestamppb.New(resp.To),
+		Notes: resp.Notes,
+	}, nil
+}
+
+func (s *server) LoadBalancerRelays(_ context.Context, req *LoadBalancerRelaysRequest) (*LoadBalancerRelaysResponse, error) {
+	resp, err := s.meter.LoadBalancerRelays(req.GetEndpoint(), fromTimestamp(req.GetFrom()), fromTimestamp(req.GetTo()))
+	if err != nil {
+		return nil, s.statusError(err)
+	}
+	return loadBalancerRelaysResponse(resp), nil
+}
+
+// AllLoadBalancersRelays sends one message per load balancer: as with AllAppsRelays, the stream only frames the responses.
+func (s *server) AllLoadBalancersRelays(req *AllLoadBalancersRelaysRequest, stream RelayMeter_AllLoadBalancersRelaysServer) error {
+	resp, err := s.meter.AllLoadBalancersRelays(fromTimestamp(req.GetFrom()), fromTimestamp(req.GetTo()))
+	if err != nil {
+		return s.statusError(err)
+	}
+	for _, r := range resp {
+		if err := stream.Send(loadBalancerRelaysResponse(r)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// statusError maps the meter's errors to gRPC status codes, following the HTTP API's status codes.
+func (s *server) statusError(err error) error {
+	errLogger := s.l.WithFields(logger.Fields{"error": err})
+	switch {
+	case errors.Is(err, api.InvalidRequest):
+		errLogger.Warn("Invalid request")
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, api.AppNotFound), errors.Is(err, api.ErrLoadBalancerNotFound):
+		errLogger.Warn("Invalid request: not found")
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, api.ErrBackendUnavailable):
+		errLogger.Warn("Backend unavailable")
+		return status.Error(codes.Unavailable, api.ErrBackendUnavailable.Error())
+	default:
+		errLogger.Warn("Internal server error")
+		return status.Error(codes.Internal, "Internal server error")
+	}
+}
+
+// fromTimestamp returns the zero time for unset timestamps, so the meter applies its defaults
+func fromTimestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func relayCounts(counts api.RelayCounts) *RelayCounts {
+	c := &RelayCounts{
+		Success:     counts.Success,
+		Failure:     counts.Failure,
+		ClientError: counts.ClientError,
+		ServerError: counts.ServerError,
+		Timeout:     counts.Timeout,
+		Other:       counts.Other,
+	}
+	if len(counts.Chains) > 0 {
+		c.Chains = make(map[string]*RelayCounts, len(counts.Chains))
+		for chain, chainCounts := range counts.Chains {
+			c.Chains[chain] = relayCounts(chainCounts)
+		}
+	}
+	return c
+}
+
+func relayLatency(latency *api.RelayLatency) *RelayLatency {
+	if latency == nil {
+		return nil
+	}
+	return &RelayLatency{
+		Relays:  latency.Relays,
+		Average: latency.Average,
+		P50:     latency.P50,
+		P95:     latency.P95,
+		P99:     latency.P99,
+	}
+}
+
+func appRelaysResponse(resp api.AppRelaysResponse) *AppRelaysResponse {
+	return &AppRelaysResponse{
+		Count:       relayCounts(resp.Count),
+		Latency:     relayLatency(resp.Latency),
+		From:        timestamppb.New(resp.From),
+		To:          timestamppb.New(resp.To),
+		Application: resp.Application,
+		Notes:       resp.Notes,
+	}
+}
+
+func loadBalancerRelaysResponse(resp api.LoadBalancerRelaysResponse) *LoadBalancerRelaysResponse {
+	return &LoadBalancerRelaysResponse{
+		Count:        relayCounts(resp.Count),
+		Latency:      relayLatency(resp.Latency),
+		From:         timestamppb.New(resp.From),
+		To:           timestamppb.New(resp.To),
+		Endpoint:     resp.Endpoint,
+		Applications: resp.Applications,
+		Notes:        resp.Notes,
+	}
+}
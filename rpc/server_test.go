@@ -0,0 +1,277 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	logger "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/adshmh/meter/api"
+)
+
+func newTestClient(t *testing.T, meter api.RelayMeter, authenticator api.Authenticator) RelayMeterClient {
+	listener := bufconn.Listen(1024 * 1024)
+	s := NewServer(meter, authenticator, logger.New())
+	go s.Serve(listener)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error dialing the server: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewRelayMeterClient(conn)
+}
+
+func TestAppRelays(t *testing.T) {
+	now := time.Now().UTC()
+	fakeMeter := &fakeRelayMeter{
+		appResponse: api.AppRelaysResponse{
+			Count:       api.RelayCounts{Success: 10, Failure: 2, Chains: map[string]api.RelayCounts{"0021": {Success: 10, Failure: 2}}},
+			Latency:     &api.RelayLatency{Relays: 12, Average: 0.5},
+			From:        now,
+			To:          now,
+			Application: "app1",
+			Notes:       []string{"The time period starts on 2022-08-01 instead of 2022-07-01: metrics are only kept for the past 30 days"},
+		},
+	}
+	client := newTestClient(t, fakeMeter, nil)
+
+	from := now.AddDate(0, 0, -1)
+	got, err := client.AppRelays(context.Background(), &AppRelaysRequest{Application: "app1", From: timestamppb.New(from)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := &AppRelaysResponse{
+		Count: &RelayCounts{
+			Success: 10,
+			Failure: 2,
+			Chains:  map[string]*RelayCounts{"0021": {Success: 10, Failure: 2}},
+		},
+		Latency:     &RelayLatency{Relays: 12, Average: 0.5},
+		From:        timestamppb.New(now),
+		To:          timestamppb.New(now),
+		Application: "app1",
+		Notes:       []string{"The time period starts on 2022-08-01 instead of 2022-07-01: metrics are only kept for the past 30 days"},
+	}
+	if diff := cmp.Diff(expected, got, protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected value (-want +got):\n%s", diff)
+	}
+	if fakeMeter.requestedApp != "app1" || !fakeMeter.requestedFrom.Equal(from) || !fakeMeter.requestedTo.IsZero() {
+		t.Errorf("Unexpected meter request: app: %s, from: %v, to: %v", fakeMeter.requestedApp, fakeMeter.requestedFrom, fakeMeter.requestedTo)
+	}
+}
+
+func TestTotalRelaysNotes(t *testing.T) {
+	notes := []string{"The time period ends on 2022-09-01 instead of 2022-09-02: no metrics are available after today"}
+	client := newTestClient(t, &fakeRelayMeter{totalResponse: api.TotalRelaysResponse{Notes: notes}}, nil)
+
+	got, err := client.TotalRelays(context.Background(), &TotalRelaysRequest{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(notes, got.GetNotes()); diff != "" {
+		t.Errorf("unexpected value (-want +got):\n%s", diff)
+	}
+}
+
+func TestAllAppsRelaysStream(t *testing.T) {
+	fakeMeter := &fakeRelayMeter{
+		allAppsResponse: []api.AppRelaysResponse{
+			{Application: "app1", Count: api.RelayCounts{Success: 1}},
+			{Application: "app2", Count: api.RelayCounts{Success: 2}},
+		},
+	}
+	client := newTestClient(t, fakeMeter, nil)
+
+	stream, err := client.AllAppsRelays(context.Background(), &AllAppsRelaysRequest{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var got []string
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error receiving: %v", err)
+		}
+		got = append(got, resp.Application)
+	}
+	if diff := cmp.Diff([]string{"app1", "app2"}, got); diff != "" {
+		t.Errorf("unexpected value (-want +got):\n%s", diff)
+	}
+}
+
+func TestStatusErrors(t *testing.T) {
+	testCases := []struct {
+		name         string
+		err          error
+		expectedCode codes.Code
+	}{
+		{name: "Invalid request", err: api.InvalidRequest, expectedCode: codes.InvalidArgument},
+		{name: "Load balancer not found", err: api.ErrLoadBalancerNotFound, expectedCode: codes.NotFound},
+		{name: "Backend unavailable", err: api.ErrBackendUnavailable, expectedCode: codes.Unavailable},
+		{name: "Internal error", err: io.ErrUnexpectedEOF, expectedCode: codes.Internal},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newTestClient(t, &fakeRelayMeter{err: tc.err}, nil)
+			_, err := client.LoadBalancerRelays(context.Background(), &LoadBalancerRelaysRequest{Endpoint: "lb1"})
+			if got := status.Code(err); got != tc.expectedCode {
+				t.Errorf("Expected code: %v, got: %v", tc.expectedCode, got)
+			}
+		})
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	authenticator, err := api.NewAPIKeyAuthenticator([]api.APIKey{
+		{Key: "user-key", User: "user1"},
+		{Key: "admin-key", Admin: true},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	client := newTestClient(t, &fakeRelayMeter{}, authenticator)
+
+	testCases := []struct {
+		name         string
+		apiKey       string
+		expectedCode codes.Code
+	}{
+		{name: "Admin key", apiKey: "admin-key", expectedCode: codes.OK},
+		{name: "User key", apiKey: "user-key", expectedCode: codes.PermissionDenied},
+		{name: "Invalid key", apiKey: "invalid", expectedCode: codes.Unauthenticated},
+		{name: "Missing key", expectedCode: codes.Unauthenticated},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.apiKey != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", tc.apiKey)
+			}
+
+			_, err := client.TotalRelays(ctx, &TotalRelaysRequest{})
+			if got := status.Code(err); got != tc.expectedCode {
+				t.Errorf("Unary call: expected code: %v, got: %v", tc.expectedCode, got)
+			}
+
+			stream, err := client.AllLoadBalancersRelays(ctx, &AllLoadBalancersRelaysRequest{})
+			if err == nil {
+				_, err = stream.Recv()
+			}
+			if err == io.EOF {
+				err = nil
+			}
+			if got := status.Code(err); got != tc.expectedCode {
+				t.Errorf("Streaming call: expected code: %v, got: %v", tc.expectedCode, got)
+			}
+		})
+	}
+}
+
+type fakeRelayMeter struct {
+	requestedApp  string
+	requestedFrom time.Time
+	requestedTo   time.Time
+
+	appResponse     api.AppRelaysResponse
+	allAppsResponse []api.AppRelaysResponse
+	totalResponse   api.TotalRelaysResponse
+	err             error
+}
+
+func (f *fakeRelayMeter) AppRelays(app string, from, to time.Time) (api.AppRelaysResponse, error) {
+	f.requestedApp = app
+	f.requestedFrom = from
+	f.requestedTo = to
+	return f.appResponse, f.err
+}
+
+func (f *fakeRelayMeter) AppDailyRelays(app string, from, to time.Time) ([]api.AppRelaysResponse, error) {
+	return nil, f.err
+}
+
+func (f *fakeRelayMeter) AppTodaysRelays(app string) ([]api.AppRelaysResponse, error) {
+	return nil, f.err
+}
+
+func (f *fakeRelayMeter) AllAppsRelays(from, to time.Time) ([]api.AppRelaysResponse, error) {
+	return f.allAppsResponse, f.err
+}
+
+func (f *fakeRelayMeter) BatchAppRelays(requests []api.AppRelaysRequest) ([]api.AppRelaysResponse, error) {
+	return nil, f.err
+}
+
+func (f *fakeRelayMeter) TopAppsRelays(from, to time.Time, n int, by api.RankBy) ([]api.AppRelaysResponse, error) {
+	return nil, f.err
+}
+
+func (f *fakeRelayMeter) UserRelays(user string, from, to time.Time) (api.UserRelaysResponse, error) {
+	return api.UserRelaysResponse{}, f.err
+}
+
+func (f *fakeRelayMeter) TotalRelays(from, to time.Time) (api.TotalRelaysResponse, error) {
+	return f.totalResponse, f.err
+}
+
+func (f *fakeRelayMeter) LoadBalancerRelays(endpoint string, from, to time.Time) (api.LoadBalancerRelaysResponse, error) {
+	return api.LoadBalancerRelaysResponse{}, f.err
+}
+
+func (f *fakeRelayMeter) AllLoadBalancersRelays(from, to time.Time) ([]api.LoadBalancerRelaysResponse, error) {
+	return nil, f.err
+}
+
+func (f *fakeRelayMeter) CompareAppRelays(app string, from, to time.Time) (api.RelaysComparisonResponse, error) {
+	return api.RelaysComparisonResponse{}, f.err
+}
+
+func (f *fakeRelayMeter) CompareUserRelays(user string, from, to time.Time) (api.RelaysComparisonResponse, error) {
+	return api.RelaysComparisonResponse{}, f.err
+}
+
+func (f *fakeRelayMeter) CompareLoadBalancerRelays(endpoint string, from, to time.Time) (api.RelaysComparisonResponse, error) {
+	return api.RelaysComparisonResponse{}, f.err
+}
+
+func (f *fakeRelayMeter) CompareTotalRelays(from, to time.Time) (api.RelaysComparisonResponse, error) {
+	return api.RelaysComparisonResponse{}, f.err
+}
+
+func (f *fakeRelayMeter) Buckets(from, to time.Time, granularity api.Granularity) ([]api.RelaysBucket, error) {
+	return nil, f.err
+}
+
+func (f *fakeRelayMeter) AppQuota(app string) (api.AppQuotaResponse, error) {
+	return api.AppQuotaResponse{}, f.err
+}
+
+func (f *fakeRelayMeter) UserQuota(user string) (api.UserQuotaResponse, error) {
+	return api.UserQuotaResponse{}, f.err
+}
+
+func (f *fakeRelayMeter) LastUpdated() time.Time {
+	return time.Time{}
+}